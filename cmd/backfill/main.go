@@ -0,0 +1,205 @@
+package main
+
+import (
+	"cloudpix/config"
+	"cloudpix/internal/application/thumbnailmanagement/dto"
+	"cloudpix/internal/application/thumbnailmanagement/usecase"
+	imagevalueobject "cloudpix/internal/domain/imagemanagement/valueobject"
+	"cloudpix/internal/domain/shared/event/dispatcher"
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"cloudpix/internal/infrastructure/imaging"
+	"cloudpix/internal/infrastructure/metrics"
+	"cloudpix/internal/infrastructure/persistence/dynamodb/imagemanagement"
+	"cloudpix/internal/infrastructure/persistence/dynamodb/thumbnailmanagement"
+	s3storage "cloudpix/internal/infrastructure/storage/s3"
+	"cloudpix/internal/logging"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// backfillState は中断した位置から再開するための状態
+type backfillState struct {
+	Cursor string `json:"cursor"`
+}
+
+func main() {
+	concurrency := flag.Int("concurrency", usecase.DefaultBackfillConcurrency, "同時に処理する画像数")
+	pageSize := flag.Int("page-size", usecase.DefaultBackfillPageSize, "1回のスキャンで取得する画像数")
+	maxImages := flag.Int("max-images", 0, "走査する画像数の上限（0は無制限）")
+	stateFile := flag.String("state", "", "再開位置を保存するファイル（指定時は前回の位置から再開）")
+	images := flag.String("images", "", "再生成する画像IDのカンマ区切りリスト")
+	force := flag.Bool("force", false, "走査したすべての画像を再生成する")
+	dryRun := flag.Bool("dry-run", false, "対象の判定のみ行い再生成しない")
+	flag.Parse()
+
+	// ロギングの初期化
+	logging.InitLogging()
+	logger := logging.GetLogger("ThumbnailBackfill")
+
+	// 設定の読み込み
+	cfg := config.NewConfig()
+	if cfg.S3BucketName == "" || cfg.MetadataTableName == "" {
+		logger.Fatal(errors.New("missing configuration"), "S3_BUCKET_NAME and METADATA_TABLE_NAME are required", nil)
+	}
+
+	// レンディション定義の読み込み（サムネイル生成関数と同じ設定を使用）
+	renditionSpecs, err := valueobject.ParseRenditionSpecs(cfg.ThumbnailRenditions)
+	if err != nil {
+		logger.Fatal(err, "Invalid thumbnail rendition configuration", nil)
+	}
+	for i, spec := range renditionSpecs {
+		renditionSpecs[i] = spec.WithAnimated(cfg.ThumbnailAnimatedGIF)
+	}
+
+	// 再開位置の読み込み
+	options := dto.BackfillOptions{
+		Concurrency: *concurrency,
+		PageSize:    *pageSize,
+		MaxImages:   *maxImages,
+		Force:       *force,
+		DryRun:      *dryRun,
+	}
+	if *images != "" {
+		for _, imageID := range strings.Split(*images, ",") {
+			if imageID = strings.TrimSpace(imageID); imageID != "" {
+				options.ImageIDs = append(options.ImageIDs, imageID)
+			}
+		}
+	}
+	if *stateFile != "" {
+		state, err := loadState(*stateFile)
+		if err != nil {
+			logger.Fatal(err, "Error loading backfill state", map[string]interface{}{
+				"stateFile": *stateFile,
+			})
+		}
+		options.Cursor = state.Cursor
+		options.OnCheckpoint = func(cursor string) {
+			if err := saveState(*stateFile, backfillState{Cursor: cursor}); err != nil {
+				logger.Error(err, "Error saving backfill state", map[string]interface{}{
+					"stateFile": *stateFile,
+				})
+			}
+		}
+	}
+
+	logger.Info("Starting thumbnail backfill", map[string]interface{}{
+		"bucketName":    cfg.S3BucketName,
+		"metadataTable": cfg.MetadataTableName,
+		"configVersion": valueobject.RenditionConfigVersion(renditionSpecs),
+		"cursor":        options.Cursor,
+		"concurrency":   options.Concurrency,
+		"dryRun":        options.DryRun,
+	})
+
+	// AWS セッションの初期化
+	// リージョン未指定の場合はAWS CLIの共有設定を使用
+	awsConfig := aws.Config{}
+	if cfg.AWSRegion != "" {
+		awsConfig.Region = aws.String(cfg.AWSRegion)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            awsConfig,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		logger.Fatal(err, "Error creating AWS session", nil)
+	}
+
+	// S3とDynamoDBクライアントの初期化
+	s3Client := s3.New(sess)
+	dbClient := dynamodb.New(sess)
+
+	// デコードを許可する画像の大きさの上限
+	imageLimits := imagevalueobject.NewImageLimits(cfg.MaxImagePixels, cfg.MaxImageBytes)
+
+	// インフラストラクチャレイヤーのセットアップ
+	thumbnailRepo := thumbnailmanagement.NewDynamoDBThumbnailRepository(dbClient, cfg.MetadataTableName)
+	similarityRepo := imagemanagement.NewDynamoDBSimilarityRepository(dbClient, cfg.SimilarityTableName)
+	imageRepo := imagemanagement.NewDynamoDBImageRepository(dbClient, cfg.MetadataTableName)
+	watermarkRepo := thumbnailmanagement.NewDynamoDBWatermarkRepository(dbClient, cfg.WatermarkTableName)
+	stateRepo := thumbnailmanagement.NewDynamoDBProcessingStateRepository(dbClient, cfg.MetadataTableName)
+	storageService := s3storage.NewS3ThumbnailStorageService(s3Client, cfg.AWSRegion, imageLimits)
+	processingService := imaging.NewImageProcessingService(imageLimits)
+	var metricsService metrics.MetricsService
+	if cfg.EnableMetrics {
+		metricsService = metrics.NewCloudWatchMetricsService(sess, metrics.DefaultMetricsConfig)
+	}
+	processingMetrics := metrics.NewThumbnailProcessingMetrics(metricsService, "ThumbnailBackfill")
+	eventDispatcher := dispatcher.NewSimpleEventDispatcher()
+
+	// アプリケーションレイヤーのセットアップ
+	thumbnailUsecase := usecase.NewThumbnailGenerationUsecase(
+		thumbnailRepo,
+		similarityRepo,
+		imageRepo,
+		watermarkRepo,
+		stateRepo,
+		storageService,
+		processingService,
+		processingMetrics,
+		eventDispatcher,
+		renditionSpecs,
+		cfg.ThumbnailMaxAttempts,
+		cfg.AWSRegion,
+	)
+	backfillUsecase := usecase.NewBackfillUsecase(imageRepo, thumbnailUsecase, renditionSpecs, cfg.S3BucketName, logger)
+
+	// 中断シグナルを受けた場合は処理中のページを完了してから終了
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := backfillUsecase.Run(ctx, options)
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if encodeErr := encoder.Encode(report); encodeErr != nil {
+			logger.Error(encodeErr, "Error writing backfill report", nil)
+		}
+	}
+	if err != nil {
+		logger.Fatal(err, "Thumbnail backfill failed", nil)
+	}
+
+	// 失敗した画像がある場合は終了コードで通知
+	if len(report.Failed) > 0 {
+		os.Exit(1)
+	}
+}
+
+// loadState は再開位置を読み込みます（ファイルが存在しない場合は先頭から）
+func loadState(path string) (backfillState, error) {
+	var state backfillState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("invalid state file: %w", err)
+	}
+	return state, nil
+}
+
+// saveState は再開位置を保存します
+func saveState(path string, state backfillState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
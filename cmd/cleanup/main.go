@@ -53,9 +53,10 @@ func main() {
 
 	// インフラストラクチャレイヤーのセットアップ
 	imageRepo := imagemanagement.NewDynamoDBImageRepository(dbClient, cfg.MetadataTableName)
-	tagRepo := tagmanagement.NewDynamoDBTagRepository(dbClient, cfg.TagsTableName, cfg.MetadataTableName)
+	tagRepo := tagmanagement.NewDynamoDBTagRepository(dbClient, cfg.TagsTableName, cfg.MetadataTableName, cfg.TagCountsTableName)
 	storageService := storageS3.NewS3StorageService(s3Client, cfg.AWSRegion)
-	cleanupService := cleanup.NewS3CleanupService(s3Client, dbClient, cfg.S3BucketName, cfg.MetadataTableName, cfg.TagsTableName)
+	similarityRepo := imagemanagement.NewDynamoDBSimilarityRepository(dbClient, cfg.SimilarityTableName)
+	cleanupService := cleanup.NewS3CleanupService(s3Client, dbClient, cfg.S3BucketName, cfg.MetadataTableName, tagRepo, similarityRepo)
 	eventDispatcher := dispatcher.NewSimpleEventDispatcher()
 
 	// アプリケーションレイヤーのセットアップ
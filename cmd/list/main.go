@@ -8,6 +8,7 @@ import (
 	"cloudpix/internal/adapter/middleware"
 	"cloudpix/internal/application/imagemanagement/usecase"
 	"cloudpix/internal/infrastructure/persistence/dynamodb/imagemanagement"
+	"cloudpix/internal/infrastructure/persistence/dynamodb/tagmanagement"
 	"cloudpix/internal/logging"
 	"os"
 
@@ -52,12 +53,16 @@ func main() {
 
 	// リポジトリのセットアップ
 	imageRepo := imagemanagement.NewDynamoDBImageRepository(dbClient, cfg.MetadataTableName)
+	similarityRepo := imagemanagement.NewDynamoDBSimilarityRepository(dbClient, cfg.SimilarityTableName)
+	tagRepo := tagmanagement.NewDynamoDBTagRepository(dbClient, cfg.TagsTableName, cfg.MetadataTableName, cfg.TagCountsTableName)
+	tagAliasRepo := tagmanagement.NewDynamoDBTagAliasRepository(dbClient, cfg.TagAliasesTableName)
 
 	// ユースケースのセットアップ
-	listUsecase := usecase.NewListUsecase(imageRepo)
+	listUsecase := usecase.NewListUsecase(imageRepo, tagRepo, tagAliasRepo)
+	similarityUsecase := usecase.NewSimilarityUsecase(imageRepo, similarityRepo)
 
 	// ハンドラのセットアップ
-	listHandler := handler.NewListHandler(listUsecase)
+	listHandler := handler.NewListHandler(listUsecase, similarityUsecase)
 
 	// ミドルウェア設定の作成
 	middlewareCfg := middleware.NewDefaultMiddlewareConfig()
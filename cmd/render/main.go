@@ -0,0 +1,137 @@
+package main
+
+import (
+	"cloudpix/cmd/shared"
+	"cloudpix/config"
+	"cloudpix/internal/adapter/api/handler"
+	"cloudpix/internal/adapter/middleware"
+	"cloudpix/internal/application/thumbnailmanagement/usecase"
+	imagevalueobject "cloudpix/internal/domain/imagemanagement/valueobject"
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"cloudpix/internal/infrastructure/imaging"
+	"cloudpix/internal/infrastructure/persistence/dynamodb/imagemanagement"
+	"cloudpix/internal/infrastructure/persistence/dynamodb/thumbnailmanagement"
+	s3storage "cloudpix/internal/infrastructure/storage/s3"
+	"cloudpix/internal/logging"
+	"os"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func main() {
+	// 環境変数の設定（必要に応じて）
+	if os.Getenv("ENVIRONMENT") == "dev" {
+		os.Setenv("LOG_LEVEL", "debug")
+	}
+
+	// ロギングの初期化
+	logging.InitLogging()
+	logger := logging.GetLogger("RenderLambda")
+
+	// 設定の読み込み
+	cfg := config.NewConfig()
+
+	// 変換プリセットの読み込み
+	presets, err := valueobject.ParseRenderPresets(cfg.RenderPresets)
+	if err != nil {
+		logger.Fatal(err, "Invalid render preset configuration", nil)
+	}
+	presetNames := make([]string, len(presets))
+	for i, preset := range presets {
+		presetNames[i] = preset.Name
+	}
+
+	logger.Info("Starting Render Lambda", map[string]interface{}{
+		"config": map[string]string{
+			"bucketName":     cfg.S3BucketName,
+			"metadataTable":  cfg.MetadataTableName,
+			"watermarkTable": cfg.WatermarkTableName,
+			"environment":    cfg.Environment,
+		},
+		"presets": presetNames,
+	})
+
+	// AWS セッションの初期化
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(cfg.AWSRegion),
+	})
+	if err != nil {
+		logger.Fatal(err, "Error creating AWS session", nil)
+	}
+
+	// S3とDynamoDBクライアントの初期化
+	s3Client := s3.New(sess)
+	dbClient := dynamodb.New(sess)
+	logger.Info("DynamoDB client initialized", map[string]interface{}{
+		"tableName": cfg.MetadataTableName,
+	})
+
+	// デコードを許可する画像の大きさの上限
+	imageLimits := imagevalueobject.NewImageLimits(cfg.MaxImagePixels, cfg.MaxImageBytes)
+
+	// インフラストラクチャレイヤーのセットアップ
+	imageRepo := imagemanagement.NewDynamoDBImageRepository(dbClient, cfg.MetadataTableName)
+	watermarkRepo := thumbnailmanagement.NewDynamoDBWatermarkRepository(dbClient, cfg.WatermarkTableName)
+	storageService := s3storage.NewS3ThumbnailStorageService(s3Client, cfg.AWSRegion, imageLimits)
+	processingService := imaging.NewImageProcessingService(imageLimits)
+
+	// アプリケーションレイヤーのセットアップ
+	renderUsecase := usecase.NewRenderUsecase(
+		imageRepo,
+		watermarkRepo,
+		storageService,
+		processingService,
+		presets,
+		cfg.S3BucketName,
+	)
+
+	// インターフェースレイヤーのセットアップ
+	renderHandler := handler.NewRenderHandler(renderUsecase)
+
+	// ミドルウェア設定の作成
+	middlewareCfg := middleware.NewDefaultMiddlewareConfig()
+	middlewareCfg.AWSRegion = cfg.AWSRegion
+	middlewareCfg.UserPoolID = cfg.UserPoolID
+	middlewareCfg.ClientID = cfg.ClientID
+	middlewareCfg.ServiceName = "CloudPix"
+	middlewareCfg.OperationName = "RenderImage"
+	middlewareCfg.FunctionName = "RenderLambda"
+	middlewareCfg.AuthEnabled = true // 認証を有効化
+
+	// 環境に基づくログ詳細度の設定
+	if cfg.Environment == "dev" {
+		middlewareCfg.DetailedRequestLog = true
+		middlewareCfg.DetailedResponseLog = true
+		middlewareCfg.IncludeQueryParams = true
+	} else {
+		middlewareCfg.DetailedRequestLog = false
+		middlewareCfg.DetailedResponseLog = false
+		middlewareCfg.IncludeQueryParams = true
+		middlewareCfg.IncludeBody = false
+	}
+
+	// 認証コンポーネントの初期化
+	authUsecase := shared.InitAuth(cfg, sess, logger)
+
+	// ミドルウェアレジストリの取得
+	registry := middleware.GetRegistry()
+
+	// 標準ミドルウェアを登録
+	registry.RegisterStandardMiddlewares(sess, middlewareCfg, authUsecase, logger)
+
+	// ミドルウェア名の順序を指定（ロギングが最初、認証が最後）
+	middlewareNames := []string{"logging", "metrics", "auth"}
+
+	// ミドルウェアチェーンの構築
+	chain := registry.BuildChain(middlewareNames)
+
+	// ハンドラーにミドルウェアを適用
+	wrappedHandler := chain.Then(renderHandler.Handle)
+
+	// Lambda関数のスタート
+	lambda.Start(wrappedHandler)
+}
@@ -0,0 +1,54 @@
+package shared
+
+import (
+	"cloudpix/config"
+	tagevent "cloudpix/internal/application/tagmanagement/event"
+	"cloudpix/internal/application/tagmanagement/usecase"
+	"cloudpix/internal/domain/shared/event/dispatcher"
+	"cloudpix/internal/domain/tagmanagement/valueobject"
+	"cloudpix/internal/infrastructure/persistence/dynamodb/tagmanagement"
+	"cloudpix/internal/logging"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// InitAutoTagging は自動タグ付けのコンポーネントを初期化します
+// ルールが設定されていない場合や設定が不正な場合は nil を返し、画像の処理は自動タグ付けなしで継続します
+func InitAutoTagging(cfg *config.Config, dbClient *dynamodb.DynamoDB, logger logging.Logger) *usecase.AutoTagUsecase {
+	rules, err := valueobject.ParseAutoTagRules(cfg.AutoTagRules)
+	if err != nil {
+		logger.Error(err, "Invalid auto-tagging rule configuration, auto-tagging is disabled", nil)
+		return nil
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	// 付与を許可するタグの名前空間の読み込み
+	tagNamespaces, err := valueobject.ParseTagNamespaces(cfg.TagNamespaces)
+	if err != nil {
+		logger.Error(err, "Invalid tag namespace configuration, auto-tagging is disabled", nil)
+		return nil
+	}
+
+	// タグ管理のリポジトリの初期化
+	tagRepo := tagmanagement.NewDynamoDBTagRepository(dbClient, cfg.TagsTableName, cfg.MetadataTableName, cfg.TagCountsTableName)
+	tagAliasRepo := tagmanagement.NewDynamoDBTagAliasRepository(dbClient, cfg.TagAliasesTableName)
+	eventDispatcher := dispatcher.NewSimpleEventDispatcher()
+
+	// 自動で付けたタグも変更履歴として記録（テーブル未設定の場合は記録しない）
+	if cfg.TagHistoryTableName != "" {
+		tagHistoryRepo := tagmanagement.NewDynamoDBTagHistoryRepository(dbClient, cfg.TagHistoryTableName)
+		eventDispatcher.Register(tagevent.NewTagHistoryHandler(tagHistoryRepo, logger))
+	}
+
+	// 自動タグ付けユースケースの初期化
+	tagUsecase := usecase.NewTagUsecase(tagRepo, tagAliasRepo, eventDispatcher, tagNamespaces)
+	autoTagUsecase := usecase.NewAutoTagUsecase(rules, tagUsecase)
+
+	logger.Info("Auto-tagging enabled", map[string]interface{}{
+		"rules": len(rules),
+	})
+
+	return autoTagUsecase
+}
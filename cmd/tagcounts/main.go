@@ -0,0 +1,79 @@
+package main
+
+import (
+	"cloudpix/config"
+	"cloudpix/internal/application/tagmanagement/usecase"
+	"cloudpix/internal/domain/shared/event/dispatcher"
+	"cloudpix/internal/domain/tagmanagement/valueobject"
+	"cloudpix/internal/infrastructure/persistence/dynamodb/tagmanagement"
+	"cloudpix/internal/logging"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func main() {
+	// ロギングの初期化
+	logging.InitLogging()
+	logger := logging.GetLogger("TagCountsRebuild")
+
+	// 設定の読み込み
+	cfg := config.NewConfig()
+	if cfg.TagsTableName == "" || cfg.MetadataTableName == "" || cfg.TagCountsTableName == "" {
+		logger.Fatal(errors.New("missing configuration"), "TAGS_TABLE_NAME, METADATA_TABLE_NAME and TAG_COUNTS_TABLE_NAME are required", nil)
+	}
+
+	logger.Info("Starting tag counts rebuild", map[string]interface{}{
+		"tagsTable":      cfg.TagsTableName,
+		"metadataTable":  cfg.MetadataTableName,
+		"tagCountsTable": cfg.TagCountsTableName,
+	})
+
+	// AWS セッションの初期化
+	// リージョン未指定の場合はAWS CLIの共有設定を使用
+	awsConfig := aws.Config{}
+	if cfg.AWSRegion != "" {
+		awsConfig.Region = aws.String(cfg.AWSRegion)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            awsConfig,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		logger.Fatal(err, "Error creating AWS session", nil)
+	}
+
+	// DynamoDBクライアントの初期化
+	dbClient := dynamodb.New(sess)
+
+	// 付与を許可するタグの名前空間の読み込み
+	tagNamespaces, err := valueobject.ParseTagNamespaces(cfg.TagNamespaces)
+	if err != nil {
+		logger.Fatal(err, "Invalid tag namespace configuration", nil)
+	}
+
+	// インフラストラクチャレイヤーのセットアップ
+	tagRepo := tagmanagement.NewDynamoDBTagRepository(dbClient, cfg.TagsTableName, cfg.MetadataTableName, cfg.TagCountsTableName)
+	tagAliasRepo := tagmanagement.NewDynamoDBTagAliasRepository(dbClient, cfg.TagAliasesTableName)
+	eventDispatcher := dispatcher.NewSimpleEventDispatcher()
+
+	// アプリケーションレイヤーのセットアップ
+	tagUsecase := usecase.NewTagUsecase(tagRepo, tagAliasRepo, eventDispatcher, tagNamespaces)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	rebuilt, err := tagUsecase.RebuildTagCounts(ctx)
+	if err != nil {
+		logger.Fatal(err, "Tag counts rebuild failed", nil)
+	}
+
+	fmt.Printf("rebuilt %d tag counts\n", rebuilt)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"cloudpix/config"
+	"cloudpix/internal/application/tagmanagement/dto"
+	tagevent "cloudpix/internal/application/tagmanagement/event"
+	"cloudpix/internal/application/tagmanagement/usecase"
+	"cloudpix/internal/domain/shared/event/dispatcher"
+	"cloudpix/internal/domain/tagmanagement/valueobject"
+	"cloudpix/internal/infrastructure/persistence/dynamodb/tagmanagement"
+	"cloudpix/internal/logging"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// historyActorID は変更履歴に記録するこのコマンドによる変更の実行元
+const historyActorID = "cli:tagmerge"
+
+// mergeState は中断した位置から再開するための状態
+type mergeState struct {
+	Cursor string `json:"cursor"`
+}
+
+// mergeReport はすべてのバッチの処理結果の集計
+type mergeReport struct {
+	Sources   []string `json:"sources"`
+	Target    string   `json:"target"`
+	Processed int      `json:"processed"`
+	Modified  int      `json:"modified"`
+	Failed    []string `json:"failed"`
+	Completed bool     `json:"completed"`
+}
+
+func main() {
+	from := flag.String("from", "", "統合元のタグのカンマ区切りリスト（1つの場合は名前変更）")
+	to := flag.String("to", "", "統合先のタグ")
+	batchSize := flag.Int("batch", usecase.DefaultMergeBatchSize, "1回のバッチで処理する画像数")
+	stateFile := flag.String("state", "", "再開位置を保存するファイル（指定時は前回の位置から再開）")
+	flag.Parse()
+
+	// ロギングの初期化
+	logging.InitLogging()
+	logger := logging.GetLogger("TagMerge")
+
+	// 設定の読み込み
+	cfg := config.NewConfig()
+	if cfg.TagsTableName == "" || cfg.MetadataTableName == "" || cfg.TagCountsTableName == "" {
+		logger.Fatal(errors.New("missing configuration"), "TAGS_TABLE_NAME, METADATA_TABLE_NAME and TAG_COUNTS_TABLE_NAME are required", nil)
+	}
+
+	request := dto.MergeTagsRequestDTO{
+		Target:    *to,
+		MaxImages: *batchSize,
+		ActorID:   historyActorID,
+	}
+	for _, tagName := range strings.Split(*from, ",") {
+		if tagName = strings.TrimSpace(tagName); tagName != "" {
+			request.Sources = append(request.Sources, tagName)
+		}
+	}
+	if len(request.Sources) == 0 || request.Target == "" {
+		logger.Fatal(errors.New("missing arguments"), "-from and -to are required", nil)
+	}
+
+	// 再開位置の読み込み
+	if *stateFile != "" {
+		state, err := loadState(*stateFile)
+		if err != nil {
+			logger.Fatal(err, "Error loading merge state", map[string]interface{}{
+				"stateFile": *stateFile,
+			})
+		}
+		request.Cursor = state.Cursor
+	}
+
+	logger.Info("Starting tag merge", map[string]interface{}{
+		"sources": request.Sources,
+		"target":  request.Target,
+		"cursor":  request.Cursor,
+	})
+
+	// AWS セッションの初期化
+	// リージョン未指定の場合はAWS CLIの共有設定を使用
+	awsConfig := aws.Config{}
+	if cfg.AWSRegion != "" {
+		awsConfig.Region = aws.String(cfg.AWSRegion)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            awsConfig,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		logger.Fatal(err, "Error creating AWS session", nil)
+	}
+
+	// DynamoDBクライアントの初期化
+	dbClient := dynamodb.New(sess)
+
+	// 付与を許可するタグの名前空間の読み込み
+	tagNamespaces, err := valueobject.ParseTagNamespaces(cfg.TagNamespaces)
+	if err != nil {
+		logger.Fatal(err, "Invalid tag namespace configuration", nil)
+	}
+
+	// インフラストラクチャレイヤーのセットアップ
+	tagRepo := tagmanagement.NewDynamoDBTagRepository(dbClient, cfg.TagsTableName, cfg.MetadataTableName, cfg.TagCountsTableName)
+	tagAliasRepo := tagmanagement.NewDynamoDBTagAliasRepository(dbClient, cfg.TagAliasesTableName)
+	eventDispatcher := dispatcher.NewSimpleEventDispatcher()
+
+	// 書き換えたタグを変更履歴として記録（テーブル未設定の場合は記録しない）
+	if cfg.TagHistoryTableName != "" {
+		tagHistoryRepo := tagmanagement.NewDynamoDBTagHistoryRepository(dbClient, cfg.TagHistoryTableName)
+		eventDispatcher.Register(tagevent.NewTagHistoryHandler(tagHistoryRepo, logger))
+	}
+
+	// アプリケーションレイヤーのセットアップ
+	tagUsecase := usecase.NewTagUsecase(tagRepo, tagAliasRepo, eventDispatcher, tagNamespaces)
+
+	// 中断シグナルを受けた場合は処理中のバッチを完了してから終了
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report := mergeReport{Failed: []string{}}
+	for ctx.Err() == nil {
+		response, err := tagUsecase.MergeTags(context.WithoutCancel(ctx), &request)
+		if err != nil {
+			logger.Fatal(err, "Tag merge failed", nil)
+		}
+
+		report.Sources = response.Sources
+		report.Target = response.Target
+		report.Processed += response.Processed
+		report.Modified += response.Modified
+		report.Failed = append(report.Failed, response.Failed...)
+		report.Completed = response.Completed
+
+		request.Cursor = response.NextCursor
+		if *stateFile != "" {
+			if err := saveState(*stateFile, mergeState{Cursor: request.Cursor}); err != nil {
+				logger.Error(err, "Error saving merge state", map[string]interface{}{
+					"stateFile": *stateFile,
+				})
+			}
+		}
+		if response.Completed {
+			break
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		logger.Error(err, "Error writing merge report", nil)
+	}
+
+	// 失敗した画像がある場合や中断した場合は終了コードで通知
+	if len(report.Failed) > 0 || !report.Completed {
+		os.Exit(1)
+	}
+}
+
+// loadState は再開位置を読み込みます（ファイルが存在しない場合は先頭から）
+func loadState(path string) (mergeState, error) {
+	var state mergeState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("invalid state file: %w", err)
+	}
+	return state, nil
+}
+
+// saveState は再開位置を保存します
+func saveState(path string, state mergeState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
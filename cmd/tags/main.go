@@ -5,8 +5,10 @@ import (
 	"cloudpix/config"
 	"cloudpix/internal/adapter/api/handler"
 	"cloudpix/internal/adapter/middleware"
+	tagevent "cloudpix/internal/application/tagmanagement/event"
 	"cloudpix/internal/application/tagmanagement/usecase"
 	"cloudpix/internal/domain/shared/event/dispatcher"
+	"cloudpix/internal/domain/tagmanagement/valueobject"
 	"cloudpix/internal/infrastructure/persistence/dynamodb/tagmanagement"
 	"cloudpix/internal/logging"
 	"os"
@@ -48,19 +50,42 @@ func main() {
 	// DynamoDBクライアントの初期化
 	dbClient := dynamodb.New(sess)
 	logger.Info("DynamoDB client initialized", map[string]interface{}{
-		"tagsTable":     cfg.TagsTableName,
-		"metadataTable": cfg.MetadataTableName,
+		"tagsTable":       cfg.TagsTableName,
+		"metadataTable":   cfg.MetadataTableName,
+		"tagCountsTable":  cfg.TagCountsTableName,
+		"tagHistoryTable": cfg.TagHistoryTableName,
 	})
 
+	// 付与を許可するタグの名前空間の読み込み
+	tagNamespaces, err := valueobject.ParseTagNamespaces(cfg.TagNamespaces)
+	if err != nil {
+		logger.Fatal(err, "Invalid tag namespace configuration", nil)
+	}
+
+	// 自動タグ付けのルールの読み込み（ルールの試行APIで使用）
+	// 設定が不正でもタグのAPIは提供を続け、設定済みのルールの試行はエラーを返す
+	autoTagRules, err := valueobject.ParseAutoTagRules(cfg.AutoTagRules)
+	if err != nil {
+		logger.Error(err, "Invalid auto-tagging rule configuration, configured rules are unavailable for dry-run", nil)
+		autoTagRules = valueobject.AutoTagRules{}
+	}
+
 	// インフラストラクチャレイヤーのセットアップ
-	tagRepo := tagmanagement.NewDynamoDBTagRepository(dbClient, cfg.TagsTableName, cfg.MetadataTableName)
+	tagRepo := tagmanagement.NewDynamoDBTagRepository(dbClient, cfg.TagsTableName, cfg.MetadataTableName, cfg.TagCountsTableName)
+	tagAliasRepo := tagmanagement.NewDynamoDBTagAliasRepository(dbClient, cfg.TagAliasesTableName)
+	tagHistoryRepo := tagmanagement.NewDynamoDBTagHistoryRepository(dbClient, cfg.TagHistoryTableName)
 	eventDispatcher := dispatcher.NewSimpleEventDispatcher()
 
+	// タグ更新イベントを変更履歴として記録
+	eventDispatcher.Register(tagevent.NewTagHistoryHandler(tagHistoryRepo, logger))
+
 	// アプリケーションレイヤーのセットアップ
-	tagUsecase := usecase.NewTagUsecase(tagRepo, eventDispatcher)
+	tagUsecase := usecase.NewTagUsecase(tagRepo, tagAliasRepo, eventDispatcher, tagNamespaces)
+	tagHistoryUsecase := usecase.NewTagHistoryUsecase(tagRepo, tagHistoryRepo)
+	autoTagUsecase := usecase.NewAutoTagUsecase(autoTagRules, tagUsecase)
 
 	// インターフェースレイヤーのセットアップ
-	tagHandler := handler.NewTagHandler(tagUsecase)
+	tagHandler := handler.NewTagHandler(tagUsecase, tagHistoryUsecase, autoTagUsecase)
 
 	// ミドルウェア設定の作成
 	middlewareCfg := middleware.NewDefaultMiddlewareConfig()
@@ -1,12 +1,18 @@
 package main
 
 import (
+	"cloudpix/cmd/shared"
 	"cloudpix/config"
 	s3handler "cloudpix/internal/adapter/event/s3"
 	"cloudpix/internal/adapter/middleware"
+	tagevent "cloudpix/internal/application/tagmanagement/event"
 	"cloudpix/internal/application/thumbnailmanagement/usecase"
+	imagevalueobject "cloudpix/internal/domain/imagemanagement/valueobject"
 	"cloudpix/internal/domain/shared/event/dispatcher"
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
 	"cloudpix/internal/infrastructure/imaging"
+	"cloudpix/internal/infrastructure/metrics"
+	"cloudpix/internal/infrastructure/persistence/dynamodb/imagemanagement"
 	"cloudpix/internal/infrastructure/persistence/dynamodb/thumbnailmanagement"
 	s3storage "cloudpix/internal/infrastructure/storage/s3"
 	"cloudpix/internal/logging"
@@ -19,9 +25,6 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
-// サムネイルサイズ（ピクセル）
-const thumbnailSize = 200
-
 func main() {
 	// 環境変数の設定（必要に応じて）
 	if os.Getenv("ENVIRONMENT") == "dev" {
@@ -34,13 +37,27 @@ func main() {
 
 	// 設定の読み込み
 	cfg := config.NewConfig()
+
+	// レンディション定義の読み込み
+	renditionSpecs, err := valueobject.ParseRenditionSpecs(cfg.ThumbnailRenditions)
+	if err != nil {
+		logger.Fatal(err, "Invalid thumbnail rendition configuration", nil)
+	}
+	renditionNames := make([]string, len(renditionSpecs))
+	for i, spec := range renditionSpecs {
+		// アニメーションGIFはフラグが有効な場合のみアニメーションを維持
+		renditionSpecs[i] = spec.WithAnimated(cfg.ThumbnailAnimatedGIF)
+		renditionNames[i] = spec.Name()
+	}
+
 	logger.Info("Starting Thumbnail Lambda", map[string]interface{}{
 		"config": map[string]string{
 			"bucketName":    cfg.S3BucketName,
 			"metadataTable": cfg.MetadataTableName,
 			"environment":   cfg.Environment,
 		},
-		"thumbnailSize": thumbnailSize,
+		"renditions":  renditionNames,
+		"animatedGIF": cfg.ThumbnailAnimatedGIF,
 	})
 
 	// AWS セッションの初期化
@@ -58,19 +75,42 @@ func main() {
 		"tableName": cfg.MetadataTableName,
 	})
 
+	// デコードを許可する画像の大きさの上限
+	imageLimits := imagevalueobject.NewImageLimits(cfg.MaxImagePixels, cfg.MaxImageBytes)
+
 	// インフラストラクチャレイヤーのセットアップ
 	thumbnailRepo := thumbnailmanagement.NewDynamoDBThumbnailRepository(dbClient, cfg.MetadataTableName)
-	storageService := s3storage.NewS3ThumbnailStorageService(s3Client, cfg.AWSRegion)
-	processingService := imaging.NewImageProcessingService()
+	similarityRepo := imagemanagement.NewDynamoDBSimilarityRepository(dbClient, cfg.SimilarityTableName)
+	imageRepo := imagemanagement.NewDynamoDBImageRepository(dbClient, cfg.MetadataTableName)
+	watermarkRepo := thumbnailmanagement.NewDynamoDBWatermarkRepository(dbClient, cfg.WatermarkTableName)
+	stateRepo := thumbnailmanagement.NewDynamoDBProcessingStateRepository(dbClient, cfg.MetadataTableName)
+	storageService := s3storage.NewS3ThumbnailStorageService(s3Client, cfg.AWSRegion, imageLimits)
+	processingService := imaging.NewImageProcessingService(imageLimits)
+	var metricsService metrics.MetricsService
+	if cfg.EnableMetrics {
+		metricsService = metrics.NewCloudWatchMetricsService(sess, metrics.DefaultMetricsConfig)
+	}
+	processingMetrics := metrics.NewThumbnailProcessingMetrics(metricsService, "ThumbnailLambda")
 	eventDispatcher := dispatcher.NewSimpleEventDispatcher()
 
+	// 元画像の幅・高さ・EXIFで判定する自動タグ付けのルールを適用
+	if autoTagUsecase := shared.InitAutoTagging(cfg, dbClient, logger); autoTagUsecase != nil {
+		eventDispatcher.Register(tagevent.NewThumbnailGeneratedAutoTagHandler(autoTagUsecase, imageRepo, logger))
+	}
+
 	// アプリケーションレイヤーのセットアップ
 	thumbnailUsecase := usecase.NewThumbnailGenerationUsecase(
 		thumbnailRepo,
+		similarityRepo,
+		imageRepo,
+		watermarkRepo,
+		stateRepo,
 		storageService,
 		processingService,
+		processingMetrics,
 		eventDispatcher,
-		thumbnailSize,
+		renditionSpecs,
+		cfg.ThumbnailMaxAttempts,
 		cfg.AWSRegion,
 	)
 
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"cloudpix/cmd/shared"
+	"cloudpix/config"
+	scheduler_handler "cloudpix/internal/adapter/event/scheduler"
+	"cloudpix/internal/adapter/middleware"
+	tagevent "cloudpix/internal/application/tagmanagement/event"
+	"cloudpix/internal/application/thumbnailmanagement/usecase"
+	imagevalueobject "cloudpix/internal/domain/imagemanagement/valueobject"
+	"cloudpix/internal/domain/shared/event/dispatcher"
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"cloudpix/internal/infrastructure/imaging"
+	"cloudpix/internal/infrastructure/metrics"
+	"cloudpix/internal/infrastructure/persistence/dynamodb/imagemanagement"
+	"cloudpix/internal/infrastructure/persistence/dynamodb/thumbnailmanagement"
+	s3storage "cloudpix/internal/infrastructure/storage/s3"
+	"cloudpix/internal/logging"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func main() {
+	// ロギングの初期化
+	logging.InitLogging()
+	logger := logging.GetLogger("ThumbnailRetryLambda")
+
+	// 設定の読み込み
+	cfg := config.NewConfig()
+
+	// レンディション定義の読み込み（サムネイル生成関数と同じ設定を使用）
+	renditionSpecs, err := valueobject.ParseRenditionSpecs(cfg.ThumbnailRenditions)
+	if err != nil {
+		logger.Fatal(err, "Invalid thumbnail rendition configuration", nil)
+	}
+	for i, spec := range renditionSpecs {
+		renditionSpecs[i] = spec.WithAnimated(cfg.ThumbnailAnimatedGIF)
+	}
+
+	logger.Info("Starting Thumbnail Retry Lambda", map[string]interface{}{
+		"config": map[string]string{
+			"bucketName":    cfg.S3BucketName,
+			"metadataTable": cfg.MetadataTableName,
+		},
+		"maxAttempts": cfg.ThumbnailMaxAttempts,
+	})
+
+	// AWS セッションの初期化
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(cfg.AWSRegion),
+	})
+	if err != nil {
+		logger.Fatal(err, "Error creating AWS session", nil)
+	}
+
+	// クライアントの初期化
+	s3Client := s3.New(sess)
+	dbClient := dynamodb.New(sess)
+
+	// デコードを許可する画像の大きさの上限
+	imageLimits := imagevalueobject.NewImageLimits(cfg.MaxImagePixels, cfg.MaxImageBytes)
+
+	// インフラストラクチャレイヤーのセットアップ
+	thumbnailRepo := thumbnailmanagement.NewDynamoDBThumbnailRepository(dbClient, cfg.MetadataTableName)
+	similarityRepo := imagemanagement.NewDynamoDBSimilarityRepository(dbClient, cfg.SimilarityTableName)
+	imageRepo := imagemanagement.NewDynamoDBImageRepository(dbClient, cfg.MetadataTableName)
+	watermarkRepo := thumbnailmanagement.NewDynamoDBWatermarkRepository(dbClient, cfg.WatermarkTableName)
+	stateRepo := thumbnailmanagement.NewDynamoDBProcessingStateRepository(dbClient, cfg.MetadataTableName)
+	storageService := s3storage.NewS3ThumbnailStorageService(s3Client, cfg.AWSRegion, imageLimits)
+	processingService := imaging.NewImageProcessingService(imageLimits)
+	var metricsService metrics.MetricsService
+	if cfg.EnableMetrics {
+		metricsService = metrics.NewCloudWatchMetricsService(sess, metrics.DefaultMetricsConfig)
+	}
+	processingMetrics := metrics.NewThumbnailProcessingMetrics(metricsService, "ThumbnailRetryLambda")
+	eventDispatcher := dispatcher.NewSimpleEventDispatcher()
+
+	// 元画像の幅・高さ・EXIFで判定する自動タグ付けのルールを適用
+	if autoTagUsecase := shared.InitAutoTagging(cfg, dbClient, logger); autoTagUsecase != nil {
+		eventDispatcher.Register(tagevent.NewThumbnailGeneratedAutoTagHandler(autoTagUsecase, imageRepo, logger))
+	}
+
+	// アプリケーションレイヤーのセットアップ
+	thumbnailUsecase := usecase.NewThumbnailGenerationUsecase(
+		thumbnailRepo,
+		similarityRepo,
+		imageRepo,
+		watermarkRepo,
+		stateRepo,
+		storageService,
+		processingService,
+		processingMetrics,
+		eventDispatcher,
+		renditionSpecs,
+		cfg.ThumbnailMaxAttempts,
+		cfg.AWSRegion,
+	)
+	retryUsecase := usecase.NewThumbnailRetryUsecase(stateRepo, thumbnailUsecase, cfg.S3BucketName, usecase.DefaultRetryBatchSize, logger)
+
+	// ハンドラーのセットアップ
+	retryHandler := scheduler_handler.NewThumbnailRetryHandler(retryUsecase, logger)
+
+	// ミドルウェア設定の作成
+	middlewareCfg := middleware.NewDefaultMiddlewareConfig()
+	middlewareCfg.AWSRegion = cfg.AWSRegion
+	middlewareCfg.ServiceName = "CloudPix"
+	middlewareCfg.OperationName = "RetryThumbnail"
+	middlewareCfg.FunctionName = "ThumbnailRetryLambda"
+
+	// 認証は不要（スケジュールタスクのため）
+	middlewareCfg.AuthEnabled = false
+
+	// ミドルウェアレジストリの取得
+	registry := middleware.GetRegistry()
+
+	// 標準ミドルウェアを登録（認証なし）
+	registry.RegisterStandardMiddlewares(sess, middlewareCfg, nil, logger)
+
+	// イベント用のミドルウェア処理
+	handlerFactory := middleware.NewHandlerFactory(middlewareCfg).WithAWSSession(sess)
+
+	// ミドルウェアを適用したスケジュールイベントハンドラーを作成
+	wrappedHandler := handlerFactory.WrapCloudWatchEventHandler(retryHandler.Handle)
+
+	// Lambda関数のスタート
+	lambda.Start(wrappedHandler)
+}
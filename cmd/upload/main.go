@@ -6,7 +6,10 @@ import (
 	"cloudpix/internal/adapter/api/handler"
 	"cloudpix/internal/adapter/middleware"
 	"cloudpix/internal/application/imagemanagement/usecase"
+	tagevent "cloudpix/internal/application/tagmanagement/event"
+	imagevalueobject "cloudpix/internal/domain/imagemanagement/valueobject"
 	"cloudpix/internal/domain/shared/event/dispatcher"
+	"cloudpix/internal/infrastructure/imaging"
 	"cloudpix/internal/infrastructure/persistence/dynamodb/imagemanagement"
 	storageS3 "cloudpix/internal/infrastructure/storage/s3"
 	"cloudpix/internal/logging"
@@ -57,10 +60,16 @@ func main() {
 	// インフラストラクチャレイヤーのセットアップ
 	imageRepo := imagemanagement.NewDynamoDBImageRepository(dbClient, cfg.MetadataTableName)
 	storageService := storageS3.NewS3StorageService(s3Client, cfg.AWSRegion)
+	imageInspector := imaging.NewImageInspector(imagevalueobject.NewImageLimits(cfg.MaxImagePixels, cfg.MaxImageBytes))
 	eventDispatcher := dispatcher.NewSimpleEventDispatcher()
 
+	// アップロード時点で判定できる自動タグ付けのルールを適用
+	if autoTagUsecase := shared.InitAutoTagging(cfg, dbClient, logger); autoTagUsecase != nil {
+		eventDispatcher.Register(tagevent.NewImageUploadedAutoTagHandler(autoTagUsecase, logger))
+	}
+
 	// アプリケーションレイヤーのセットアップ
-	uploadUsecase := usecase.NewUploadUsecase(imageRepo, storageService, eventDispatcher, cfg.S3BucketName)
+	uploadUsecase := usecase.NewUploadUsecase(imageRepo, storageService, imageInspector, eventDispatcher, cfg.S3BucketName)
 
 	// インターフェースレイヤーのセットアップ
 	uploadHandler := handler.NewUploadHandler(uploadUsecase)
@@ -6,17 +6,30 @@ import (
 )
 
 type Config struct {
-	S3BucketName       string
-	TagsTableName      string
-	MetadataTableName  string
-	AWSRegion          string
-	UserPoolID         string
-	ClientID           string
-	Environment        string
-	FunctionName       string
-	EnableMetrics      bool
-	EnableXRay         bool
-	ImageRetentionDays int
+	S3BucketName         string
+	TagsTableName        string
+	TagCountsTableName   string
+	TagAliasesTableName  string
+	TagHistoryTableName  string
+	TagNamespaces        string
+	AutoTagRules         string
+	MetadataTableName    string
+	SimilarityTableName  string
+	WatermarkTableName   string
+	AWSRegion            string
+	UserPoolID           string
+	ClientID             string
+	Environment          string
+	FunctionName         string
+	EnableMetrics        bool
+	EnableXRay           bool
+	ImageRetentionDays   int
+	ThumbnailRenditions  string
+	ThumbnailAnimatedGIF bool
+	ThumbnailMaxAttempts int
+	RenderPresets        string
+	MaxImagePixels       int64
+	MaxImageBytes        int64
 }
 
 func NewConfig() *Config {
@@ -31,17 +44,47 @@ func NewConfig() *Config {
 		}
 	}
 
+	// サムネイル生成の最大試行回数の取得
+	maxAttempts := 0 // 未設定の場合はユースケースのデフォルト値を使用
+	if maxAttemptsStr := os.Getenv("THUMBNAIL_MAX_ATTEMPTS"); maxAttemptsStr != "" {
+		if attempts, err := strconv.Atoi(maxAttemptsStr); err == nil {
+			maxAttempts = attempts
+		}
+	}
+
+	// 画像の大きさの上限の取得（未設定の場合はデフォルト値を使用）
+	var maxImagePixels, maxImageBytes int64
+	if value, err := strconv.ParseInt(os.Getenv("MAX_IMAGE_PIXELS"), 10, 64); err == nil {
+		maxImagePixels = value
+	}
+	if value, err := strconv.ParseInt(os.Getenv("MAX_IMAGE_BYTES"), 10, 64); err == nil {
+		maxImageBytes = value
+	}
+
 	return &Config{
-		S3BucketName:       os.Getenv("S3_BUCKET_NAME"),
-		TagsTableName:      os.Getenv("TAGS_TABLE_NAME"),
-		MetadataTableName:  os.Getenv("METADATA_TABLE_NAME"),
-		AWSRegion:          os.Getenv("AWS_REGION"),
-		UserPoolID:         os.Getenv("USER_POOL_ID"),
-		ClientID:           os.Getenv("USER_POOL_CLIENT_ID"),
-		Environment:        os.Getenv("ENVIRONMENT"),
-		FunctionName:       os.Getenv("AWS_LAMBDA_FUNCTION_NAME"),
-		EnableMetrics:      enableMetrics,
-		EnableXRay:         enableXRay,
-		ImageRetentionDays: retentionDays,
+		S3BucketName:         os.Getenv("S3_BUCKET_NAME"),
+		TagsTableName:        os.Getenv("TAGS_TABLE_NAME"),
+		TagCountsTableName:   os.Getenv("TAG_COUNTS_TABLE_NAME"),
+		TagAliasesTableName:  os.Getenv("TAG_ALIASES_TABLE_NAME"),
+		TagHistoryTableName:  os.Getenv("TAG_HISTORY_TABLE_NAME"),
+		TagNamespaces:        os.Getenv("TAG_NAMESPACES"),
+		AutoTagRules:         os.Getenv("AUTO_TAG_RULES"),
+		MetadataTableName:    os.Getenv("METADATA_TABLE_NAME"),
+		SimilarityTableName:  os.Getenv("SIMILARITY_TABLE_NAME"),
+		WatermarkTableName:   os.Getenv("WATERMARK_TABLE_NAME"),
+		AWSRegion:            os.Getenv("AWS_REGION"),
+		UserPoolID:           os.Getenv("USER_POOL_ID"),
+		ClientID:             os.Getenv("USER_POOL_CLIENT_ID"),
+		Environment:          os.Getenv("ENVIRONMENT"),
+		FunctionName:         os.Getenv("AWS_LAMBDA_FUNCTION_NAME"),
+		EnableMetrics:        enableMetrics,
+		EnableXRay:           enableXRay,
+		ImageRetentionDays:   retentionDays,
+		ThumbnailRenditions:  os.Getenv("THUMBNAIL_RENDITIONS"),
+		ThumbnailAnimatedGIF: os.Getenv("THUMBNAIL_ANIMATED_GIF") == "true",
+		ThumbnailMaxAttempts: maxAttempts,
+		RenderPresets:        os.Getenv("RENDER_PRESETS"),
+		MaxImagePixels:       maxImagePixels,
+		MaxImageBytes:        maxImageBytes,
 	}
 }
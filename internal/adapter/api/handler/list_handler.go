@@ -3,23 +3,29 @@ package handler
 import (
 	"cloudpix/internal/application/imagemanagement/dto"
 	"cloudpix/internal/application/imagemanagement/usecase"
+	"cloudpix/internal/contextutil"
 	"cloudpix/internal/logging"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 )
 
 // ListHandler は画像一覧を処理するハンドラー
 type ListHandler struct {
-	listUsecase *usecase.ListUsecase
+	listUsecase       *usecase.ListUsecase
+	similarityUsecase *usecase.SimilarityUsecase
 }
 
 // NewListHandler は新しいListHandlerを作成します
-func NewListHandler(listUsecase *usecase.ListUsecase) *ListHandler {
+func NewListHandler(listUsecase *usecase.ListUsecase, similarityUsecase *usecase.SimilarityUsecase) *ListHandler {
 	return &ListHandler{
-		listUsecase: listUsecase,
+		listUsecase:       listUsecase,
+		similarityUsecase: similarityUsecase,
 	}
 }
 
@@ -31,20 +37,52 @@ func (h *ListHandler) Handle(ctx context.Context, request events.APIGatewayProxy
 		"path":   request.Path,
 	})
 
+	// リソースに応じたルーティング
+	switch request.Resource {
+	case "/images/{imageId}":
+		return h.getImage(ctx, request)
+	case "/images/{imageId}/similar":
+		return h.getSimilarImages(ctx, request)
+	case "/images/duplicates":
+		return h.getDuplicates(ctx, request)
+	case "/search":
+		return h.search(ctx, request)
+	}
+
 	// クエリパラメータからフィルターを取得
 	date := request.QueryStringParameters["date"]
+	color := request.QueryStringParameters["color"]
+	expand, err := usecase.ParseExpand(request.QueryStringParameters["expand"])
+	if err != nil {
+		return h.errorResponse(http.StatusBadRequest, err.Error())
+	}
 
 	var response *dto.ListResponse
-	var err error
 
-	if date != "" {
+	if color != "" {
+		tolerance, parseErr := parseTolerance(request.QueryStringParameters["tolerance"])
+		if parseErr != nil {
+			return h.errorResponse(http.StatusBadRequest, usecase.ErrInvalidTolerance.Error())
+		}
+		cursor := request.QueryStringParameters["cursor"]
+		logger.Info("Filtering by color", map[string]interface{}{
+			"color":     color,
+			"tolerance": tolerance,
+			"date":      date,
+			"cursor":    cursor,
+		})
+		response, err = h.listUsecase.ListByColor(ctx, date, color, tolerance, cursor, expand)
+		if errors.Is(err, usecase.ErrInvalidColor) || errors.Is(err, usecase.ErrInvalidTolerance) {
+			return h.errorResponse(http.StatusBadRequest, err.Error())
+		}
+	} else if date != "" {
 		logger.Info("Filtering by date", map[string]interface{}{
 			"date": date,
 		})
-		response, err = h.listUsecase.ListByDate(ctx, date)
+		response, err = h.listUsecase.ListByDate(ctx, date, expand)
 	} else {
 		logger.Info("Listing all images", nil)
-		response, err = h.listUsecase.List(ctx)
+		response, err = h.listUsecase.List(ctx, expand)
 	}
 
 	if err != nil {
@@ -59,6 +97,179 @@ func (h *ListHandler) Handle(ctx context.Context, request events.APIGatewayProxy
 	return h.jsonResponse(http.StatusOK, response)
 }
 
+// getImage は特定の画像の詳細を取得します
+func (h *ListHandler) getImage(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+
+	// パスパラメータから画像IDを取得
+	imageID := request.PathParameters["imageId"]
+	if imageID == "" {
+		return h.errorResponse(http.StatusBadRequest, "画像IDが指定されていません")
+	}
+
+	expand, err := usecase.ParseExpand(request.QueryStringParameters["expand"])
+	if err != nil {
+		return h.errorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	// 画像の詳細を取得
+	response, err := h.listUsecase.GetImage(ctx, imageID, expand)
+	if err != nil {
+		if errors.Is(err, usecase.ErrImageNotFound) {
+			return h.errorResponse(http.StatusNotFound, "指定された画像が見つかりません")
+		}
+		logger.Error(err, "Error getting image", map[string]interface{}{
+			"imageId": imageID,
+		})
+		return h.errorResponse(http.StatusInternalServerError, "画像の取得に失敗しました")
+	}
+
+	return h.jsonResponse(http.StatusOK, response)
+}
+
+// getSimilarImages は知覚ハッシュが近い画像を取得します
+func (h *ListHandler) getSimilarImages(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+
+	// パスパラメータから画像IDを取得
+	imageID := request.PathParameters["imageId"]
+	if imageID == "" {
+		return h.errorResponse(http.StatusBadRequest, "画像IDが指定されていません")
+	}
+
+	maxDistance, err := parseMaxDistance(request.QueryStringParameters["maxDistance"])
+	if err != nil {
+		return h.errorResponse(http.StatusBadRequest, usecase.ErrInvalidDistance.Error())
+	}
+
+	response, err := h.similarityUsecase.FindSimilar(ctx, imageID, maxDistance)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrImageNotFound):
+			return h.errorResponse(http.StatusNotFound, "指定された画像が見つかりません")
+		case errors.Is(err, usecase.ErrInvalidDistance):
+			return h.errorResponse(http.StatusBadRequest, err.Error())
+		case errors.Is(err, usecase.ErrPerceptualHashNotReady):
+			return h.errorResponse(http.StatusConflict, err.Error())
+		}
+		logger.Error(err, "Error finding similar images", map[string]interface{}{
+			"imageId": imageID,
+		})
+		return h.errorResponse(http.StatusInternalServerError, "類似画像の検索に失敗しました")
+	}
+
+	return h.jsonResponse(http.StatusOK, response)
+}
+
+// getDuplicates は認証ユーザーの画像のうち重複の可能性が高いまとまりを取得します
+func (h *ListHandler) getDuplicates(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+
+	user, ok := contextutil.GetUserInfo(ctx)
+	if !ok || user == nil {
+		return h.errorResponse(http.StatusUnauthorized, "認証が必要です")
+	}
+
+	maxDistance, err := parseMaxDistance(request.QueryStringParameters["maxDistance"])
+	if err != nil {
+		return h.errorResponse(http.StatusBadRequest, usecase.ErrInvalidDistance.Error())
+	}
+
+	response, err := h.similarityUsecase.FindDuplicates(ctx, user.ID.String(), maxDistance)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidDistance) || errors.Is(err, usecase.ErrOwnerRequired) {
+			return h.errorResponse(http.StatusBadRequest, err.Error())
+		}
+		logger.Error(err, "Error finding duplicate images", nil)
+		return h.errorResponse(http.StatusInternalServerError, "重複画像の検索に失敗しました")
+	}
+
+	return h.jsonResponse(http.StatusOK, response)
+}
+
+// search はタグの組み合わせで画像を検索します
+func (h *ListHandler) search(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+	params := request.QueryStringParameters
+
+	expand, err := usecase.ParseExpand(params["expand"])
+	if err != nil {
+		return h.errorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	limit := 0
+	if value := params["limit"]; value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			return h.errorResponse(http.StatusBadRequest, usecase.ErrInvalidSearchLimit.Error())
+		}
+	}
+
+	searchRequest := dto.SearchRequest{
+		Tags:        splitQueryList(params["tags"]),
+		Exclude:     splitQueryList(params["exclude"]),
+		Mode:        dto.SearchMode(strings.ToLower(params["mode"])),
+		Date:        params["date"],
+		ContentType: params["contentType"],
+		Limit:       limit,
+		Cursor:      params["cursor"],
+		Expand:      expand,
+	}
+	logger.Info("Searching images by tags", map[string]interface{}{
+		"tags":        searchRequest.Tags,
+		"exclude":     searchRequest.Exclude,
+		"mode":        searchRequest.Mode,
+		"date":        searchRequest.Date,
+		"contentType": searchRequest.ContentType,
+	})
+
+	response, err := h.listUsecase.Search(ctx, searchRequest)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrSearchTagsRequired),
+			errors.Is(err, usecase.ErrInvalidSearchTag),
+			errors.Is(err, usecase.ErrInvalidSearchMode),
+			errors.Is(err, usecase.ErrInvalidSearchDate),
+			errors.Is(err, usecase.ErrInvalidSearchLimit):
+			return h.errorResponse(http.StatusBadRequest, err.Error())
+		}
+		logger.Error(err, "Error searching images", nil)
+		return h.errorResponse(http.StatusInternalServerError, "画像の検索に失敗しました")
+	}
+
+	return h.jsonResponse(http.StatusOK, response)
+}
+
+// splitQueryList はカンマ区切りのクエリパラメータを分割します
+func splitQueryList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	values := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// parseMaxDistance はクエリパラメータのハミング距離を解析します（未指定の場合はデフォルト値）
+func parseMaxDistance(value string) (int, error) {
+	if value == "" {
+		return usecase.DefaultSimilarityDistance, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// parseTolerance はクエリパラメータの色の許容差を解析します（未指定の場合はデフォルト値）
+func parseTolerance(value string) (float64, error) {
+	if value == "" {
+		return usecase.DefaultColorTolerance, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
 // jsonResponse はJSON形式のレスポンスを作成します
 func (h *ListHandler) jsonResponse(statusCode int, body interface{}) (events.APIGatewayProxyResponse, error) {
 	responseJSON, err := json.Marshal(body)
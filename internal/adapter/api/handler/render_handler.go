@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"cloudpix/internal/application/thumbnailmanagement/dto"
+	"cloudpix/internal/application/thumbnailmanagement/usecase"
+	"cloudpix/internal/logging"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// RenderHandler はオンデマンド画像変換を処理するハンドラー
+type RenderHandler struct {
+	renderUsecase *usecase.RenderUsecase
+}
+
+// NewRenderHandler は新しいRenderHandlerを作成します
+func NewRenderHandler(renderUsecase *usecase.RenderUsecase) *RenderHandler {
+	return &RenderHandler{
+		renderUsecase: renderUsecase,
+	}
+}
+
+// Handle はAPIリクエストを処理します
+func (h *RenderHandler) Handle(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+	logger.Info("Processing render request", map[string]interface{}{
+		"method": request.HTTPMethod,
+		"path":   request.Path,
+	})
+
+	if request.Resource != "/images/{imageId}/render" || request.HTTPMethod != "GET" {
+		return h.errorResponse(http.StatusNotFound, "Not Found")
+	}
+
+	// パスパラメータから画像IDを取得
+	imageID := request.PathParameters["imageId"]
+	if imageID == "" {
+		return h.errorResponse(http.StatusBadRequest, "画像IDが指定されていません")
+	}
+
+	// クエリパラメータから変換パラメータを取得
+	renderRequest, err := h.parseRenderRequest(request.QueryStringParameters)
+	if err != nil {
+		return h.errorResponse(http.StatusBadRequest, "変換パラメータの形式が不正です")
+	}
+
+	// 画像を変換
+	response, err := h.renderUsecase.Render(ctx, imageID, renderRequest)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrImageNotFound):
+			return h.errorResponse(http.StatusNotFound, "指定された画像が見つかりません")
+		case errors.Is(err, usecase.ErrInvalidRenderOptions):
+			return h.errorResponse(http.StatusBadRequest, err.Error())
+		case errors.Is(err, usecase.ErrRenderNotAllowed):
+			return h.errorResponse(http.StatusForbidden, "許可されていない変換パラメータです")
+		case errors.Is(err, usecase.ErrUnsupportedFormat):
+			return h.errorResponse(http.StatusUnsupportedMediaType, "この画像形式は変換できません")
+		case errors.Is(err, usecase.ErrSourceTooLarge):
+			return h.errorResponse(http.StatusUnprocessableEntity, "画像が大きすぎるため変換できません")
+		}
+		logger.Error(err, "Error rendering image", map[string]interface{}{
+			"imageId": imageID,
+		})
+		return h.errorResponse(http.StatusInternalServerError, "画像の変換に失敗しました")
+	}
+
+	logger.Info("Image rendered", map[string]interface{}{
+		"imageId": imageID,
+		"cached":  response.Cached,
+	})
+
+	// キャッシュされた変換結果へリダイレクト
+	return h.redirectResponse(response)
+}
+
+// parseRenderRequest はクエリパラメータから変換リクエストを作成します
+func (h *RenderHandler) parseRenderRequest(params map[string]string) (*dto.RenderRequestDTO, error) {
+	renderRequest := &dto.RenderRequestDTO{
+		Preset:  params["preset"],
+		Fit:     params["fit"],
+		Gravity: params["gravity"],
+		Format:  params["format"],
+	}
+
+	var err error
+	if value := params["width"]; value != "" {
+		if renderRequest.Width, err = strconv.Atoi(value); err != nil {
+			return nil, err
+		}
+	}
+	if value := params["height"]; value != "" {
+		if renderRequest.Height, err = strconv.Atoi(value); err != nil {
+			return nil, err
+		}
+	}
+	if value := params["quality"]; value != "" {
+		if renderRequest.Quality, err = strconv.Atoi(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return renderRequest, nil
+}
+
+// redirectResponse は変換結果へのリダイレクトレスポンスを作成します
+func (h *RenderHandler) redirectResponse(body *dto.RenderResponseDTO) (events.APIGatewayProxyResponse, error) {
+	responseJSON, err := json.Marshal(body)
+	if err != nil {
+		return h.errorResponse(http.StatusInternalServerError, "レスポンス生成中にエラーが発生しました")
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusFound,
+		Headers: map[string]string{
+			"Content-Type":  "application/json",
+			"Location":      body.URL,
+			"Cache-Control": "private, max-age=3600",
+		},
+		Body: string(responseJSON),
+	}, nil
+}
+
+// errorResponse はエラーレスポンスを作成します
+func (h *RenderHandler) errorResponse(statusCode int, message string) (events.APIGatewayProxyResponse, error) {
+	body, _ := json.Marshal(map[string]string{"error": message})
+	return events.APIGatewayProxyResponse{
+		StatusCode: statusCode,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Body: string(body),
+	}, nil
+}
@@ -3,23 +3,35 @@ package handler
 import (
 	"cloudpix/internal/application/tagmanagement/dto"
 	"cloudpix/internal/application/tagmanagement/usecase"
+	"cloudpix/internal/contextutil"
+	authentity "cloudpix/internal/domain/authmanagement/entity"
 	"cloudpix/internal/logging"
 	"context"
 	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 )
 
 // TagHandler はタグ管理のAPIハンドラー
 type TagHandler struct {
-	tagUsecase *usecase.TagUsecase
+	tagUsecase        *usecase.TagUsecase
+	tagHistoryUsecase *usecase.TagHistoryUsecase
+	autoTagUsecase    *usecase.AutoTagUsecase
 }
 
 // NewTagHandler は新しいタグハンドラーを作成します
-func NewTagHandler(tagUsecase *usecase.TagUsecase) *TagHandler {
+func NewTagHandler(
+	tagUsecase *usecase.TagUsecase,
+	tagHistoryUsecase *usecase.TagHistoryUsecase,
+	autoTagUsecase *usecase.AutoTagUsecase,
+) *TagHandler {
 	return &TagHandler{
-		tagUsecase: tagUsecase,
+		tagUsecase:        tagUsecase,
+		tagHistoryUsecase: tagHistoryUsecase,
+		autoTagUsecase:    autoTagUsecase,
 	}
 }
 
@@ -40,6 +52,39 @@ func (h *TagHandler) Handle(ctx context.Context, request events.APIGatewayProxyR
 			// タグを追加
 			return h.addTags(ctx, request)
 		}
+	} else if request.Resource == "/tags/suggest" {
+		if request.HTTPMethod == "GET" {
+			// タグの入力補完の候補を取得
+			return h.suggestTags(ctx, request)
+		}
+	} else if request.Resource == "/tags/rename" || request.Resource == "/tags/merge" {
+		if request.HTTPMethod == "POST" {
+			// タグの名前変更・統合（管理者のみ）
+			return h.mergeTags(ctx, request)
+		}
+	} else if request.Resource == "/tags/history" {
+		if request.HTTPMethod == "GET" {
+			// 期間を指定してタグの変更履歴を取得（管理者のみ）
+			return h.queryHistory(ctx, request)
+		}
+	} else if request.Resource == "/tags/rules/dry-run" {
+		if request.HTTPMethod == "POST" {
+			// 自動タグ付けのルールを画像に適用せずに試行（管理者のみ）
+			return h.dryRunRules(ctx, request)
+		}
+	} else if request.Resource == "/tags/aliases" {
+		if request.HTTPMethod == "GET" {
+			// タグの別名の一覧を取得
+			return h.listAliases(ctx)
+		}
+	} else if request.Resource == "/tags/aliases/{alias}" {
+		if request.HTTPMethod == "PUT" {
+			// タグの別名を登録（管理者のみ）
+			return h.putAlias(ctx, request)
+		} else if request.HTTPMethod == "DELETE" {
+			// タグの別名を削除（管理者のみ）
+			return h.deleteAlias(ctx, request)
+		}
 	} else if request.Resource == "/tags/{imageId}" {
 		if request.HTTPMethod == "GET" {
 			// 特定の画像のタグを取得
@@ -48,19 +93,61 @@ func (h *TagHandler) Handle(ctx context.Context, request events.APIGatewayProxyR
 			// タグを削除
 			return h.removeTags(ctx, request)
 		}
+	} else if request.Resource == "/tags/{imageId}/history" {
+		if request.HTTPMethod == "GET" {
+			// 特定の画像のタグの変更履歴を取得
+			return h.getImageHistory(ctx, request)
+		}
 	}
 
 	// 未対応のパス・メソッド
 	return h.errorResponse(404, "Not Found")
 }
 
-// listTags はすべてのタグのリストを取得する
+// listTags はタグの一覧を利用数とともに取得する
+// sort=count|name で並び順、scope=mine で認証ユーザーの画像での利用数に限定、limit で件数を指定できる
+// namespace で名前空間のタグに限定、group=namespace で名前空間ごとのグループも返す
 func (h *TagHandler) listTags(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	logger := logging.FromContext(ctx)
+	params := request.QueryStringParameters
+
+	listRequest := dto.ListTagsRequestDTO{
+		Sort:      strings.ToLower(params["sort"]),
+		Namespace: params["namespace"],
+	}
+
+	// グループ化
+	switch group := strings.ToLower(params["group"]); group {
+	case "":
+	case "namespace":
+		listRequest.GroupByNamespace = true
+	default:
+		return h.errorResponse(400, "group には namespace を指定してください")
+	}
+
+	// 件数の上限
+	if value := params["limit"]; value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return h.errorResponse(400, usecase.ErrInvalidLimit.Error())
+		}
+		listRequest.Limit = limit
+	}
+
+	// 利用数を数える範囲
+	owner, errResponse, ok := h.usageOwner(ctx, params["scope"])
+	if !ok {
+		return errResponse, nil
+	}
+	listRequest.Owner = owner
 
 	// タグの一覧を取得
-	response, err := h.tagUsecase.ListAllTags(ctx)
+	response, err := h.tagUsecase.ListTags(ctx, &listRequest)
 	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidSort) || errors.Is(err, usecase.ErrInvalidLimit) ||
+			errors.Is(err, usecase.ErrInvalidNamespace) {
+			return h.errorResponse(400, err.Error())
+		}
 		logger.Error(err, "Error listing tags", nil)
 		return h.errorResponse(500, "タグの取得に失敗しました")
 	}
@@ -69,6 +156,68 @@ func (h *TagHandler) listTags(ctx context.Context, request events.APIGatewayProx
 	return h.jsonResponse(200, response)
 }
 
+// suggestTags は名前が前方一致するタグを利用数の多い順に取得する
+// prefix は必須で、scope=mine で認証ユーザーの画像での利用数に限定、limit で候補の件数を指定できる
+func (h *TagHandler) suggestTags(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+	params := request.QueryStringParameters
+
+	suggestRequest := dto.SuggestTagsRequestDTO{
+		Prefix: params["prefix"],
+	}
+	if strings.TrimSpace(suggestRequest.Prefix) == "" {
+		return h.errorResponse(400, "prefix を指定してください")
+	}
+
+	// 候補の件数
+	if value := params["limit"]; value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return h.errorResponse(400, usecase.ErrInvalidSuggestLimit.Error())
+		}
+		suggestRequest.Limit = limit
+	}
+
+	// 利用数を数える範囲
+	owner, errResponse, ok := h.usageOwner(ctx, params["scope"])
+	if !ok {
+		return errResponse, nil
+	}
+	suggestRequest.Owner = owner
+
+	// 候補を取得
+	response, err := h.tagUsecase.SuggestTags(ctx, &suggestRequest)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidPrefix) || errors.Is(err, usecase.ErrInvalidSuggestLimit) {
+			return h.errorResponse(400, err.Error())
+		}
+		logger.Error(err, "Error suggesting tags", nil)
+		return h.errorResponse(500, "タグの候補の取得に失敗しました")
+	}
+
+	// レスポンスをJSON形式で返す
+	return h.jsonResponse(200, response)
+}
+
+// usageOwner は scope パラメータから利用数を数える範囲の所有者を返す（all または未指定の場合は空）
+// 不正な指定の場合はエラーレスポンスと false を返す
+func (h *TagHandler) usageOwner(ctx context.Context, scope string) (string, events.APIGatewayProxyResponse, bool) {
+	switch strings.ToLower(scope) {
+	case "", "all":
+		return "", events.APIGatewayProxyResponse{}, true
+	case "mine":
+		user, ok := contextutil.GetUserInfo(ctx)
+		if !ok || user == nil {
+			response, _ := h.errorResponse(401, "認証が必要です")
+			return "", response, false
+		}
+		return user.ID.String(), events.APIGatewayProxyResponse{}, true
+	default:
+		response, _ := h.errorResponse(400, "scope には all または mine を指定してください")
+		return "", response, false
+	}
+}
+
 // getImageTags は特定の画像のタグを取得する
 func (h *TagHandler) getImageTags(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	logger := logging.FromContext(ctx)
@@ -96,6 +245,7 @@ func (h *TagHandler) getImageTags(ctx context.Context, request events.APIGateway
 }
 
 // addTags はタグを追加する
+// タグごとの処理結果を返し、strict が true の場合は無効なタグが含まれていれば何も変更せずに検証結果とともに400を返す
 func (h *TagHandler) addTags(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	logger := logging.FromContext(ctx)
 
@@ -113,6 +263,7 @@ func (h *TagHandler) addTags(ctx context.Context, request events.APIGatewayProxy
 	if len(tagRequest.Tags) == 0 {
 		return h.errorResponse(400, "タグが指定されていません")
 	}
+	tagRequest.ActorID = h.actorID(ctx)
 
 	// タグを追加
 	response, err := h.tagUsecase.AddTags(ctx, &tagRequest)
@@ -120,6 +271,10 @@ func (h *TagHandler) addTags(ctx context.Context, request events.APIGatewayProxy
 		if errors.Is(err, usecase.ErrImageNotFound) {
 			return h.errorResponse(404, "指定された画像が見つかりません")
 		}
+		var validationErr *usecase.TagValidationError
+		if errors.As(err, &validationErr) {
+			return h.tagValidationErrorResponse(validationErr)
+		}
 		if errors.Is(err, usecase.ErrInvalidTag) {
 			return h.errorResponse(400, "無効なタグ形式が含まれています")
 		}
@@ -134,7 +289,205 @@ func (h *TagHandler) addTags(ctx context.Context, request events.APIGatewayProxy
 	return h.jsonResponse(200, response)
 }
 
+// mergeTags はすべての画像でタグの名前を変更、または複数のタグを1つに統合する（管理者のみ）
+// 処理しきれなかった場合は nextCursor を返すため、cursor に指定して続きを実行する
+func (h *TagHandler) mergeTags(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+
+	user, errResponse, ok := h.requireAdmin(ctx)
+	if !ok {
+		return errResponse, nil
+	}
+
+	// リクエストボディをパースしてタグを書き換え
+	var response *dto.MergeTagsResponseDTO
+	var err error
+	if request.Resource == "/tags/rename" {
+		var renameRequest dto.RenameTagRequestDTO
+		if err := json.Unmarshal([]byte(request.Body), &renameRequest); err != nil {
+			logger.Error(err, "Error parsing request body", nil)
+			return h.errorResponse(400, "無効なリクエスト形式です")
+		}
+		renameRequest.ActorID = user.ID.String()
+		response, err = h.tagUsecase.RenameTag(ctx, &renameRequest)
+	} else {
+		var mergeRequest dto.MergeTagsRequestDTO
+		if err := json.Unmarshal([]byte(request.Body), &mergeRequest); err != nil {
+			logger.Error(err, "Error parsing request body", nil)
+			return h.errorResponse(400, "無効なリクエスト形式です")
+		}
+		mergeRequest.ActorID = user.ID.String()
+		response, err = h.tagUsecase.MergeTags(ctx, &mergeRequest)
+	}
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidTag) {
+			return h.errorResponse(400, "無効なタグ形式が含まれています")
+		}
+		if errors.Is(err, usecase.ErrInvalidMerge) || errors.Is(err, usecase.ErrInvalidMergeCursor) ||
+			errors.Is(err, usecase.ErrInvalidMergeBatchSize) || errors.Is(err, usecase.ErrNamespaceNotAllowed) {
+			return h.errorResponse(400, err.Error())
+		}
+		logger.Error(err, "Error merging tags", map[string]interface{}{
+			"resource": request.Resource,
+			"body":     request.Body,
+		})
+		return h.errorResponse(500, "タグの統合に失敗しました")
+	}
+
+	logger.Info("Tags merged", map[string]interface{}{
+		"userId":    user.ID.String(),
+		"sources":   response.Sources,
+		"target":    response.Target,
+		"processed": response.Processed,
+		"modified":  response.Modified,
+		"failed":    len(response.Failed),
+		"completed": response.Completed,
+	})
+
+	// レスポンスをJSON形式で返す
+	return h.jsonResponse(200, response)
+}
+
+// listAliases はタグの別名の一覧を取得する
+func (h *TagHandler) listAliases(ctx context.Context) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+
+	response, err := h.tagUsecase.ListAliases(ctx)
+	if err != nil {
+		logger.Error(err, "Error listing tag aliases", nil)
+		return h.errorResponse(500, "タグの別名の取得に失敗しました")
+	}
+
+	// レスポンスをJSON形式で返す
+	return h.jsonResponse(200, response)
+}
+
+// putAlias はタグの別名を登録する（管理者のみ）
+func (h *TagHandler) putAlias(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+
+	if _, errResponse, ok := h.requireAdmin(ctx); !ok {
+		return errResponse, nil
+	}
+
+	// リクエストボディをパース
+	var aliasRequest dto.PutTagAliasRequestDTO
+	if err := json.Unmarshal([]byte(request.Body), &aliasRequest); err != nil {
+		logger.Error(err, "Error parsing request body", nil)
+		return h.errorResponse(400, "無効なリクエスト形式です")
+	}
+	aliasRequest.Alias = request.PathParameters["alias"]
+
+	// バリデーション
+	if aliasRequest.Alias == "" {
+		return h.errorResponse(400, "別名は必須です")
+	}
+	if aliasRequest.Canonical == "" {
+		return h.errorResponse(400, "正規のタグは必須です")
+	}
+
+	// 別名を登録
+	response, err := h.tagUsecase.PutAlias(ctx, &aliasRequest)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidTag) {
+			return h.errorResponse(400, "無効なタグ形式が含まれています")
+		}
+		if errors.Is(err, usecase.ErrInvalidAlias) || errors.Is(err, usecase.ErrAliasChain) ||
+			errors.Is(err, usecase.ErrNamespaceNotAllowed) {
+			return h.errorResponse(400, err.Error())
+		}
+		logger.Error(err, "Error saving tag alias", map[string]interface{}{
+			"alias":     aliasRequest.Alias,
+			"canonical": aliasRequest.Canonical,
+		})
+		return h.errorResponse(500, "タグの別名の登録に失敗しました")
+	}
+
+	// レスポンスをJSON形式で返す
+	return h.jsonResponse(200, response)
+}
+
+// deleteAlias はタグの別名を削除する（管理者のみ）
+func (h *TagHandler) deleteAlias(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+
+	if _, errResponse, ok := h.requireAdmin(ctx); !ok {
+		return errResponse, nil
+	}
+
+	alias := request.PathParameters["alias"]
+	if alias == "" {
+		return h.errorResponse(400, "別名は必須です")
+	}
+
+	if err := h.tagUsecase.DeleteAlias(ctx, alias); err != nil {
+		if errors.Is(err, usecase.ErrInvalidTag) {
+			return h.errorResponse(400, "無効なタグ形式です")
+		}
+		if errors.Is(err, usecase.ErrAliasNotFound) {
+			return h.errorResponse(404, err.Error())
+		}
+		logger.Error(err, "Error deleting tag alias", map[string]interface{}{
+			"alias": alias,
+		})
+		return h.errorResponse(500, "タグの別名の削除に失敗しました")
+	}
+
+	return h.jsonResponse(200, map[string]string{
+		"message": "タグの別名を削除しました",
+	})
+}
+
+// dryRunRules は自動タグ付けのルールを画像の属性に対して評価する（管理者のみ）
+// rules を省略した場合は設定されているルールを評価し、画像のタグは変更しない
+func (h *TagHandler) dryRunRules(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+
+	if _, errResponse, ok := h.requireAdmin(ctx); !ok {
+		return errResponse, nil
+	}
+
+	// リクエストボディをパース
+	var dryRunRequest dto.AutoTagDryRunRequestDTO
+	if err := json.Unmarshal([]byte(request.Body), &dryRunRequest); err != nil {
+		logger.Error(err, "Error parsing request body", nil)
+		return h.errorResponse(400, "無効なリクエスト形式です")
+	}
+
+	// ルールを評価
+	response, err := h.autoTagUsecase.DryRun(ctx, &dryRunRequest)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidAutoTagRules) {
+			return h.errorResponse(400, err.Error())
+		}
+		if errors.Is(err, usecase.ErrAutoTagRulesNotConfigured) {
+			return h.errorResponse(409, err.Error())
+		}
+		logger.Error(err, "Error evaluating auto-tagging rules", nil)
+		return h.errorResponse(500, "自動タグ付けのルールの評価に失敗しました")
+	}
+
+	// レスポンスをJSON形式で返す
+	return h.jsonResponse(200, response)
+}
+
+// requireAdmin は認証ユーザーが管理者かどうかを確認する
+// 管理者でない場合はエラーレスポンスと false を返す
+func (h *TagHandler) requireAdmin(ctx context.Context) (*authentity.User, events.APIGatewayProxyResponse, bool) {
+	user, ok := contextutil.GetUserInfo(ctx)
+	if !ok || user == nil {
+		response, _ := h.errorResponse(401, "認証が必要です")
+		return nil, response, false
+	}
+	if !user.IsAdmin() {
+		response, _ := h.errorResponse(403, "管理者のみ操作できます")
+		return nil, response, false
+	}
+	return user, events.APIGatewayProxyResponse{}, true
+}
+
 // removeTags はタグを削除する
+// タグごとの処理結果を返し、strict が true の場合は無効なタグが含まれていれば何も変更せずに検証結果とともに400を返す
 func (h *TagHandler) removeTags(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	logger := logging.FromContext(ctx)
 
@@ -154,6 +507,7 @@ func (h *TagHandler) removeTags(ctx context.Context, request events.APIGatewayPr
 			return h.errorResponse(400, "無効なリクエスト形式です")
 		}
 	}
+	tagRequest.ActorID = h.actorID(ctx)
 
 	// タグを削除（ボディが空の場合はすべてのタグを削除）
 	response, err := h.tagUsecase.RemoveTags(ctx, &tagRequest)
@@ -161,6 +515,10 @@ func (h *TagHandler) removeTags(ctx context.Context, request events.APIGatewayPr
 		if errors.Is(err, usecase.ErrImageNotFound) {
 			return h.errorResponse(404, "指定された画像が見つかりません")
 		}
+		var validationErr *usecase.TagValidationError
+		if errors.As(err, &validationErr) {
+			return h.tagValidationErrorResponse(validationErr)
+		}
 		logger.Error(err, "Error removing tags", map[string]interface{}{
 			"imageId": imageID,
 			"tags":    tagRequest.Tags,
@@ -172,6 +530,107 @@ func (h *TagHandler) removeTags(ctx context.Context, request events.APIGatewayPr
 	return h.jsonResponse(200, response)
 }
 
+// getImageHistory は特定の画像のタグの変更履歴を新しい順に取得する
+// limit で件数、cursor で前回の nextCursor からの続きを指定できる
+func (h *TagHandler) getImageHistory(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+	params := request.QueryStringParameters
+
+	// パスパラメータから画像IDを取得
+	historyRequest := dto.ImageTagHistoryRequestDTO{
+		ImageID: request.PathParameters["imageId"],
+		Cursor:  params["cursor"],
+	}
+	if historyRequest.ImageID == "" {
+		return h.errorResponse(400, "画像IDが指定されていません")
+	}
+
+	// 件数の上限
+	if value := params["limit"]; value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return h.errorResponse(400, usecase.ErrInvalidHistoryLimit.Error())
+		}
+		historyRequest.Limit = limit
+	}
+
+	// 変更履歴を取得
+	response, err := h.tagHistoryUsecase.GetImageHistory(ctx, &historyRequest)
+	if err != nil {
+		if errors.Is(err, usecase.ErrImageNotFound) {
+			return h.errorResponse(404, "指定された画像が見つかりません")
+		}
+		if errors.Is(err, usecase.ErrInvalidHistoryLimit) || errors.Is(err, usecase.ErrInvalidHistoryCursor) {
+			return h.errorResponse(400, err.Error())
+		}
+		logger.Error(err, "Error getting image tag history", map[string]interface{}{
+			"imageId": historyRequest.ImageID,
+		})
+		return h.errorResponse(500, "タグの変更履歴の取得に失敗しました")
+	}
+
+	// レスポンスをJSON形式で返す
+	return h.jsonResponse(200, response)
+}
+
+// queryHistory は期間内のすべての画像のタグの変更履歴を新しい順に取得する（管理者のみ）
+// from・to で期間（RFC3339形式または日付）、tag でタグ、actor でユーザーに絞り込み、limit・cursor でページングできる
+func (h *TagHandler) queryHistory(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+	params := request.QueryStringParameters
+
+	if _, errResponse, ok := h.requireAdmin(ctx); !ok {
+		return errResponse, nil
+	}
+
+	queryRequest := dto.TagHistoryQueryRequestDTO{
+		From:    params["from"],
+		To:      params["to"],
+		Tag:     params["tag"],
+		ActorID: params["actor"],
+		Cursor:  params["cursor"],
+	}
+
+	// 件数の上限
+	if value := params["limit"]; value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return h.errorResponse(400, usecase.ErrInvalidHistoryLimit.Error())
+		}
+		queryRequest.Limit = limit
+	}
+
+	// 変更履歴を検索
+	response, err := h.tagHistoryUsecase.QueryHistory(ctx, &queryRequest)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidTag) {
+			return h.errorResponse(400, "無効なタグ形式です")
+		}
+		if errors.Is(err, usecase.ErrInvalidHistoryLimit) || errors.Is(err, usecase.ErrInvalidHistoryRange) ||
+			errors.Is(err, usecase.ErrInvalidHistoryCursor) {
+			return h.errorResponse(400, err.Error())
+		}
+		logger.Error(err, "Error querying tag history", map[string]interface{}{
+			"from": queryRequest.From,
+			"to":   queryRequest.To,
+			"tag":  queryRequest.Tag,
+		})
+		return h.errorResponse(500, "タグの変更履歴の取得に失敗しました")
+	}
+
+	// レスポンスをJSON形式で返す
+	return h.jsonResponse(200, response)
+}
+
+// actorID は変更履歴に記録する認証ユーザーのIDを返す（認証情報がない場合は空）
+func (h *TagHandler) actorID(ctx context.Context) string {
+	user, ok := contextutil.GetUserInfo(ctx)
+	if !ok || user == nil {
+		return ""
+	}
+	return user.ID.String()
+}
+
 // jsonResponse はJSON形式のレスポンスを作成する
 func (h *TagHandler) jsonResponse(statusCode int, body interface{}) (events.APIGatewayProxyResponse, error) {
 	responseJSON, err := json.Marshal(body)
@@ -188,6 +647,14 @@ func (h *TagHandler) jsonResponse(statusCode int, body interface{}) (events.APIG
 	}, nil
 }
 
+// tagValidationErrorResponse は厳格モードで拒否したリクエストのエラーレスポンスをタグごとの検証結果とともに作成する
+func (h *TagHandler) tagValidationErrorResponse(err *usecase.TagValidationError) (events.APIGatewayProxyResponse, error) {
+	return h.jsonResponse(400, map[string]interface{}{
+		"error":   err.Error(),
+		"results": err.Results,
+	})
+}
+
 // errorResponse はエラーレスポンスを作成する
 func (h *TagHandler) errorResponse(statusCode int, message string) (events.APIGatewayProxyResponse, error) {
 	body, _ := json.Marshal(map[string]string{"error": message})
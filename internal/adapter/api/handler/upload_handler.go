@@ -3,9 +3,11 @@ package handler
 import (
 	"cloudpix/internal/application/imagemanagement/dto"
 	"cloudpix/internal/application/imagemanagement/usecase"
+	"cloudpix/internal/contextutil"
 	"cloudpix/internal/logging"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -34,9 +36,23 @@ func (h *UploadHandler) Handle(ctx context.Context, event events.APIGatewayProxy
 		return h.createErrorResponse(http.StatusBadRequest, "不正なリクエスト形式")
 	}
 
+	// 認証済みユーザーを所有者として設定
+	if user, ok := contextutil.GetUserInfo(ctx); ok && user != nil {
+		request.Owner = user.ID.String()
+		for _, role := range user.Roles {
+			request.OwnerRoles = append(request.OwnerRoles, string(role))
+		}
+	}
+
 	// アップロード処理実行
 	response, err := h.uploadUsecase.ProcessUpload(ctx, &request)
 	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrImageTooLarge):
+			return h.createErrorResponse(http.StatusRequestEntityTooLarge, err.Error())
+		case errors.Is(err, usecase.ErrInvalidImageData):
+			return h.createErrorResponse(http.StatusBadRequest, err.Error())
+		}
 		logger.Error(err, "Upload error", nil)
 		return h.createErrorResponse(http.StatusInternalServerError, "アップロード処理中にエラーが発生しました")
 	}
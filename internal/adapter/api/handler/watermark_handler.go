@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"cloudpix/internal/application/thumbnailmanagement/dto"
+	"cloudpix/internal/application/thumbnailmanagement/usecase"
+	"cloudpix/internal/contextutil"
+	"cloudpix/internal/logging"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// WatermarkHandler は透かし設定を処理するハンドラー
+type WatermarkHandler struct {
+	watermarkUsecase *usecase.WatermarkUsecase
+}
+
+// NewWatermarkHandler は新しいWatermarkHandlerを作成します
+func NewWatermarkHandler(watermarkUsecase *usecase.WatermarkUsecase) *WatermarkHandler {
+	return &WatermarkHandler{
+		watermarkUsecase: watermarkUsecase,
+	}
+}
+
+// Handle はAPIリクエストを処理します
+func (h *WatermarkHandler) Handle(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := logging.FromContext(ctx)
+	logger.Info("Processing watermark request", map[string]interface{}{
+		"method": request.HTTPMethod,
+		"path":   request.Path,
+	})
+
+	user, ok := contextutil.GetUserInfo(ctx)
+	if !ok || user == nil {
+		return h.errorResponse(http.StatusUnauthorized, "認証が必要です")
+	}
+
+	// パスとメソッドに基づいてルーティング
+	switch request.Resource {
+	case "/watermark":
+		switch request.HTTPMethod {
+		case "GET":
+			setting, err := h.watermarkUsecase.GetUserSetting(ctx, user.ID.String())
+			return h.settingResponse(ctx, setting, err)
+		case "PUT":
+			watermarkRequest, err := h.parseRequest(request)
+			if err != nil {
+				return h.errorResponse(http.StatusBadRequest, "無効なリクエスト形式です")
+			}
+			setting, err := h.watermarkUsecase.PutUserSetting(ctx, user.ID.String(), watermarkRequest)
+			return h.settingResponse(ctx, setting, err)
+		case "DELETE":
+			err := h.watermarkUsecase.DeleteUserSetting(ctx, user.ID.String())
+			return h.deletedResponse(ctx, err)
+		}
+	case "/watermark/default":
+		if request.HTTPMethod == "GET" {
+			setting, err := h.watermarkUsecase.GetDefaultSetting(ctx)
+			return h.settingResponse(ctx, setting, err)
+		}
+
+		// デフォルト設定の変更は管理者のみ
+		if !user.IsAdmin() {
+			return h.errorResponse(http.StatusForbidden, "管理者のみ操作できます")
+		}
+		switch request.HTTPMethod {
+		case "PUT":
+			watermarkRequest, err := h.parseRequest(request)
+			if err != nil {
+				return h.errorResponse(http.StatusBadRequest, "無効なリクエスト形式です")
+			}
+			setting, err := h.watermarkUsecase.PutDefaultSetting(ctx, watermarkRequest)
+			return h.settingResponse(ctx, setting, err)
+		case "DELETE":
+			err := h.watermarkUsecase.DeleteDefaultSetting(ctx)
+			return h.deletedResponse(ctx, err)
+		}
+	}
+
+	// 未対応のパス・メソッド
+	return h.errorResponse(http.StatusNotFound, "Not Found")
+}
+
+// parseRequest はリクエストボディから透かし設定を取得します
+func (h *WatermarkHandler) parseRequest(request events.APIGatewayProxyRequest) (*dto.WatermarkRequestDTO, error) {
+	var watermarkRequest dto.WatermarkRequestDTO
+	if err := json.Unmarshal([]byte(request.Body), &watermarkRequest); err != nil {
+		return nil, err
+	}
+	return &watermarkRequest, nil
+}
+
+// settingResponse はユースケースの結果をレスポンスに変換します
+func (h *WatermarkHandler) settingResponse(ctx context.Context, setting *dto.WatermarkSettingDTO, err error) (events.APIGatewayProxyResponse, error) {
+	if err != nil {
+		return h.usecaseErrorResponse(ctx, err)
+	}
+	return h.jsonResponse(http.StatusOK, setting)
+}
+
+// deletedResponse は削除の結果をレスポンスに変換します
+func (h *WatermarkHandler) deletedResponse(ctx context.Context, err error) (events.APIGatewayProxyResponse, error) {
+	if err != nil {
+		return h.usecaseErrorResponse(ctx, err)
+	}
+	return events.APIGatewayProxyResponse{StatusCode: http.StatusNoContent}, nil
+}
+
+// usecaseErrorResponse はユースケースのエラーをステータスコードに対応付けます
+func (h *WatermarkHandler) usecaseErrorResponse(ctx context.Context, err error) (events.APIGatewayProxyResponse, error) {
+	switch {
+	case errors.Is(err, usecase.ErrWatermarkNotFound):
+		return h.errorResponse(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrInvalidWatermark),
+		errors.Is(err, usecase.ErrUnknownRendition),
+		errors.Is(err, usecase.ErrWatermarkImageNotFound):
+		return h.errorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	logging.FromContext(ctx).Error(err, "Error processing watermark setting", nil)
+	return h.errorResponse(http.StatusInternalServerError, "透かし設定の処理に失敗しました")
+}
+
+// jsonResponse はJSON形式のレスポンスを作成します
+func (h *WatermarkHandler) jsonResponse(statusCode int, body interface{}) (events.APIGatewayProxyResponse, error) {
+	responseJSON, err := json.Marshal(body)
+	if err != nil {
+		return h.errorResponse(http.StatusInternalServerError, "レスポンス生成中にエラーが発生しました")
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: statusCode,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Body: string(responseJSON),
+	}, nil
+}
+
+// errorResponse はエラーレスポンスを作成します
+func (h *WatermarkHandler) errorResponse(statusCode int, message string) (events.APIGatewayProxyResponse, error) {
+	body, _ := json.Marshal(map[string]string{"error": message})
+	return events.APIGatewayProxyResponse{
+		StatusCode: statusCode,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Body: string(body),
+	}, nil
+}
@@ -43,7 +43,7 @@ func (h *ThumbnailHandler) Handle(ctx context.Context, s3Event events.S3Event) e
 				"bucket": bucket,
 				"key":    key,
 			})
-			// 次の画像の処理を続行
+			// 失敗は処理状態に記録され、スケジュールされた再試行で再処理されるため次の画像の処理を続行
 			continue
 		}
 
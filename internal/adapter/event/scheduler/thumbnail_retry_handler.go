@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"cloudpix/internal/application/thumbnailmanagement/usecase"
+	"cloudpix/internal/logging"
+	"context"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// ThumbnailRetryHandler はスケジュールされたサムネイル生成の再試行イベントを処理するハンドラー
+type ThumbnailRetryHandler struct {
+	retryUsecase *usecase.ThumbnailRetryUsecase
+	logger       logging.Logger
+}
+
+// NewThumbnailRetryHandler は新しい再試行ハンドラーを作成します
+func NewThumbnailRetryHandler(retryUsecase *usecase.ThumbnailRetryUsecase, logger logging.Logger) *ThumbnailRetryHandler {
+	return &ThumbnailRetryHandler{
+		retryUsecase: retryUsecase,
+		logger:       logger,
+	}
+}
+
+// Handle はEventBridgeスケジュールイベントを処理します
+func (h *ThumbnailRetryHandler) Handle(ctx context.Context, event events.CloudWatchEvent) error {
+	startTime := time.Now()
+	h.logger.Info("Thumbnail retry started", map[string]interface{}{
+		"event":     event.Source,
+		"eventTime": event.Time.String(),
+	})
+
+	// 再試行を実行
+	report, err := h.retryUsecase.RetryFailed(ctx)
+	if err != nil {
+		h.logger.Error(err, "Thumbnail retry failed", map[string]interface{}{
+			"duration": time.Since(startTime).Milliseconds(),
+		})
+		return err
+	}
+
+	// 結果ログの記録
+	h.logger.Info("Thumbnail retry completed", map[string]interface{}{
+		"duration":     time.Since(startTime).Milliseconds(),
+		"due":          report.Due,
+		"succeeded":    report.Succeeded,
+		"failed":       report.Failed,
+		"deadLettered": report.DeadLettered,
+	})
+	return nil
+}
@@ -0,0 +1,41 @@
+package dto
+
+// ExpandField はレスポンスに含める追加フィールドの種類を表します
+type ExpandField string
+
+// 展開できるフィールド
+const (
+	ExpandThumbnail   ExpandField = "thumbnail"   // 代表サムネイルのURLとサイズ
+	ExpandRenditions  ExpandField = "renditions"  // 全レンディション
+	ExpandPlaceholder ExpandField = "placeholder" // BlurHashとLQIP
+	ExpandPalette     ExpandField = "palette"     // 代表色
+	ExpandStatus      ExpandField = "status"      // サムネイル生成の処理状態
+	ExpandTags        ExpandField = "tags"        // タグ（タグテーブルの読み込みが必要）
+)
+
+// ExpandFields はすべての展開できるフィールド
+var ExpandFields = []ExpandField{
+	ExpandThumbnail,
+	ExpandRenditions,
+	ExpandPlaceholder,
+	ExpandPalette,
+	ExpandStatus,
+	ExpandTags,
+}
+
+// Expand はレスポンスに含める追加フィールドの集合を表します
+type Expand map[ExpandField]bool
+
+// ExpandAll はすべての追加フィールドを含む集合を返します
+func ExpandAll() Expand {
+	expand := make(Expand, len(ExpandFields))
+	for _, field := range ExpandFields {
+		expand[field] = true
+	}
+	return expand
+}
+
+// Has は指定されたフィールドを含むかを判定します
+func (e Expand) Has(field ExpandField) bool {
+	return e[field]
+}
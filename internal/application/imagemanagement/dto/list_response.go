@@ -1,18 +1,60 @@
 package dto
 
+// RenditionDTO はサムネイルレンディションのデータ転送オブジェクト
+type RenditionDTO struct {
+	Name        string      `json:"name"`
+	URL         string      `json:"url"`
+	Width       int         `json:"width"`
+	Height      int         `json:"height"`
+	ContentType string      `json:"contentType"`
+	Crop        *CropBoxDTO `json:"crop,omitempty"`
+}
+
+// CropBoxDTO はレンディションの生成に使用した元画像の領域のデータ転送オブジェクト
+type CropBoxDTO struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// PaletteColorDTO は画像の代表色のデータ転送オブジェクト
+type PaletteColorDTO struct {
+	Color  string  `json:"color"`
+	Weight float64 `json:"weight"`
+}
+
+// ThumbnailStatusDTO はサムネイル生成の処理状態のデータ転送オブジェクト
+type ThumbnailStatusDTO struct {
+	Status      string `json:"status"` // PENDING, SUCCEEDED, FAILED, DEAD_LETTER
+	Attempts    int    `json:"attempts"`
+	LastError   string `json:"lastError,omitempty"`
+	NextRetryAt string `json:"nextRetryAt,omitempty"`
+	UpdatedAt   string `json:"updatedAt,omitempty"`
+}
+
 // ImageMetadataDTO は画像メタデータのデータ転送オブジェクト
 type ImageMetadataDTO struct {
-	ImageID      string `json:"imageId"`
-	FileName     string `json:"fileName"`
-	ContentType  string `json:"contentType"`
-	Size         int    `json:"size"`
-	UploadDate   string `json:"uploadDate"`
-	DownloadURL  string `json:"downloadUrl"`
-	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
+	ImageID         string              `json:"imageId"`
+	FileName        string              `json:"fileName"`
+	ContentType     string              `json:"contentType"`
+	Size            int                 `json:"size"`
+	UploadDate      string              `json:"uploadDate"`
+	DownloadURL     string              `json:"downloadUrl"`
+	ThumbnailURL    string              `json:"thumbnailUrl,omitempty"`
+	ThumbnailWidth  int                 `json:"thumbnailWidth,omitempty"`
+	ThumbnailHeight int                 `json:"thumbnailHeight,omitempty"`
+	BlurHash        string              `json:"blurHash,omitempty"`
+	LQIP            string              `json:"lqip,omitempty"` // data URI形式の極小画像
+	Renditions      []RenditionDTO      `json:"renditions,omitempty"`
+	Palette         []PaletteColorDTO   `json:"palette,omitempty"`
+	Processing      *ThumbnailStatusDTO `json:"thumbnailStatus,omitempty"`
+	Tags            []string            `json:"tags,omitempty"`
 }
 
 // ListResponse は画像一覧のレスポンスを表します
 type ListResponse struct {
-	Images []ImageMetadataDTO `json:"images"`
-	Count  int                `json:"count"`
+	Images     []ImageMetadataDTO `json:"images"`
+	Count      int                `json:"count"`
+	NextCursor string             `json:"nextCursor,omitempty"` // 続きを検索する位置（ページ単位で検索した場合のみ）
 }
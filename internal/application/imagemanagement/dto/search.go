@@ -0,0 +1,29 @@
+package dto
+
+// SearchMode は指定された複数のタグの組み合わせ方を表します
+type SearchMode string
+
+// タグの組み合わせ方
+const (
+	SearchModeAll SearchMode = "all" // すべてのタグを持つ画像（AND）
+	SearchModeAny SearchMode = "any" // いずれかのタグを持つ画像（OR）
+)
+
+// SearchRequest はタグによる画像検索のリクエストを表します
+type SearchRequest struct {
+	Tags        []string   // 検索するタグ
+	Exclude     []string   // 除外するタグ（NOT）
+	Mode        SearchMode // 空の場合は SearchModeAll
+	Date        string     // アップロード日（YYYY-MM-DD）での絞り込み
+	ContentType string     // コンテンツタイプでの絞り込み
+	Limit       int        // 0の場合はデフォルト件数
+	Cursor      string     // 前のページの NextCursor
+	Expand      Expand
+}
+
+// SearchResponse はタグによる画像検索のレスポンスを表します
+type SearchResponse struct {
+	Images     []ImageMetadataDTO `json:"images"`
+	Count      int                `json:"count"`
+	NextCursor string             `json:"nextCursor,omitempty"` // 次のページがない場合は空
+}
@@ -0,0 +1,28 @@
+package dto
+
+// SimilarImageDTO は類似画像とそのハミング距離のデータ転送オブジェクト
+type SimilarImageDTO struct {
+	ImageMetadataDTO
+	Distance int `json:"distance"`
+}
+
+// SimilarImagesResponse は類似画像検索のレスポンスを表します
+type SimilarImagesResponse struct {
+	ImageID     string            `json:"imageId"`
+	MaxDistance int               `json:"maxDistance"`
+	Images      []SimilarImageDTO `json:"images"`
+	Count       int               `json:"count"`
+}
+
+// DuplicateClusterDTO は重複の可能性が高い画像のまとまりを表します
+type DuplicateClusterDTO struct {
+	Images      []ImageMetadataDTO `json:"images"`
+	MaxDistance int                `json:"maxDistance"`
+}
+
+// DuplicateReportResponse は重複画像レポートのレスポンスを表します
+type DuplicateReportResponse struct {
+	MaxDistance int                   `json:"maxDistance"`
+	Clusters    []DuplicateClusterDTO `json:"clusters"`
+	Count       int                   `json:"count"`
+}
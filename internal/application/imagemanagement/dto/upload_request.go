@@ -2,9 +2,11 @@ package dto
 
 // UploadRequest はクライアントからのアップロードリクエストを表します
 type UploadRequest struct {
-	FileName    string `json:"fileName"`
-	ContentType string `json:"contentType"`
-	Data        string `json:"data,omitempty"` // Base64エンコードされた画像データ
+	FileName    string   `json:"fileName"`
+	ContentType string   `json:"contentType"`
+	Data        string   `json:"data,omitempty"` // Base64エンコードされた画像データ
+	Owner       string   `json:"-"`              // 認証済みユーザーのID（ハンドラーで設定）
+	OwnerRoles  []string `json:"-"`              // 認証済みユーザーのロール（ハンドラーで設定）
 }
 
 // UploadResponse はアップロード操作のレスポンスを表します
@@ -68,16 +68,16 @@ func (u *CleanupUsecase) ProcessCleanup(ctx context.Context) error {
 
 	// 各画像を処理
 	for _, image := range oldImages {
-		err := u.cleanupService.ArchiveImage(ctx, image.ID)
+		err := u.cleanupService.ArchiveImage(ctx, image.GetImageID())
 		if err != nil {
 			u.logger.Error(err, "Failed to archive image", map[string]interface{}{
-				"imageId": image.ID,
+				"imageId": image.GetImageID(),
 			})
 			continue
 		}
 
 		u.logger.Info("Successfully archived image", map[string]interface{}{
-			"imageId": image.ID,
+			"imageId": image.GetImageID(),
 		})
 	}
 
@@ -2,25 +2,102 @@ package usecase
 
 import (
 	"cloudpix/internal/application/imagemanagement/dto"
+	"cloudpix/internal/domain/imagemanagement/aggregate"
 	"cloudpix/internal/domain/imagemanagement/repository"
 	"cloudpix/internal/domain/imagemanagement/valueobject"
+	tagrepository "cloudpix/internal/domain/tagmanagement/repository"
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 )
 
+const (
+	// DefaultColorTolerance はデフォルトの色の許容差（CIELABでの距離）
+	DefaultColorTolerance = 20.0
+	// MaxColorTolerance は指定できる色の許容差の上限
+	MaxColorTolerance = 100.0
+	// 色検索の対象とする代表色の最小占有率（画像のごく一部にしかない色は無視する）
+	minPaletteWeight = 0.1
+	// 日付を指定しない色検索で1回に走査する画像数
+	colorScanPageSize = 100
+	// 日付を指定しない色検索で1リクエストに走査する画像数の上限
+	maxColorScanImages = 1000
+	// 日付を指定しない色検索でこの件数の画像が見つかった時点で走査を止める
+	colorSearchLimit = 50
+)
+
+var (
+	// ErrImageNotFound は指定された画像が存在しない場合のエラー
+	ErrImageNotFound = errors.New("指定された画像が見つかりません")
+	// ErrInvalidColor は検索する色の形式が不正な場合のエラー
+	ErrInvalidColor = errors.New("色は #RRGGBB 形式で指定してください")
+	// ErrInvalidTolerance は色の許容差が範囲外の場合のエラー
+	ErrInvalidTolerance = fmt.Errorf("許容差は0から%gの範囲である必要があります", MaxColorTolerance)
+	// ErrInvalidExpand は展開するフィールドの指定が不正な場合のエラー
+	ErrInvalidExpand = errors.New("expand には thumbnail, renditions, placeholder, palette, status, tags, none をカンマ区切りで指定してください")
+)
+
+// expandNone は追加フィールドを含めないことを表す expand の値
+const expandNone = "none"
+
 // ListUsecase は画像一覧取得のユースケースを実装します
 type ListUsecase struct {
-	imageRepository repository.ImageRepository
+	imageRepository    repository.ImageRepository
+	tagRepository      tagrepository.TagRepository
+	tagAliasRepository tagrepository.TagAliasRepository
 }
 
 // NewListUsecase は新しい一覧取得ユースケースを作成します
-func NewListUsecase(imageRepository repository.ImageRepository) *ListUsecase {
+func NewListUsecase(
+	imageRepository repository.ImageRepository,
+	tagRepository tagrepository.TagRepository,
+	tagAliasRepository tagrepository.TagAliasRepository,
+) *ListUsecase {
 	return &ListUsecase{
-		imageRepository: imageRepository,
+		imageRepository:    imageRepository,
+		tagRepository:      tagRepository,
+		tagAliasRepository: tagAliasRepository,
+	}
+}
+
+// ParseExpand はカンマ区切りの expand パラメータを解析します
+// 指定がない場合はすべての追加フィールドを含め、none の場合は基本のフィールドのみを返します
+func ParseExpand(value string) (dto.Expand, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return dto.ExpandAll(), nil
 	}
+
+	expand := make(dto.Expand)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == expandNone {
+			continue
+		}
+		field, ok := findExpandField(name)
+		if !ok {
+			return nil, ErrInvalidExpand
+		}
+		expand[field] = true
+	}
+	return expand, nil
+}
+
+// findExpandField は名前に対応する展開フィールドを探します
+func findExpandField(name string) (dto.ExpandField, bool) {
+	for _, field := range dto.ExpandFields {
+		if string(field) == name {
+			return field, true
+		}
+	}
+	return "", false
 }
 
 // List はすべての画像を取得します
-func (u *ListUsecase) List(ctx context.Context) (*dto.ListResponse, error) {
+func (u *ListUsecase) List(ctx context.Context, expand dto.Expand) (*dto.ListResponse, error) {
 	options := repository.ImageQueryOptions{
 		Limit: 100, // デフォルト上限
 	}
@@ -31,27 +108,11 @@ func (u *ListUsecase) List(ctx context.Context) (*dto.ListResponse, error) {
 		return nil, err
 	}
 
-	// エンティティをDTOに変換
-	imagesDTO := make([]dto.ImageMetadataDTO, len(images))
-	for i, img := range images {
-		imagesDTO[i] = dto.ImageMetadataDTO{
-			ImageID:     img.ID,
-			FileName:    img.FileName.String(),
-			ContentType: img.ContentType.String(),
-			Size:        img.Size.Value(),
-			UploadDate:  img.UploadDate.String(),
-			DownloadURL: img.DownloadURL,
-		}
-	}
-
-	return &dto.ListResponse{
-		Images: imagesDTO,
-		Count:  len(imagesDTO),
-	}, nil
+	return u.toListResponse(ctx, images, expand)
 }
 
 // ListByDate は指定された日付の画像を取得します
-func (u *ListUsecase) ListByDate(ctx context.Context, dateStr string) (*dto.ListResponse, error) {
+func (u *ListUsecase) ListByDate(ctx context.Context, dateStr string, expand dto.Expand) (*dto.ListResponse, error) {
 	// 日付の値オブジェクトを作成
 	date, err := valueobject.NewUploadDate(dateStr)
 	if err != nil {
@@ -64,16 +125,94 @@ func (u *ListUsecase) ListByDate(ctx context.Context, dateStr string) (*dto.List
 		return nil, err
 	}
 
-	// エンティティをDTOに変換
+	return u.toListResponse(ctx, images, expand)
+}
+
+// ListByColor は代表色が指定された色に近い画像を近い順に取得します
+// 日付が指定されている場合はその日付の画像に絞り込み、指定されていない場合はカーソルの位置から全画像をページ単位で走査します
+// 走査は一定数の画像が見つかるか走査数の上限に達した時点で止め、続きの位置を NextCursor として返します（並びは返した範囲内での近い順）
+func (u *ListUsecase) ListByColor(ctx context.Context, dateStr, colorStr string, tolerance float64, cursor string, expand dto.Expand) (*dto.ListResponse, error) {
+	target, err := valueobject.ParseHexColor(colorStr)
+	if err != nil {
+		return nil, ErrInvalidColor
+	}
+	if !(tolerance >= 0 && tolerance <= MaxColorTolerance) {
+		return nil, ErrInvalidTolerance
+	}
+
+	// 許容差以内の代表色を持つ画像を抽出
+	matches := make([]colorMatch, 0)
+	nextCursor := ""
+	if dateStr != "" {
+		date, err := valueobject.NewUploadDate(dateStr)
+		if err != nil {
+			return nil, err
+		}
+		images, err := u.imageRepository.FindByDate(ctx, date)
+		if err != nil {
+			return nil, err
+		}
+		matches = appendColorMatches(matches, images, target, tolerance)
+	} else {
+		scanned := 0
+		for {
+			page, err := u.imageRepository.FindPage(ctx, cursor, colorScanPageSize)
+			if err != nil {
+				return nil, err
+			}
+			scanned += len(page.Images)
+			matches = appendColorMatches(matches, page.Images, target, tolerance)
+
+			cursor = page.NextCursor
+			if cursor == "" || len(matches) >= colorSearchLimit || scanned >= maxColorScanImages {
+				break
+			}
+		}
+		nextCursor = cursor
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].distance < matches[j].distance })
+
+	sortedImages := make([]*aggregate.ImageAggregate, len(matches))
+	for i, match := range matches {
+		sortedImages[i] = match.image
+	}
+
+	response, err := u.toListResponse(ctx, sortedImages, expand)
+	if err != nil {
+		return nil, err
+	}
+	response.NextCursor = nextCursor
+	return response, nil
+}
+
+// colorMatch は色検索に一致した画像と代表色の距離
+type colorMatch struct {
+	image    *aggregate.ImageAggregate
+	distance float64
+}
+
+// appendColorMatches は許容差以内の代表色を持つ画像を一致した画像に追加します
+func appendColorMatches(matches []colorMatch, images []*aggregate.ImageAggregate, target valueobject.Color, tolerance float64) []colorMatch {
+	for _, img := range images {
+		if distance, ok := closestPaletteDistance(img.Palette, target); ok && distance <= tolerance {
+			matches = append(matches, colorMatch{image: img, distance: distance})
+		}
+	}
+	return matches
+}
+
+// toListResponse は画像集約を一覧のレスポンスに変換します
+// タグを含める場合は全画像のタグをまとめて読み込みます
+func (u *ListUsecase) toListResponse(ctx context.Context, images []*aggregate.ImageAggregate, expand dto.Expand) (*dto.ListResponse, error) {
+	// 集約をDTOに変換
 	imagesDTO := make([]dto.ImageMetadataDTO, len(images))
 	for i, img := range images {
-		imagesDTO[i] = dto.ImageMetadataDTO{
-			ImageID:     img.ID,
-			FileName:    img.FileName.String(),
-			ContentType: img.ContentType.String(),
-			Size:        img.Size.Value(),
-			UploadDate:  img.UploadDate.String(),
-			DownloadURL: img.DownloadURL,
+		imagesDTO[i] = toImageMetadataDTO(img, expand)
+	}
+
+	if expand.Has(dto.ExpandTags) {
+		if err := u.attachTags(ctx, imagesDTO); err != nil {
+			return nil, err
 		}
 	}
 
@@ -82,3 +221,155 @@ func (u *ListUsecase) ListByDate(ctx context.Context, dateStr string) (*dto.List
 		Count:  len(imagesDTO),
 	}, nil
 }
+
+// attachTags は画像のタグを画像ごとに問い合わせずにまとめて取得して設定します
+func (u *ListUsecase) attachTags(ctx context.Context, images []dto.ImageMetadataDTO) error {
+	if len(images) == 0 {
+		return nil
+	}
+
+	imageIDs := make([]string, len(images))
+	for i, image := range images {
+		imageIDs[i] = image.ImageID
+	}
+
+	tagsByImage, err := u.tagRepository.FindTagsByImageIDs(ctx, imageIDs)
+	if err != nil {
+		return fmt.Errorf("failed to find tags: %w", err)
+	}
+
+	for i := range images {
+		tags := tagsByImage[images[i].ImageID]
+		if len(tags) == 0 {
+			continue
+		}
+		tagNames := make([]string, len(tags))
+		for j, tag := range tags {
+			tagNames[j] = tag.Name()
+		}
+		sort.Strings(tagNames)
+		images[i].Tags = tagNames
+	}
+	return nil
+}
+
+// closestPaletteDistance は代表色のうち指定された色に最も近いもののCIELABでの距離を返します
+func closestPaletteDistance(palette []aggregate.PaletteColor, target valueobject.Color) (float64, bool) {
+	closest, found := 0.0, false
+	for _, paletteColor := range palette {
+		if paletteColor.Weight < minPaletteWeight {
+			continue
+		}
+		parsed, err := valueobject.ParseHexColor(paletteColor.Color)
+		if err != nil {
+			continue
+		}
+		if distance := parsed.DeltaE(target); !found || distance < closest {
+			closest, found = distance, true
+		}
+	}
+	return closest, found
+}
+
+// GetImage は指定されたIDの画像詳細を取得します
+func (u *ListUsecase) GetImage(ctx context.Context, imageID string, expand dto.Expand) (*dto.ImageMetadataDTO, error) {
+	// 画像の存在チェック
+	exists, err := u.imageRepository.Exists(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, ErrImageNotFound
+	}
+
+	// リポジトリから画像集約を取得
+	imageAggregate, err := u.imageRepository.FindByID(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := u.toListResponse(ctx, []*aggregate.ImageAggregate{imageAggregate}, expand)
+	if err != nil {
+		return nil, err
+	}
+	return &response.Images[0], nil
+}
+
+// toImageMetadataDTO は画像集約をDTOに変換します
+// 追加フィールドは expand に含まれるもののみ設定します（タグは attachTags で設定）
+func toImageMetadataDTO(imageAggregate *aggregate.ImageAggregate, expand dto.Expand) dto.ImageMetadataDTO {
+	img := imageAggregate.Image
+
+	imageDTO := dto.ImageMetadataDTO{
+		ImageID:     img.ID,
+		FileName:    img.FileName.String(),
+		ContentType: img.ContentType.String(),
+		Size:        img.Size.Value(),
+		UploadDate:  img.UploadDate.String(),
+		DownloadURL: img.DownloadURL,
+	}
+
+	// 代表サムネイルを設定
+	if expand.Has(dto.ExpandThumbnail) {
+		imageDTO.ThumbnailURL = imageAggregate.ThumbnailURL
+		imageDTO.ThumbnailWidth = imageAggregate.ThumbnailWidth
+		imageDTO.ThumbnailHeight = imageAggregate.ThumbnailHeight
+	}
+
+	// プレースホルダーを設定
+	if expand.Has(dto.ExpandPlaceholder) {
+		imageDTO.BlurHash = imageAggregate.BlurHash
+		imageDTO.LQIP = imageAggregate.LQIP
+	}
+
+	// サムネイルのレンディションを設定
+	if expand.Has(dto.ExpandRenditions) {
+		for _, rendition := range imageAggregate.Renditions {
+			renditionDTO := dto.RenditionDTO{
+				Name:        rendition.Name,
+				URL:         rendition.URL,
+				Width:       rendition.Width,
+				Height:      rendition.Height,
+				ContentType: rendition.ContentType,
+			}
+			if rendition.Crop != nil {
+				renditionDTO.Crop = &dto.CropBoxDTO{
+					X:      rendition.Crop.X,
+					Y:      rendition.Crop.Y,
+					Width:  rendition.Crop.Width,
+					Height: rendition.Crop.Height,
+				}
+			}
+			imageDTO.Renditions = append(imageDTO.Renditions, renditionDTO)
+		}
+	}
+
+	// 代表色を設定
+	if expand.Has(dto.ExpandPalette) {
+		for _, paletteColor := range imageAggregate.Palette {
+			imageDTO.Palette = append(imageDTO.Palette, dto.PaletteColorDTO{
+				Color:  paletteColor.Color,
+				Weight: paletteColor.Weight,
+			})
+		}
+	}
+
+	// サムネイル生成の処理状態を設定
+	if processing := imageAggregate.Processing; processing != nil && expand.Has(dto.ExpandStatus) {
+		statusDTO := &dto.ThumbnailStatusDTO{
+			Status:    processing.Status,
+			Attempts:  processing.Attempts,
+			LastError: processing.LastError,
+		}
+		if !processing.NextRetryAt.IsZero() {
+			statusDTO.NextRetryAt = processing.NextRetryAt.Format(time.RFC3339)
+		}
+		if !processing.UpdatedAt.IsZero() {
+			statusDTO.UpdatedAt = processing.UpdatedAt.Format(time.RFC3339)
+		}
+		imageDTO.Processing = statusDTO
+	}
+
+	return imageDTO
+}
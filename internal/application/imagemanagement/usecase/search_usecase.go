@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"cloudpix/internal/application/imagemanagement/dto"
+	"cloudpix/internal/domain/imagemanagement/aggregate"
+	"cloudpix/internal/domain/imagemanagement/valueobject"
+	tagvalueobject "cloudpix/internal/domain/tagmanagement/valueobject"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultSearchLimit はタグ検索で1ページに返すデフォルトの件数
+	DefaultSearchLimit = 50
+	// MaxSearchLimit はタグ検索で1ページに返す件数の上限
+	MaxSearchLimit = 100
+	// 検索結果の候補をまとめて読み込む件数
+	searchBatchSize = 100
+)
+
+var (
+	// ErrSearchTagsRequired は検索するタグが指定されていない場合のエラー
+	ErrSearchTagsRequired = errors.New("検索するタグを1つ以上指定してください")
+	// ErrInvalidSearchTag は検索するタグの形式が不正な場合のエラー
+	ErrInvalidSearchTag = errors.New("無効なタグ形式です")
+	// ErrInvalidSearchMode はタグの組み合わせ方の指定が不正な場合のエラー
+	ErrInvalidSearchMode = errors.New("mode には all または any を指定してください")
+	// ErrInvalidSearchDate は絞り込む日付の形式が不正な場合のエラー
+	ErrInvalidSearchDate = errors.New("日付はYYYY-MM-DD形式で指定してください")
+	// ErrInvalidSearchLimit は件数の指定が範囲外の場合のエラー
+	ErrInvalidSearchLimit = fmt.Errorf("件数は1から%dの範囲である必要があります", MaxSearchLimit)
+)
+
+// Search はタグの組み合わせ（AND・OR・NOT）で画像を検索し、日付とコンテンツタイプで絞り込んで1ページ分返します
+// 各タグは正規のタグとその別名に展開され、いずれかが付いた画像に一致します
+// 結果は画像IDの順に並び、カーソルには最後に返した画像IDを使用します
+func (u *ListUsecase) Search(ctx context.Context, request dto.SearchRequest) (*dto.SearchResponse, error) {
+	include, err := parseSearchTags(request.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(include) == 0 {
+		return nil, ErrSearchTagsRequired
+	}
+	exclude, err := parseSearchTags(request.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := request.Mode
+	if mode == "" {
+		mode = dto.SearchModeAll
+	}
+	if mode != dto.SearchModeAll && mode != dto.SearchModeAny {
+		return nil, ErrInvalidSearchMode
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit < 1 || limit > MaxSearchLimit {
+		return nil, ErrInvalidSearchLimit
+	}
+
+	var date string
+	if request.Date != "" {
+		uploadDate, err := valueobject.NewUploadDate(request.Date)
+		if err != nil {
+			return nil, ErrInvalidSearchDate
+		}
+		date = uploadDate.String()
+	}
+	contentType := strings.ToLower(strings.TrimSpace(request.ContentType))
+
+	// 別名を正規のタグにまとめる
+	aliases, err := u.tagAliasRepository.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tag aliases: %w", err)
+	}
+	tagAliases := tagvalueobject.NewTagAliases(aliases)
+	include = resolveSearchTags(include, tagAliases)
+	exclude = resolveSearchTags(exclude, tagAliases)
+
+	// タグの組み合わせから候補の画像IDを求める
+	candidates, err := u.matchTags(ctx, include, exclude, mode, tagAliases)
+	if err != nil {
+		return nil, err
+	}
+
+	// カーソルより後の候補を順に読み込み、絞り込み条件に一致する画像を集める
+	start := sort.SearchStrings(candidates, request.Cursor)
+	if start < len(candidates) && candidates[start] == request.Cursor {
+		start++
+	}
+	candidates = candidates[start:]
+
+	matched := make([]*aggregate.ImageAggregate, 0, limit)
+	nextCursor := ""
+	for offset := 0; offset < len(candidates) && nextCursor == ""; offset += searchBatchSize {
+		end := min(offset+searchBatchSize, len(candidates))
+		images, err := u.imageRepository.FindByIDs(ctx, candidates[offset:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to find images: %w", err)
+		}
+
+		for _, image := range images {
+			if date != "" && image.Image.UploadDate.String() != date {
+				continue
+			}
+			if contentType != "" && strings.ToLower(image.Image.ContentType.String()) != contentType {
+				continue
+			}
+			matched = append(matched, image)
+			if len(matched) == limit {
+				// 最後の候補でなければ続きがある
+				if image.GetImageID() != candidates[len(candidates)-1] {
+					nextCursor = image.GetImageID()
+				}
+				break
+			}
+		}
+	}
+
+	response, err := u.toListResponse(ctx, matched, request.Expand)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.SearchResponse{
+		Images:     response.Images,
+		Count:      response.Count,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// matchTags はタグの組み合わせに一致する画像IDを昇順で返します
+func (u *ListUsecase) matchTags(ctx context.Context, include, exclude []tagvalueobject.Tag, mode dto.SearchMode, aliases tagvalueobject.TagAliases) ([]string, error) {
+	var matched map[string]bool
+	for _, tag := range include {
+		tagged, err := u.findImagesByExpandedTag(ctx, tag, aliases)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case matched == nil:
+			matched = tagged
+		case mode == dto.SearchModeAny:
+			for imageID := range tagged {
+				matched[imageID] = true
+			}
+		default:
+			for imageID := range matched {
+				if !tagged[imageID] {
+					delete(matched, imageID)
+				}
+			}
+		}
+
+		// AND の場合は候補がなくなれば残りのタグを調べる必要はない
+		if mode == dto.SearchModeAll && len(matched) == 0 {
+			return []string{}, nil
+		}
+	}
+
+	for _, tag := range exclude {
+		tagged, err := u.findImagesByExpandedTag(ctx, tag, aliases)
+		if err != nil {
+			return nil, err
+		}
+		for imageID := range tagged {
+			delete(matched, imageID)
+		}
+	}
+
+	candidates := make([]string, 0, len(matched))
+	for imageID := range matched {
+		candidates = append(candidates, imageID)
+	}
+	sort.Strings(candidates)
+	return candidates, nil
+}
+
+// findImagesByExpandedTag は正規のタグまたはその別名のいずれかが付いた画像IDの集合を返します
+func (u *ListUsecase) findImagesByExpandedTag(ctx context.Context, tag tagvalueobject.Tag, aliases tagvalueobject.TagAliases) (map[string]bool, error) {
+	tagged := make(map[string]bool)
+	for _, expanded := range aliases.Expand(tag) {
+		imageIDs, err := u.tagRepository.FindImagesByTag(ctx, expanded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find images by tag: %w", err)
+		}
+		for _, imageID := range imageIDs {
+			tagged[imageID] = true
+		}
+	}
+	return tagged, nil
+}
+
+// resolveSearchTags は検索するタグを正規のタグに置き換え、重複を除去します
+func resolveSearchTags(tags []tagvalueobject.Tag, aliases tagvalueobject.TagAliases) []tagvalueobject.Tag {
+	resolved := make([]tagvalueobject.Tag, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		canonical := aliases.Resolve(tag)
+		if seen[canonical.Name()] {
+			continue
+		}
+		seen[canonical.Name()] = true
+		resolved = append(resolved, canonical)
+	}
+	return resolved
+}
+
+// parseSearchTags は検索するタグを正規化し、重複を除去します
+func parseSearchTags(names []string) ([]tagvalueobject.Tag, error) {
+	tags := make([]tagvalueobject.Tag, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		tag, err := tagvalueobject.NewTag(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSearchTag, name)
+		}
+		if seen[tag.Name()] {
+			continue
+		}
+		seen[tag.Name()] = true
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
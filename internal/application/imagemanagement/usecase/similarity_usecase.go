@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"cloudpix/internal/application/imagemanagement/dto"
+	"cloudpix/internal/domain/imagemanagement/aggregate"
+	"cloudpix/internal/domain/imagemanagement/repository"
+	"cloudpix/internal/domain/imagemanagement/valueobject"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// デフォルトの類似とみなすハミング距離
+const DefaultSimilarityDistance = 5
+
+var (
+	// ErrInvalidDistance はハミング距離が範囲外の場合のエラー
+	ErrInvalidDistance = fmt.Errorf("距離は0から%dの範囲である必要があります", valueobject.MaxSimilarityDistance)
+	// ErrPerceptualHashNotReady は知覚ハッシュがまだ計算されていない場合のエラー
+	ErrPerceptualHashNotReady = errors.New("画像の知覚ハッシュがまだ計算されていません")
+	// ErrOwnerRequired は所有者が特定できない場合のエラー
+	ErrOwnerRequired = errors.New("ユーザーが特定できません")
+)
+
+// SimilarityUsecase は知覚ハッシュによる類似画像検索のユースケースを実装します
+type SimilarityUsecase struct {
+	imageRepository      repository.ImageRepository
+	similarityRepository repository.SimilarityRepository
+}
+
+// NewSimilarityUsecase は新しい類似画像検索ユースケースを作成します
+func NewSimilarityUsecase(
+	imageRepository repository.ImageRepository,
+	similarityRepository repository.SimilarityRepository,
+) *SimilarityUsecase {
+	return &SimilarityUsecase{
+		imageRepository:      imageRepository,
+		similarityRepository: similarityRepository,
+	}
+}
+
+// FindSimilar は指定された画像からハミング距離が maxDistance 以内の画像を取得します
+func (u *SimilarityUsecase) FindSimilar(ctx context.Context, imageID string, maxDistance int) (*dto.SimilarImagesResponse, error) {
+	if maxDistance < 0 || maxDistance > valueobject.MaxSimilarityDistance {
+		return nil, ErrInvalidDistance
+	}
+
+	// 画像の存在チェック
+	exists, err := u.imageRepository.Exists(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrImageNotFound
+	}
+
+	source, err := u.imageRepository.FindByID(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+	if source.PerceptualHash == "" {
+		return nil, ErrPerceptualHashNotReady
+	}
+	hash, err := valueobject.ParsePerceptualHash(source.PerceptualHash)
+	if err != nil {
+		return nil, ErrPerceptualHashNotReady
+	}
+
+	// バンドインデックスから候補を取得
+	bandKeys, err := hash.CandidateBands(maxDistance)
+	if err != nil {
+		return nil, ErrInvalidDistance
+	}
+	candidates, err := u.similarityRepository.FindCandidates(ctx, bandKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	// 候補のうち距離が範囲内のものだけを残す
+	distances := make(map[string]int)
+	candidateIDs := make([]string, 0)
+	for _, candidate := range candidates {
+		if candidate.ImageID == imageID {
+			continue
+		}
+		if _, seen := distances[candidate.ImageID]; seen {
+			continue
+		}
+		distance := hash.Distance(candidate.Hash)
+		if distance > maxDistance {
+			continue
+		}
+		distances[candidate.ImageID] = distance
+		candidateIDs = append(candidateIDs, candidate.ImageID)
+	}
+
+	// 候補の画像をまとめて取得（削除済みの画像はインデックスに残っていても結果に含まれない）
+	candidateAggregates, err := u.imageRepository.FindByIDs(ctx, candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+	images := make([]dto.SimilarImageDTO, 0, len(candidateAggregates))
+	for _, candidateAggregate := range candidateAggregates {
+		images = append(images, dto.SimilarImageDTO{
+			ImageMetadataDTO: toImageMetadataDTO(candidateAggregate, dto.ExpandAll()),
+			Distance:         distances[candidateAggregate.GetImageID()],
+		})
+	}
+
+	// 距離の近い順に並べる
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].Distance != images[j].Distance {
+			return images[i].Distance < images[j].Distance
+		}
+		return images[i].ImageID < images[j].ImageID
+	})
+
+	return &dto.SimilarImagesResponse{
+		ImageID:     imageID,
+		MaxDistance: maxDistance,
+		Images:      images,
+		Count:       len(images),
+	}, nil
+}
+
+// FindDuplicates はユーザーが所有する画像のうち重複の可能性が高いまとまりを返します
+func (u *SimilarityUsecase) FindDuplicates(ctx context.Context, owner string, maxDistance int) (*dto.DuplicateReportResponse, error) {
+	if owner == "" {
+		return nil, ErrOwnerRequired
+	}
+	if maxDistance < 0 || maxDistance > valueobject.MaxSimilarityDistance {
+		return nil, ErrInvalidDistance
+	}
+
+	images, err := u.imageRepository.FindByOwner(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	// 知覚ハッシュが計算済みの画像のみを対象にする
+	hashed := make([]*aggregate.ImageAggregate, 0, len(images))
+	hashes := make([]valueobject.PerceptualHash, 0, len(images))
+	for _, image := range images {
+		hash, err := valueobject.ParsePerceptualHash(image.PerceptualHash)
+		if err != nil {
+			continue
+		}
+		hashed = append(hashed, image)
+		hashes = append(hashes, hash)
+	}
+
+	// 距離が範囲内の画像同士を同じまとまりにする（Union-Find）
+	parent := make([]int, len(hashed))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := 0; i < len(hashes); i++ {
+		for j := i + 1; j < len(hashes); j++ {
+			if hashes[i].Distance(hashes[j]) <= maxDistance {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	for i := range hashed {
+		root := find(i)
+		members[root] = append(members[root], i)
+	}
+
+	// 2枚以上の画像を含むまとまりをレポートにする
+	clusters := make([]dto.DuplicateClusterDTO, 0)
+	for _, indexes := range members {
+		if len(indexes) < 2 {
+			continue
+		}
+
+		cluster := dto.DuplicateClusterDTO{
+			Images: make([]dto.ImageMetadataDTO, 0, len(indexes)),
+		}
+		for n, i := range indexes {
+			cluster.Images = append(cluster.Images, toImageMetadataDTO(hashed[i], dto.ExpandAll()))
+			for _, j := range indexes[n+1:] {
+				if distance := hashes[i].Distance(hashes[j]); distance > cluster.MaxDistance {
+					cluster.MaxDistance = distance
+				}
+			}
+		}
+		sort.Slice(cluster.Images, func(i, j int) bool {
+			return cluster.Images[i].ImageID < cluster.Images[j].ImageID
+		})
+		clusters = append(clusters, cluster)
+	}
+
+	// 画像数の多いまとまりから順に並べる
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Images) != len(clusters[j].Images) {
+			return len(clusters[i].Images) > len(clusters[j].Images)
+		}
+		return clusters[i].Images[0].ImageID < clusters[j].Images[0].ImageID
+	})
+
+	return &dto.DuplicateReportResponse{
+		MaxDistance: maxDistance,
+		Clusters:    clusters,
+		Count:       len(clusters),
+	}, nil
+}
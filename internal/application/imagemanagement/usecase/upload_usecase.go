@@ -11,16 +11,26 @@ import (
 	"cloudpix/internal/domain/shared/event/dispatcher"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// アップロードの検証エラー
+var (
+	// ErrImageTooLarge は画像が処理できる大きさの上限を超えていることを表します
+	ErrImageTooLarge = errors.New("画像が大きすぎます")
+	// ErrInvalidImageData は画像データのヘッダーを読み取れないことを表します
+	ErrInvalidImageData = errors.New("画像データを読み取れません")
+)
+
 // UploadUsecase は画像アップロードのユースケースを実装します
 type UploadUsecase struct {
 	imageRepository repository.ImageRepository
 	storageService  service.StorageService
+	imageInspector  service.ImageInspector
 	eventDispatcher dispatcher.EventDispatcher
 	bucketName      string
 }
@@ -29,12 +39,14 @@ type UploadUsecase struct {
 func NewUploadUsecase(
 	imageRepository repository.ImageRepository,
 	storageService service.StorageService,
+	imageInspector service.ImageInspector,
 	eventDispatcher dispatcher.EventDispatcher,
 	bucketName string,
 ) *UploadUsecase {
 	return &UploadUsecase{
 		imageRepository: imageRepository,
 		storageService:  storageService,
+		imageInspector:  imageInspector,
 		eventDispatcher: eventDispatcher,
 		bucketName:      bucketName,
 	}
@@ -74,6 +86,14 @@ func (u *UploadUsecase) ProcessUpload(ctx context.Context, request *dto.UploadRe
 		}
 		imageSize, _ = valueobject.NewImageSize(len(data))
 
+		// 画像全体はデコードせずにヘッダーで宣言された大きさを確認
+		if _, _, err := u.imageInspector.Inspect(contentType.String(), data); err != nil {
+			if errors.Is(err, valueobject.ErrImageTooLarge) {
+				return nil, fmt.Errorf("%w: %v", ErrImageTooLarge, err)
+			}
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImageData, err)
+		}
+
 		// S3にアップロード
 		downloadURL, err = u.storageService.StoreImage(
 			ctx,
@@ -112,9 +132,11 @@ func (u *UploadUsecase) ProcessUpload(ctx context.Context, request *dto.UploadRe
 		objectKey,
 		downloadURL,
 	)
+	image.SetOwner(request.Owner)
 
 	// 集約を作成
 	imageAggregate := aggregate.NewImageAggregate(image)
+	imageAggregate.MarkThumbnailPending()
 
 	// リポジトリに保存
 	err = u.imageRepository.Save(ctx, imageAggregate)
@@ -123,7 +145,7 @@ func (u *UploadUsecase) ProcessUpload(ctx context.Context, request *dto.UploadRe
 	}
 
 	// イベントを発行
-	uploadEvent := event.NewImageUploadedEvent(image, u.bucketName)
+	uploadEvent := event.NewImageUploadedEvent(image, u.bucketName, request.OwnerRoles)
 	err = u.eventDispatcher.Dispatch(ctx, uploadEvent)
 	if err != nil {
 		// イベント発行に失敗してもアップロードは成功とみなす
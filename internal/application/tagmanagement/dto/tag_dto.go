@@ -1,26 +1,205 @@
 package dto
 
+import "encoding/json"
+
 // TagsResponseDTO はタグ一覧のレスポンスDTO
 type TagsResponseDTO struct {
 	Tags  []string `json:"tags"`
 	Count int      `json:"count"`
 }
 
+// タグ一覧の並び順
+const (
+	TagSortCount = "count" // 利用数の多い順
+	TagSortName  = "name"  // タグ名順
+)
+
+// ListTagsRequestDTO はタグ一覧取得のリクエストDTO
+type ListTagsRequestDTO struct {
+	Owner            string // 空の場合は全ユーザーの利用数
+	Sort             string // 空の場合は TagSortCount
+	Limit            int    // 0の場合は制限なし
+	Namespace        string // 指定した場合はその名前空間のタグのみ
+	GroupByNamespace bool   // 名前空間ごとのグループも返す
+}
+
+// SuggestTagsRequestDTO はタグの入力補完のリクエストDTO
+type SuggestTagsRequestDTO struct {
+	Prefix string // タグ名の先頭部分
+	Owner  string // 空の場合は全ユーザーの利用数
+	Limit  int    // 0の場合はデフォルトの件数
+}
+
+// TagCountDTO はタグとその利用数のDTO
+type TagCountDTO struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Count     int    `json:"count"`
+}
+
+// TagGroupDTO は名前空間ごとのタグのグループのDTO
+type TagGroupDTO struct {
+	Namespace string        `json:"namespace"` // 名前空間のないタグのグループは空
+	Tags      []TagCountDTO `json:"tags"`
+	Count     int           `json:"count"`
+}
+
+// TagCountsResponseDTO は利用数つきのタグ一覧のレスポンスDTO
+type TagCountsResponseDTO struct {
+	Tags   []TagCountDTO `json:"tags"`
+	Groups []TagGroupDTO `json:"groups,omitempty"`
+	Count  int           `json:"count"`
+}
+
 // AddTagRequestDTO はタグ追加リクエストのDTO
 type AddTagRequestDTO struct {
 	ImageID string   `json:"imageId"`
 	Tags    []string `json:"tags"`
+	Strict  bool     `json:"strict,omitempty"` // true の場合は無効なタグが1つでもあればリクエスト全体を拒否
+	ActorID string   `json:"-"`                // 変更履歴に記録する操作したユーザー
 }
 
 // RemoveTagRequestDTO はタグ削除リクエストのDTO
 type RemoveTagRequestDTO struct {
 	ImageID string   `json:"imageId"`
 	Tags    []string `json:"tags"`
+	Strict  bool     `json:"strict,omitempty"` // true の場合は無効なタグが1つでもあればリクエスト全体を拒否
+	ActorID string   `json:"-"`                // 変更履歴に記録する操作したユーザー
+}
+
+// タグごとの処理結果
+const (
+	TagResultAdded     = "added"     // 追加した
+	TagResultRemoved   = "removed"   // 削除した
+	TagResultDuplicate = "duplicate" // すでに付いている、またはリクエスト内で重複している
+	TagResultNotFound  = "not_found" // 画像に付いていない
+	TagResultInvalid   = "invalid"   // タグとして無効、または付与できない
+	TagResultValid     = "valid"     // 有効（厳格モードでリクエスト全体を拒否したため処理していない）
+)
+
+// TagResultDTO は指定されたタグごとの処理結果のDTO
+type TagResultDTO struct {
+	Input  string `json:"input"`            // 指定されたタグ
+	Tag    string `json:"tag,omitempty"`    // 正規化・別名の解決後のタグ（無効な場合は空）
+	Status string `json:"status"`           // 処理結果
+	Reason string `json:"reason,omitempty"` // 無効・重複の理由
 }
 
 // TagUpdateResponseDTO はタグ更新のレスポンスDTO
 type TagUpdateResponseDTO struct {
-	ImageID  string `json:"imageId"`
-	Message  string `json:"message"`
-	Modified int    `json:"modified"`
+	ImageID  string         `json:"imageId"`
+	Message  string         `json:"message"`
+	Modified int            `json:"modified"`
+	Results  []TagResultDTO `json:"results"` // 指定された順のタグごとの処理結果
+}
+
+// MergeTagsRequestDTO はタグの統合リクエストのDTO
+type MergeTagsRequestDTO struct {
+	Sources   []string `json:"sources"`             // 統合元のタグ
+	Target    string   `json:"target"`              // 統合先のタグ
+	Cursor    string   `json:"cursor,omitempty"`    // 前回の nextCursor（続きから処理する場合）
+	MaxImages int      `json:"maxImages,omitempty"` // 1回で処理する画像数（0の場合はデフォルト）
+	ActorID   string   `json:"-"`                   // 変更履歴に記録する操作したユーザー
+}
+
+// RenameTagRequestDTO はタグの名前変更リクエストのDTO
+type RenameTagRequestDTO struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Cursor    string `json:"cursor,omitempty"`
+	MaxImages int    `json:"maxImages,omitempty"`
+	ActorID   string `json:"-"`
+}
+
+// MergeTagsResponseDTO はタグの統合・名前変更のレスポンスDTO
+type MergeTagsResponseDTO struct {
+	Sources    []string `json:"sources"`
+	Target     string   `json:"target"`
+	Processed  int      `json:"processed"`            // 処理した画像数
+	Modified   int      `json:"modified"`             // タグを書き換えた画像数
+	Failed     []string `json:"failed"`               // 書き換えに失敗した画像ID
+	NextCursor string   `json:"nextCursor,omitempty"` // 続きがある場合の開始位置
+	Completed  bool     `json:"completed"`            // すべての統合元のタグを処理したか
+}
+
+// TagAliasDTO はタグの別名のDTO
+type TagAliasDTO struct {
+	Alias     string `json:"alias"`
+	Canonical string `json:"canonical"`
+}
+
+// TagAliasesResponseDTO はタグの別名一覧のレスポンスDTO
+type TagAliasesResponseDTO struct {
+	Aliases []TagAliasDTO `json:"aliases"`
+	Count   int           `json:"count"`
+}
+
+// PutTagAliasRequestDTO はタグの別名の登録リクエストのDTO
+type PutTagAliasRequestDTO struct {
+	Alias     string `json:"-"` // パスパラメータから設定
+	Canonical string `json:"canonical"`
+}
+
+// TagHistoryEntryDTO はタグの変更履歴エントリのDTO
+type TagHistoryEntryDTO struct {
+	ImageID     string   `json:"imageId"`
+	ActorID     string   `json:"actorId"`
+	AddedTags   []string `json:"addedTags"`
+	RemovedTags []string `json:"removedTags"`
+	OccurredAt  string   `json:"occurredAt"` // RFC3339形式
+}
+
+// TagHistoryResponseDTO はタグの変更履歴のレスポンスDTO（新しい順）
+type TagHistoryResponseDTO struct {
+	Entries    []TagHistoryEntryDTO `json:"entries"`
+	Count      int                  `json:"count"`
+	NextCursor string               `json:"nextCursor,omitempty"` // 続きがある場合の開始位置
+}
+
+// ImageTagHistoryRequestDTO は画像のタグの変更履歴取得のリクエストDTO
+type ImageTagHistoryRequestDTO struct {
+	ImageID string
+	Cursor  string
+	Limit   int // 0の場合はデフォルトの件数
+}
+
+// TagHistoryQueryRequestDTO は期間を指定したタグの変更履歴検索のリクエストDTO
+type TagHistoryQueryRequestDTO struct {
+	From    string // RFC3339形式または日付（空の場合は To の7日前）
+	To      string // RFC3339形式または日付（空の場合は現在時刻、日付の場合はその日の終わりまで）
+	Tag     string // 指定した場合はこのタグを追加・削除した変更のみ
+	ActorID string // 指定した場合はこのユーザーによる変更のみ
+	Cursor  string
+	Limit   int // 0の場合はデフォルトの件数
+}
+
+// ImageFactsDTO は自動タグ付けのルールの判定に使用する画像の属性のDTO
+// 指定しない属性は不明として扱われ、その属性の条件には一致しません
+type ImageFactsDTO struct {
+	FileName      string            `json:"fileName"`
+	ContentType   string            `json:"contentType"`
+	Size          int64             `json:"size"`
+	Width         int               `json:"width"`
+	Height        int               `json:"height"`
+	EXIF          map[string]string `json:"exif"`
+	UploaderRoles []string          `json:"uploaderRoles"`
+}
+
+// AutoTagDryRunRequestDTO は自動タグ付けのルールの試行リクエストのDTO
+type AutoTagDryRunRequestDTO struct {
+	Rules json.RawMessage `json:"rules,omitempty"` // 試行するルール定義（省略時は設定されているルール）
+	Image ImageFactsDTO   `json:"image"`
+}
+
+// AutoTagRuleResultDTO はルールごとの判定結果のDTO
+type AutoTagRuleResultDTO struct {
+	Name    string   `json:"name"`
+	Matched bool     `json:"matched"`
+	Tags    []string `json:"tags"` // ルールで付けるタグ
+}
+
+// AutoTagDryRunResponseDTO は自動タグ付けのルールの試行結果のDTO
+type AutoTagDryRunResponseDTO struct {
+	Rules []AutoTagRuleResultDTO `json:"rules"`
+	Tags  []string               `json:"tags"` // 別名の解決後に実際に付与されるタグ
 }
@@ -0,0 +1,124 @@
+package event
+
+import (
+	"cloudpix/internal/application/tagmanagement/usecase"
+	imagemanagement_event "cloudpix/internal/domain/imagemanagement/event"
+	imagerepository "cloudpix/internal/domain/imagemanagement/repository"
+	"cloudpix/internal/domain/shared/event/dispatcher"
+	"cloudpix/internal/domain/tagmanagement/valueobject"
+	thumbnailmanagement_event "cloudpix/internal/domain/thumbnailmanagement/event"
+	"cloudpix/internal/logging"
+	"context"
+	"fmt"
+)
+
+// ImageUploadedAutoTagHandler はアップロードされた画像に自動タグ付けのルールを適用するハンドラー
+// アップロード時点ではファイル名・コンテンツタイプ・サイズ・アップロードしたユーザーのロールで判定します
+type ImageUploadedAutoTagHandler struct {
+	autoTagUsecase *usecase.AutoTagUsecase
+	logger         logging.Logger
+}
+
+// NewImageUploadedAutoTagHandler は新しいイベントハンドラーを作成します
+func NewImageUploadedAutoTagHandler(autoTagUsecase *usecase.AutoTagUsecase, logger logging.Logger) *ImageUploadedAutoTagHandler {
+	return &ImageUploadedAutoTagHandler{
+		autoTagUsecase: autoTagUsecase,
+		logger:         logger,
+	}
+}
+
+// HandleEvent はイベントを処理します
+func (h *ImageUploadedAutoTagHandler) HandleEvent(ctx context.Context, event dispatcher.DomainEvent) error {
+	// イベントを適切な型にキャスト
+	uploadEvent, ok := event.(*imagemanagement_event.ImageUploadedEvent)
+	if !ok {
+		return fmt.Errorf("expected ImageUploadedEvent, got %T", event)
+	}
+
+	facts := valueobject.ImageFacts{
+		FileName:      uploadEvent.FileName,
+		ContentType:   uploadEvent.ContentType,
+		Size:          int64(uploadEvent.Size),
+		UploaderRoles: uploadEvent.OwnerRoles,
+	}
+	return applyAutoTagRules(ctx, h.autoTagUsecase, h.logger, uploadEvent.ImageID, facts)
+}
+
+// EventType はこのハンドラーが処理するイベントタイプを返します
+func (h *ImageUploadedAutoTagHandler) EventType() string {
+	return "image.uploaded"
+}
+
+// ThumbnailGeneratedAutoTagHandler はサムネイルを生成した画像に自動タグ付けのルールを適用するハンドラー
+// 生成後は画像のメタデータに加えて元画像の幅・高さ・EXIFで判定します
+type ThumbnailGeneratedAutoTagHandler struct {
+	autoTagUsecase  *usecase.AutoTagUsecase
+	imageRepository imagerepository.ImageRepository
+	logger          logging.Logger
+}
+
+// NewThumbnailGeneratedAutoTagHandler は新しいイベントハンドラーを作成します
+func NewThumbnailGeneratedAutoTagHandler(
+	autoTagUsecase *usecase.AutoTagUsecase,
+	imageRepository imagerepository.ImageRepository,
+	logger logging.Logger,
+) *ThumbnailGeneratedAutoTagHandler {
+	return &ThumbnailGeneratedAutoTagHandler{
+		autoTagUsecase:  autoTagUsecase,
+		imageRepository: imageRepository,
+		logger:          logger,
+	}
+}
+
+// HandleEvent はイベントを処理します
+func (h *ThumbnailGeneratedAutoTagHandler) HandleEvent(ctx context.Context, event dispatcher.DomainEvent) error {
+	// イベントを適切な型にキャスト
+	generatedEvent, ok := event.(*thumbnailmanagement_event.ThumbnailGeneratedEvent)
+	if !ok {
+		return fmt.Errorf("expected ThumbnailGeneratedEvent, got %T", event)
+	}
+
+	// ファイル名などはイベントに含まれないため画像のメタデータから取得
+	imageAggregate, err := h.imageRepository.FindByID(ctx, generatedEvent.ImageID)
+	if err != nil {
+		h.logger.Error(err, "Failed to load image for auto-tagging", map[string]interface{}{
+			"imageId": generatedEvent.ImageID,
+		})
+		return fmt.Errorf("failed to load image for auto-tagging: %w", err)
+	}
+
+	image := imageAggregate.Image
+	facts := valueobject.ImageFacts{
+		FileName:    image.FileName.String(),
+		ContentType: image.ContentType.String(),
+		Size:        int64(image.Size.Value()),
+		Width:       generatedEvent.SourceWidth,
+		Height:      generatedEvent.SourceHeight,
+		EXIF:        generatedEvent.EXIF,
+	}
+	return applyAutoTagRules(ctx, h.autoTagUsecase, h.logger, generatedEvent.ImageID, facts)
+}
+
+// EventType はこのハンドラーが処理するイベントタイプを返します
+func (h *ThumbnailGeneratedAutoTagHandler) EventType() string {
+	return "thumbnail.generated"
+}
+
+// applyAutoTagRules は画像に自動タグ付けのルールを適用し、結果をログに記録します
+func applyAutoTagRules(ctx context.Context, autoTagUsecase *usecase.AutoTagUsecase, logger logging.Logger, imageID string, facts valueobject.ImageFacts) error {
+	result, err := autoTagUsecase.ApplyRules(ctx, imageID, facts)
+	if err != nil {
+		logger.Error(err, "Failed to apply auto-tagging rules", map[string]interface{}{
+			"imageId": imageID,
+		})
+		return fmt.Errorf("failed to apply auto-tagging rules: %w", err)
+	}
+
+	if result.Modified > 0 {
+		logger.Info("Auto-tagging rules applied", map[string]interface{}{
+			"imageId":  imageID,
+			"modified": result.Modified,
+		})
+	}
+	return nil
+}
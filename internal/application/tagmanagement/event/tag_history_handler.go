@@ -0,0 +1,63 @@
+package event
+
+import (
+	"cloudpix/internal/domain/shared/event/dispatcher"
+	"cloudpix/internal/domain/tagmanagement/entity"
+	tagmanagement_event "cloudpix/internal/domain/tagmanagement/event"
+	"cloudpix/internal/domain/tagmanagement/repository"
+	"cloudpix/internal/logging"
+	"context"
+	"fmt"
+)
+
+// TagHistoryHandler はタグ更新イベントを変更履歴として記録するハンドラー
+type TagHistoryHandler struct {
+	tagHistoryRepository repository.TagHistoryRepository
+	logger               logging.Logger
+}
+
+// NewTagHistoryHandler は新しいイベントハンドラーを作成します
+func NewTagHistoryHandler(tagHistoryRepository repository.TagHistoryRepository, logger logging.Logger) *TagHistoryHandler {
+	return &TagHistoryHandler{
+		tagHistoryRepository: tagHistoryRepository,
+		logger:               logger,
+	}
+}
+
+// HandleEvent はイベントを処理します
+func (h *TagHistoryHandler) HandleEvent(ctx context.Context, event dispatcher.DomainEvent) error {
+	// イベントを適切な型にキャスト
+	updatedEvent, ok := event.(*tagmanagement_event.TagsUpdatedEvent)
+	if !ok {
+		return fmt.Errorf("expected TagsUpdatedEvent, got %T", event)
+	}
+
+	// タグが変わっていない場合は記録しない
+	if len(updatedEvent.AddedTags) == 0 && len(updatedEvent.RemovedTags) == 0 {
+		return nil
+	}
+
+	entry := entity.NewTagHistoryEntry(
+		updatedEvent.ImageID,
+		updatedEvent.ActorID,
+		updatedEvent.AddedTags,
+		updatedEvent.RemovedTags,
+		updatedEvent.OccurredAt(),
+	)
+	if err := h.tagHistoryRepository.Save(ctx, entry); err != nil {
+		h.logger.Error(err, "Failed to record tag history", map[string]interface{}{
+			"imageId":     updatedEvent.ImageID,
+			"actorId":     updatedEvent.ActorID,
+			"addedTags":   updatedEvent.AddedTags,
+			"removedTags": updatedEvent.RemovedTags,
+		})
+		return fmt.Errorf("failed to record tag history: %w", err)
+	}
+
+	return nil
+}
+
+// EventType はこのハンドラーが処理するイベントタイプを返します
+func (h *TagHistoryHandler) EventType() string {
+	return "tags.updated"
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"cloudpix/internal/application/tagmanagement/dto"
+	"cloudpix/internal/domain/tagmanagement/valueobject"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// AutoTagActorID は変更履歴に記録する自動タグ付けによる変更の実行元
+const AutoTagActorID = "system:auto-tagging"
+
+// 自動タグ付けのエラー
+var (
+	ErrInvalidAutoTagRules       = errors.New("無効な自動タグ付けのルールです")
+	ErrAutoTagRulesNotConfigured = errors.New("有効な自動タグ付けのルールが設定されていません")
+)
+
+// AutoTagUsecase は条件に一致した画像にタグを付ける自動タグ付けのユースケース
+// タグの付与は通常のタグの追加と同じく別名の解決と名前空間の確認を行い、変更履歴に記録されます
+type AutoTagUsecase struct {
+	rules      valueobject.AutoTagRules
+	tagUsecase *TagUsecase
+}
+
+// NewAutoTagUsecase は新しい自動タグ付けユースケースを作成します
+func NewAutoTagUsecase(rules valueobject.AutoTagRules, tagUsecase *TagUsecase) *AutoTagUsecase {
+	return &AutoTagUsecase{
+		rules:      rules,
+		tagUsecase: tagUsecase,
+	}
+}
+
+// ApplyRules は画像の属性に一致するルールのタグを画像に追加します
+// すでに付いているタグは追加しないため、同じ画像に複数回適用しても結果は変わりません
+func (u *AutoTagUsecase) ApplyRules(ctx context.Context, imageID string, facts valueobject.ImageFacts) (*dto.TagUpdateResponseDTO, error) {
+	tags := u.rules.Match(facts).Tags()
+	if len(tags) == 0 {
+		return &dto.TagUpdateResponseDTO{
+			ImageID:  imageID,
+			Message:  "No rules matched",
+			Modified: 0,
+			Results:  []dto.TagResultDTO{},
+		}, nil
+	}
+
+	return u.tagUsecase.AddTags(ctx, &dto.AddTagRequestDTO{
+		ImageID: imageID,
+		Tags:    tagNames(tags),
+		ActorID: AutoTagActorID,
+	})
+}
+
+// DryRun は画像にタグを付けずにルールを評価し、ルールごとの判定結果と付与されるタグを返します
+// リクエストでルールが指定された場合は設定されているルールの代わりにそのルールを評価します
+func (u *AutoTagUsecase) DryRun(ctx context.Context, request *dto.AutoTagDryRunRequestDTO) (*dto.AutoTagDryRunResponseDTO, error) {
+	rules := u.rules
+	if len(request.Rules) == 0 && len(rules) == 0 {
+		// 未設定や設定の誤りで評価するルールがない場合は、一致しなかった結果と区別できるようエラーにする
+		return nil, ErrAutoTagRulesNotConfigured
+	}
+	if len(request.Rules) > 0 {
+		parsed, err := valueobject.ParseAutoTagRules(string(request.Rules))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAutoTagRules, err)
+		}
+		rules = parsed
+	}
+
+	facts := toImageFacts(request.Image)
+	response := &dto.AutoTagDryRunResponseDTO{
+		Rules: make([]dto.AutoTagRuleResultDTO, len(rules)),
+		Tags:  []string{},
+	}
+	for i, rule := range rules {
+		response.Rules[i] = dto.AutoTagRuleResultDTO{
+			Name:    rule.Name(),
+			Matched: rule.Matches(facts),
+			Tags:    tagNames(rule.Tags()),
+		}
+	}
+
+	// 実際の付与と同じく別名を解決し、許可されていない名前空間のタグを除く
+	matched := rules.Match(facts)
+	if len(matched) == 0 {
+		return response, nil
+	}
+	aliases, err := u.tagUsecase.loadAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, tag := range matched.Tags() {
+		resolved, err := u.tagUsecase.resolveTag(tag.Name(), aliases)
+		if err != nil || seen[resolved.Name()] {
+			continue
+		}
+		seen[resolved.Name()] = true
+		response.Tags = append(response.Tags, resolved.Name())
+	}
+
+	return response, nil
+}
+
+// toImageFacts は画像の属性のDTOを値オブジェクトに変換します
+func toImageFacts(facts dto.ImageFactsDTO) valueobject.ImageFacts {
+	return valueobject.ImageFacts{
+		FileName:      facts.FileName,
+		ContentType:   facts.ContentType,
+		Size:          facts.Size,
+		Width:         facts.Width,
+		Height:        facts.Height,
+		EXIF:          facts.EXIF,
+		UploaderRoles: facts.UploaderRoles,
+	}
+}
+
+// tagNames はタグの名前の一覧を返します
+func tagNames(tags []valueobject.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name()
+	}
+	return names
+}
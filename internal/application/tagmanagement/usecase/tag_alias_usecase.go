@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"cloudpix/internal/application/tagmanagement/dto"
+	"cloudpix/internal/domain/tagmanagement/valueobject"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// タグの別名のエラー
+var (
+	ErrInvalidAlias  = errors.New("別名と正規のタグには異なるタグを指定してください")
+	ErrAliasChain    = errors.New("別名を正規のタグにすることや、正規のタグを別名にすることはできません")
+	ErrAliasNotFound = errors.New("指定された別名が見つかりません")
+)
+
+// ListAliases はすべてのタグの別名を別名の名前順に取得します
+func (u *TagUsecase) ListAliases(ctx context.Context) (*dto.TagAliasesResponseDTO, error) {
+	aliases, err := u.tagAliasRepository.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepositoryFailure, err)
+	}
+
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Alias().Name() < aliases[j].Alias().Name()
+	})
+
+	response := &dto.TagAliasesResponseDTO{
+		Aliases: make([]dto.TagAliasDTO, len(aliases)),
+		Count:   len(aliases),
+	}
+	for i, alias := range aliases {
+		response.Aliases[i] = toTagAliasDTO(alias)
+	}
+	return response, nil
+}
+
+// PutAlias はタグの別名を登録します
+// 登録済みの画像のタグは書き換えないため、別名のまま付けられたタグは検索時の展開で一致します
+// 正規のタグにまとめる場合は MergeTags を使用します
+func (u *TagUsecase) PutAlias(ctx context.Context, request *dto.PutTagAliasRequestDTO) (*dto.TagAliasDTO, error) {
+	aliasTag, err := valueobject.NewTag(request.Alias)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTag, err)
+	}
+	canonical, err := valueobject.NewTag(request.Canonical)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTag, err)
+	}
+	if !u.namespaces.Allows(canonical) {
+		return nil, ErrNamespaceNotAllowed
+	}
+
+	alias, err := valueobject.NewTagAlias(aliasTag, canonical)
+	if err != nil {
+		return nil, ErrInvalidAlias
+	}
+
+	// 別名は1段階のみとし、別名の連鎖を作らない
+	aliases, err := u.loadAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if aliases.IsAlias(canonical) || aliases.HasAliases(aliasTag) {
+		return nil, ErrAliasChain
+	}
+
+	if err := u.tagAliasRepository.Save(ctx, alias); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepositoryFailure, err)
+	}
+
+	result := toTagAliasDTO(alias)
+	return &result, nil
+}
+
+// DeleteAlias はタグの別名を削除します
+func (u *TagUsecase) DeleteAlias(ctx context.Context, aliasName string) error {
+	aliasTag, err := valueobject.NewTag(aliasName)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTag, err)
+	}
+
+	aliases, err := u.loadAliases(ctx)
+	if err != nil {
+		return err
+	}
+	if !aliases.IsAlias(aliasTag) {
+		return ErrAliasNotFound
+	}
+
+	if err := u.tagAliasRepository.Delete(ctx, aliasTag); err != nil {
+		return fmt.Errorf("%w: %v", ErrRepositoryFailure, err)
+	}
+	return nil
+}
+
+// loadAliases は登録されているすべての別名を読み込みます
+func (u *TagUsecase) loadAliases(ctx context.Context) (valueobject.TagAliases, error) {
+	aliases, err := u.tagAliasRepository.FindAll(ctx)
+	if err != nil {
+		return valueobject.TagAliases{}, fmt.Errorf("%w: %v", ErrRepositoryFailure, err)
+	}
+	return valueobject.NewTagAliases(aliases), nil
+}
+
+// toTagAliasDTO は別名をDTOに変換します
+func toTagAliasDTO(alias valueobject.TagAlias) dto.TagAliasDTO {
+	return dto.TagAliasDTO{
+		Alias:     alias.Alias().Name(),
+		Canonical: alias.Canonical().Name(),
+	}
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"cloudpix/internal/application/tagmanagement/dto"
+	"cloudpix/internal/domain/tagmanagement/repository"
+	"cloudpix/internal/domain/tagmanagement/valueobject"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// タグの変更履歴で1回に返す件数
+const (
+	DefaultHistoryLimit = 50
+	MaxHistoryLimit     = 100
+)
+
+// 期間を指定した変更履歴の検索の期間
+const (
+	DefaultHistoryRange = 7 * 24 * time.Hour
+	MaxHistoryRangeDays = 31
+)
+
+// historyDateLayout は期間に日付のみを指定する場合の形式
+const historyDateLayout = "2006-01-02"
+
+// タグの変更履歴のエラー
+var (
+	ErrInvalidHistoryLimit  = fmt.Errorf("件数は1から%dの範囲である必要があります", MaxHistoryLimit)
+	ErrInvalidHistoryRange  = fmt.Errorf("期間は from <= to かつ%d日以内のRFC3339形式または日付で指定してください", MaxHistoryRangeDays)
+	ErrInvalidHistoryCursor = errors.New("無効なカーソルです")
+)
+
+// TagHistoryUsecase はタグの変更履歴のユースケース
+// 変更履歴はタグ更新イベントを受けた TagHistoryHandler が記録します
+type TagHistoryUsecase struct {
+	tagRepository        repository.TagRepository
+	tagHistoryRepository repository.TagHistoryRepository
+}
+
+// NewTagHistoryUsecase は新しいタグの変更履歴ユースケースを作成します
+func NewTagHistoryUsecase(tagRepository repository.TagRepository, tagHistoryRepository repository.TagHistoryRepository) *TagHistoryUsecase {
+	return &TagHistoryUsecase{
+		tagRepository:        tagRepository,
+		tagHistoryRepository: tagHistoryRepository,
+	}
+}
+
+// GetImageHistory は画像のタグの変更履歴を新しい順に取得します
+func (u *TagHistoryUsecase) GetImageHistory(ctx context.Context, request *dto.ImageTagHistoryRequestDTO) (*dto.TagHistoryResponseDTO, error) {
+	limit, err := historyLimit(request.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// 画像の存在チェック
+	exists, err := u.tagRepository.ImageExists(ctx, request.ImageID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepositoryFailure, err)
+	}
+	if !exists {
+		return nil, ErrImageNotFound
+	}
+
+	page, err := u.tagHistoryRepository.FindByImage(ctx, request.ImageID, request.Cursor, limit)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidHistoryCursor) {
+			return nil, ErrInvalidHistoryCursor
+		}
+		return nil, fmt.Errorf("%w: %v", ErrRepositoryFailure, err)
+	}
+
+	return toTagHistoryResponse(page), nil
+}
+
+// QueryHistory は期間内のすべての画像のタグの変更履歴を新しい順に取得します
+// タグを指定すると、そのタグを追加・削除した変更（誰がいつ外したかなど）に絞り込めます
+func (u *TagHistoryUsecase) QueryHistory(ctx context.Context, request *dto.TagHistoryQueryRequestDTO) (*dto.TagHistoryResponseDTO, error) {
+	limit, err := historyLimit(request.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := parseHistoryRange(request.From, request.To, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	query := repository.TagHistoryQuery{
+		From:    from,
+		To:      to,
+		ActorID: request.ActorID,
+		Cursor:  request.Cursor,
+		Limit:   limit,
+	}
+	if request.Tag != "" {
+		tag, err := valueobject.NewTag(request.Tag)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTag, err)
+		}
+		query.TagName = tag.Name()
+	}
+
+	page, err := u.tagHistoryRepository.FindByTimeRange(ctx, query)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidHistoryCursor) {
+			return nil, ErrInvalidHistoryCursor
+		}
+		return nil, fmt.Errorf("%w: %v", ErrRepositoryFailure, err)
+	}
+
+	return toTagHistoryResponse(page), nil
+}
+
+// historyLimit は変更履歴の件数を検証します（0の場合はデフォルトの件数）
+func historyLimit(limit int) (int, error) {
+	if limit == 0 {
+		return DefaultHistoryLimit, nil
+	}
+	if limit < 0 || limit > MaxHistoryLimit {
+		return 0, ErrInvalidHistoryLimit
+	}
+	return limit, nil
+}
+
+// parseHistoryRange は検索の期間を解析します
+// 終了の省略時は現在時刻、開始の省略時は終了の DefaultHistoryRange 前とし、日付のみの終了はその日の終わりまでを含めます
+func parseHistoryRange(fromValue, toValue string, now time.Time) (time.Time, time.Time, error) {
+	to := now
+	if toValue != "" {
+		parsed, dateOnly, err := parseHistoryTime(toValue)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidHistoryRange
+		}
+		to = parsed
+		if dateOnly {
+			to = parsed.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+	}
+
+	from := to.Add(-DefaultHistoryRange)
+	if fromValue != "" {
+		parsed, _, err := parseHistoryTime(fromValue)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidHistoryRange
+		}
+		from = parsed
+	}
+
+	if from.After(to) || to.Sub(from) > MaxHistoryRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, ErrInvalidHistoryRange
+	}
+	return from, to, nil
+}
+
+// parseHistoryTime はRFC3339形式または日付（UTC）の時刻を解析し、日付のみの指定だったかどうかも返します
+func parseHistoryTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse(historyDateLayout, value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
+
+// toTagHistoryResponse は変更履歴のページをレスポンスに変換します
+func toTagHistoryResponse(page *repository.TagHistoryPage) *dto.TagHistoryResponseDTO {
+	entries := make([]dto.TagHistoryEntryDTO, len(page.Entries))
+	for i, entry := range page.Entries {
+		entries[i] = dto.TagHistoryEntryDTO{
+			ImageID:     entry.ImageID,
+			ActorID:     entry.ActorID,
+			AddedTags:   entry.AddedTags,
+			RemovedTags: entry.RemovedTags,
+			OccurredAt:  entry.OccurredAt.Format(time.RFC3339),
+		}
+	}
+
+	return &dto.TagHistoryResponseDTO{
+		Entries:    entries,
+		Count:      len(entries),
+		NextCursor: page.NextCursor,
+	}
+}
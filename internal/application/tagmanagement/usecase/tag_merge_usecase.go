@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"cloudpix/internal/application/tagmanagement/dto"
+	"cloudpix/internal/domain/tagmanagement/event"
+	"cloudpix/internal/domain/tagmanagement/valueobject"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// タグの統合で1回に処理する画像数
+const (
+	DefaultMergeBatchSize = 50
+	MaxMergeBatchSize     = 100
+)
+
+const (
+	// maxMergeDuration はタグの統合を1回の呼び出しで続ける時間の上限（API Gatewayのタイムアウトより短くする）
+	maxMergeDuration = 20 * time.Second
+	// mergeDeadlineMargin はコンテキストの期限の前に統合を打ち切る余裕
+	mergeDeadlineMargin = 3 * time.Second
+)
+
+// mergeCursorSeparator は統合の再開位置でタグ名と画像IDを区切る文字（タグ名には使用できない文字）
+const mergeCursorSeparator = "/"
+
+// タグの統合のエラー
+var (
+	ErrInvalidMerge          = errors.New("統合元には統合先と異なるタグを1つ以上指定してください")
+	ErrInvalidMergeCursor    = errors.New("無効なカーソルです")
+	ErrInvalidMergeBatchSize = fmt.Errorf("maxImages は1から%dの範囲である必要があります", MaxMergeBatchSize)
+)
+
+// RenameTag はタグの名前を変更します
+// 変更先のタグがすでに使われている場合は、そのタグへの統合になります
+func (u *TagUsecase) RenameTag(ctx context.Context, request *dto.RenameTagRequestDTO) (*dto.MergeTagsResponseDTO, error) {
+	return u.MergeTags(ctx, &dto.MergeTagsRequestDTO{
+		Sources:   []string{request.From},
+		Target:    request.To,
+		Cursor:    request.Cursor,
+		MaxImages: request.MaxImages,
+		ActorID:   request.ActorID,
+	})
+}
+
+// MergeTags は統合元のタグが付いたすべての画像のタグを統合先のタグに書き換えます
+// 統合先に別名が指定された場合はその正規のタグに書き換えます
+// 1回の呼び出しでは最大 MaxImages 枚を時間の上限まで処理し、続きがある場合は NextCursor を返します
+// 書き換えた画像は統合元のタグを持たなくなるため、失敗した画像はカーソルなしで再実行すると再度処理されます
+func (u *TagUsecase) MergeTags(ctx context.Context, request *dto.MergeTagsRequestDTO) (*dto.MergeTagsResponseDTO, error) {
+	// 統合先に別名が指定された場合は正規のタグに統合する（別名のタグを付けないため）
+	aliases, err := u.loadAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := u.resolveTag(request.Target, aliases)
+	if errors.Is(err, ErrNamespaceNotAllowed) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTag, err)
+	}
+
+	sources, err := mergeSources(request.Sources, target)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := request.MaxImages
+	if batchSize == 0 {
+		batchSize = DefaultMergeBatchSize
+	}
+	if batchSize < 0 || batchSize > MaxMergeBatchSize {
+		return nil, ErrInvalidMergeBatchSize
+	}
+
+	sourceIndex, after, err := parseMergeCursor(request.Cursor, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.MergeTagsResponseDTO{
+		Sources: make([]string, len(sources)),
+		Target:  target.Name(),
+		Failed:  []string{},
+	}
+	for i, source := range sources {
+		response.Sources[i] = source.Name()
+	}
+
+	// 時間の上限に達した場合は処理済みの画像の位置で打ち切り、続きをカーソルで返す
+	deadline := mergeDeadline(ctx)
+
+	// 統合元のタグごとに、画像ID順にページを処理する
+	timedOut := false
+	for sourceIndex < len(sources) && response.Processed < batchSize && !timedOut {
+		page, err := u.tagRepository.FindImagesByTagPage(ctx, sources[sourceIndex], after, batchSize-response.Processed)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRepositoryFailure, err)
+		}
+
+		for _, imageID := range page.ImageIDs {
+			if time.Now().After(deadline) {
+				timedOut = true
+				break
+			}
+			response.Processed++
+			after = imageID
+			modified, err := u.retagImage(ctx, imageID, sources, target, request.ActorID)
+			if err != nil {
+				response.Failed = append(response.Failed, imageID)
+				continue
+			}
+			if modified {
+				response.Modified++
+			}
+		}
+		if timedOut {
+			break
+		}
+
+		if page.NextCursor == "" {
+			sourceIndex++
+			after = ""
+		} else {
+			after = page.NextCursor
+		}
+	}
+
+	response.Completed = sourceIndex == len(sources)
+	if !response.Completed {
+		response.NextCursor = sources[sourceIndex].Name() + mergeCursorSeparator + after
+	}
+
+	return response, nil
+}
+
+// mergeDeadline は1回の統合を打ち切る時刻を返します
+func mergeDeadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(maxMergeDuration)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Add(-mergeDeadlineMargin).Before(deadline) {
+		deadline = ctxDeadline.Add(-mergeDeadlineMargin)
+	}
+	return deadline
+}
+
+// retagImage は画像から統合元のタグを外して統合先のタグを付け、変更があった場合は保存してイベントを発行します
+func (u *TagUsecase) retagImage(ctx context.Context, imageID string, sources []valueobject.Tag, target valueobject.Tag, actorID string) (bool, error) {
+	taggedImage, err := u.tagRepository.FindTaggedImage(ctx, imageID)
+	if err != nil {
+		return false, err
+	}
+	if taggedImage == nil {
+		return false, nil
+	}
+
+	removedTags := make([]string, 0, len(sources))
+	for _, source := range sources {
+		if taggedImage.RemoveTag(source) {
+			removedTags = append(removedTags, source.Name())
+		}
+	}
+	if len(removedTags) == 0 {
+		return false, nil
+	}
+	var addedTags []string
+	if taggedImage.AddTag(target) {
+		addedTags = []string{target.Name()}
+	}
+
+	if err := u.tagRepository.Save(ctx, taggedImage); err != nil {
+		return false, err
+	}
+
+	// イベント発行
+	u.eventDispatcher.Dispatch(ctx, event.NewTagsUpdatedEvent(taggedImage, actorID, addedTags, removedTags))
+
+	return true, nil
+}
+
+// mergeSources は統合元のタグを正規化し、重複と統合先を除いてタグ名順に並べます
+func mergeSources(names []string, target valueobject.Tag) ([]valueobject.Tag, error) {
+	unique := make(map[string]valueobject.Tag, len(names))
+	for _, name := range names {
+		tag, err := valueobject.NewTag(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTag, err)
+		}
+		if !tag.Equals(target) {
+			unique[tag.Name()] = tag
+		}
+	}
+	if len(unique) == 0 {
+		return nil, ErrInvalidMerge
+	}
+
+	sources := make([]valueobject.Tag, 0, len(unique))
+	for _, tag := range unique {
+		sources = append(sources, tag)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Name() < sources[j].Name()
+	})
+	return sources, nil
+}
+
+// parseMergeCursor は再開位置を統合元のタグの位置とタグ内の画像IDに変換します
+func parseMergeCursor(cursor string, sources []valueobject.Tag) (int, string, error) {
+	if cursor == "" {
+		return 0, "", nil
+	}
+
+	tagName, after, ok := strings.Cut(cursor, mergeCursorSeparator)
+	if !ok {
+		return 0, "", ErrInvalidMergeCursor
+	}
+	for i, source := range sources {
+		if source.Name() == tagName {
+			return i, after, nil
+		}
+	}
+	return 0, "", ErrInvalidMergeCursor
+}
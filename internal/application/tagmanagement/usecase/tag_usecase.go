@@ -10,50 +10,210 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 )
 
 // 定義済みエラー
 var (
-	ErrImageNotFound     = errors.New("指定された画像が見つかりません")
-	ErrInvalidTag        = errors.New("無効なタグ形式です")
-	ErrRepositoryFailure = errors.New("タグリポジトリ操作に失敗しました")
+	ErrImageNotFound       = errors.New("指定された画像が見つかりません")
+	ErrInvalidTag          = errors.New("無効なタグ形式です")
+	ErrRepositoryFailure   = errors.New("タグリポジトリ操作に失敗しました")
+	ErrInvalidSort         = errors.New("sort には count または name を指定してください")
+	ErrInvalidLimit        = fmt.Errorf("件数は1から%dの範囲である必要があります", MaxTagListLimit)
+	ErrInvalidPrefix       = errors.New("無効な前方一致の条件です")
+	ErrInvalidSuggestLimit = fmt.Errorf("候補の件数は1から%dの範囲である必要があります", MaxSuggestLimit)
+	ErrInvalidNamespace    = errors.New("無効な名前空間です")
+	ErrNamespaceNotAllowed = errors.New("許可されていない名前空間のタグです")
+)
+
+// TagValidationError は厳格モードで無効なタグが指定されたため、リクエスト全体を拒否したことを表します
+// errors.Is で ErrInvalidTag として判定でき、Results に指定されたタグごとの検証結果を持ちます
+type TagValidationError struct {
+	Results []dto.TagResultDTO
+}
+
+// Error はエラーメッセージを返します
+func (e *TagValidationError) Error() string {
+	invalid := 0
+	for _, result := range e.Results {
+		if result.Status == dto.TagResultInvalid {
+			invalid++
+		}
+	}
+	return fmt.Sprintf("%s: %d個のタグが無効です", ErrInvalidTag.Error(), invalid)
+}
+
+// Unwrap は ErrInvalidTag を返します
+func (e *TagValidationError) Unwrap() error {
+	return ErrInvalidTag
+}
+
+// タグごとの処理結果の理由
+const (
+	reasonDuplicateInRequest = "同じタグがリクエスト内ですでに指定されています"
+	reasonAlreadyTagged      = "画像にすでに付いています"
+	reasonNotTagged          = "画像に付いていません"
+)
+
+// MaxTagListLimit はタグ一覧で一度に返す件数の上限
+const MaxTagListLimit = 1000
+
+// タグの入力補完で返す候補の件数
+const (
+	DefaultSuggestLimit = 10
+	MaxSuggestLimit     = 50
 )
 
 // TagUsecase はタグ管理のユースケース
 type TagUsecase struct {
-	tagRepository   repository.TagRepository
-	eventDispatcher dispatcher.EventDispatcher
+	tagRepository      repository.TagRepository
+	tagAliasRepository repository.TagAliasRepository
+	eventDispatcher    dispatcher.EventDispatcher
+	namespaces         valueobject.TagNamespaces
 }
 
 // NewTagUsecase は新しいタグユースケースを作成します
+// namespaces は画像に付与できる名前空間つきのタグの名前空間です
 func NewTagUsecase(
 	tagRepository repository.TagRepository,
+	tagAliasRepository repository.TagAliasRepository,
 	eventDispatcher dispatcher.EventDispatcher,
+	namespaces valueobject.TagNamespaces,
 ) *TagUsecase {
 	return &TagUsecase{
-		tagRepository:   tagRepository,
-		eventDispatcher: eventDispatcher,
+		tagRepository:      tagRepository,
+		tagAliasRepository: tagAliasRepository,
+		eventDispatcher:    eventDispatcher,
+		namespaces:         namespaces,
 	}
 }
 
-// ListAllTags は全てのタグを取得します
-func (u *TagUsecase) ListAllTags(ctx context.Context) (*dto.TagsResponseDTO, error) {
-	// リポジトリから全タグを取得
-	tags, err := u.tagRepository.FindAllTags(ctx)
+// ListTags はタグを利用数とともに取得します
+// 利用数はタグの追加・削除時に更新された値を読み取るため、タグテーブルの走査は行いません
+func (u *TagUsecase) ListTags(ctx context.Context, request *dto.ListTagsRequestDTO) (*dto.TagCountsResponseDTO, error) {
+	sortBy := request.Sort
+	if sortBy == "" {
+		sortBy = dto.TagSortCount
+	}
+	if sortBy != dto.TagSortCount && sortBy != dto.TagSortName {
+		return nil, ErrInvalidSort
+	}
+	if request.Limit < 0 || request.Limit > MaxTagListLimit {
+		return nil, ErrInvalidLimit
+	}
+
+	// 名前空間が指定された場合は、その名前空間のタグのみを前方一致で取得
+	var usages []valueobject.TagUsage
+	var err error
+	if request.Namespace != "" {
+		prefix, prefixErr := valueobject.NewNamespacePrefix(request.Namespace)
+		if prefixErr != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidNamespace, prefixErr)
+		}
+		usages, err = u.tagRepository.FindTagUsageByPrefix(ctx, request.Owner, prefix)
+	} else {
+		usages, err = u.tagRepository.FindTagUsage(ctx, request.Owner)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrRepositoryFailure, err)
 	}
 
-	// タグ名の配列に変換
-	tagNames := make([]string, len(tags))
-	for i, tag := range tags {
-		tagNames[i] = tag.Name()
+	sortTagUsage(usages, sortBy)
+	response := toTagCountsResponse(usages, request.Limit)
+	if request.GroupByNamespace {
+		response.Groups = groupByNamespace(response.Tags)
 	}
+	return response, nil
+}
 
-	return &dto.TagsResponseDTO{
-		Tags:  tagNames,
-		Count: len(tagNames),
-	}, nil
+// SuggestTags は名前が前方一致するタグを利用数の多い順に取得します
+// 前方一致の条件はタグ名と同じ規則で正規化されます
+func (u *TagUsecase) SuggestTags(ctx context.Context, request *dto.SuggestTagsRequestDTO) (*dto.TagCountsResponseDTO, error) {
+	prefix, err := valueobject.NewTagPrefix(request.Prefix)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrefix, err)
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = DefaultSuggestLimit
+	}
+	if limit < 0 || limit > MaxSuggestLimit {
+		return nil, ErrInvalidSuggestLimit
+	}
+
+	usages, err := u.tagRepository.FindTagUsageByPrefix(ctx, request.Owner, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepositoryFailure, err)
+	}
+
+	sortTagUsage(usages, dto.TagSortCount)
+	return toTagCountsResponse(usages, limit), nil
+}
+
+// sortTagUsage はタグの利用数を利用数の多い順（同数はタグ名順）またはタグ名順に並べます
+func sortTagUsage(usages []valueobject.TagUsage, sortBy string) {
+	sort.Slice(usages, func(i, j int) bool {
+		if sortBy == dto.TagSortCount && usages[i].Count() != usages[j].Count() {
+			return usages[i].Count() > usages[j].Count()
+		}
+		return usages[i].Tag().Name() < usages[j].Tag().Name()
+	})
+}
+
+// toTagCountsResponse はタグの利用数を先頭から limit 件（0の場合はすべて）のレスポンスに変換します
+func toTagCountsResponse(usages []valueobject.TagUsage, limit int) *dto.TagCountsResponseDTO {
+	if limit > 0 && len(usages) > limit {
+		usages = usages[:limit]
+	}
+
+	tags := make([]dto.TagCountDTO, len(usages))
+	for i, usage := range usages {
+		tags[i] = dto.TagCountDTO{
+			Name:      usage.Tag().Name(),
+			Namespace: usage.Tag().Namespace(),
+			Count:     usage.Count(),
+		}
+	}
+
+	return &dto.TagCountsResponseDTO{
+		Tags:  tags,
+		Count: len(tags),
+	}
+}
+
+// groupByNamespace は並び順を保ったままタグを名前空間ごとにまとめます
+// グループは名前空間の名前順で、名前空間のないタグのグループが先頭になります
+func groupByNamespace(tags []dto.TagCountDTO) []dto.TagGroupDTO {
+	indexes := make(map[string]int)
+	groups := make([]dto.TagGroupDTO, 0)
+	for _, tag := range tags {
+		index, ok := indexes[tag.Namespace]
+		if !ok {
+			index = len(groups)
+			indexes[tag.Namespace] = index
+			groups = append(groups, dto.TagGroupDTO{
+				Namespace: tag.Namespace,
+				Tags:      make([]dto.TagCountDTO, 0),
+			})
+		}
+		groups[index].Tags = append(groups[index].Tags, tag)
+		groups[index].Count++
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Namespace < groups[j].Namespace
+	})
+	return groups
+}
+
+// RebuildTagCounts はタグテーブル全体からタグの利用数を数え直します
+func (u *TagUsecase) RebuildTagCounts(ctx context.Context) (int, error) {
+	rebuilt, err := u.tagRepository.RebuildTagUsage(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrRepositoryFailure, err)
+	}
+	return rebuilt, nil
 }
 
 // GetImageTags は指定された画像のタグを取得します
@@ -91,7 +251,8 @@ func (u *TagUsecase) GetImageTags(ctx context.Context, imageID string) (*dto.Tag
 	}, nil
 }
 
-// AddTags は画像にタグを追加します
+// AddTags は画像にタグを追加し、指定されたタグごとの処理結果を返します
+// 無効なタグは追加せずに理由を結果に記録し、Strict の場合は無効なタグが1つでもあれば何も変更せずに TagValidationError を返します
 func (u *TagUsecase) AddTags(ctx context.Context, request *dto.AddTagRequestDTO) (*dto.TagUpdateResponseDTO, error) {
 	// 画像の存在チェック
 	exists, err := u.tagRepository.ImageExists(ctx, request.ImageID)
@@ -103,6 +264,31 @@ func (u *TagUsecase) AddTags(ctx context.Context, request *dto.AddTagRequestDTO)
 		return nil, ErrImageNotFound
 	}
 
+	// 別名で指定されたタグは正規のタグとして保存する
+	aliases, err := u.loadAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// タグを検証して別名を解決
+	results := make([]dto.TagResultDTO, len(request.Tags))
+	tags := make([]valueobject.Tag, len(request.Tags))
+	for i, tagName := range request.Tags {
+		results[i].Input = tagName
+		tag, err := u.resolveTag(tagName, aliases)
+		if err != nil {
+			results[i].Status = dto.TagResultInvalid
+			results[i].Reason = err.Error()
+			continue
+		}
+		tags[i] = tag
+		results[i].Tag = tag.Name()
+		results[i].Status = dto.TagResultValid
+	}
+	if request.Strict && hasInvalidTags(results) {
+		return nil, &TagValidationError{Results: results}
+	}
+
 	// タグ付き画像情報を取得または作成
 	var taggedImage *entity.TaggedImage
 	taggedImage, err = u.tagRepository.FindTaggedImage(ctx, request.ImageID)
@@ -115,17 +301,28 @@ func (u *TagUsecase) AddTags(ctx context.Context, request *dto.AddTagRequestDTO)
 	}
 
 	// タグを追加
-	addedCount := 0
-	for _, tagName := range request.Tags {
-		tag, err := valueobject.NewTag(tagName)
-		if err != nil {
-			continue // 無効なタグはスキップ
+	addedTags := make([]string, 0, len(request.Tags))
+	requested := make(map[string]bool, len(request.Tags))
+	for i := range results {
+		if results[i].Status == dto.TagResultInvalid {
+			continue // 無効なタグや許可されていない名前空間のタグはスキップ
 		}
 
-		if taggedImage.AddTag(tag) {
-			addedCount++
+		name := tags[i].Name()
+		switch {
+		case requested[name]:
+			results[i].Status = dto.TagResultDuplicate
+			results[i].Reason = reasonDuplicateInRequest
+		case taggedImage.AddTag(tags[i]):
+			results[i].Status = dto.TagResultAdded
+			addedTags = append(addedTags, name)
+		default:
+			results[i].Status = dto.TagResultDuplicate
+			results[i].Reason = reasonAlreadyTagged
 		}
+		requested[name] = true
 	}
+	addedCount := len(addedTags)
 
 	// 変更があった場合のみ保存
 	if addedCount > 0 {
@@ -135,7 +332,7 @@ func (u *TagUsecase) AddTags(ctx context.Context, request *dto.AddTagRequestDTO)
 		}
 
 		// イベント発行
-		tagsUpdatedEvent := event.NewTagsUpdatedEvent(taggedImage)
+		tagsUpdatedEvent := event.NewTagsUpdatedEvent(taggedImage, request.ActorID, addedTags, nil)
 		u.eventDispatcher.Dispatch(ctx, tagsUpdatedEvent)
 	}
 
@@ -143,10 +340,26 @@ func (u *TagUsecase) AddTags(ctx context.Context, request *dto.AddTagRequestDTO)
 		ImageID:  request.ImageID,
 		Message:  fmt.Sprintf("%d tags added", addedCount),
 		Modified: addedCount,
+		Results:  results,
 	}, nil
 }
 
-// RemoveTags は画像からタグを削除します
+// resolveTag はタグ名を正規化して別名を正規のタグに解決し、付与できないタグの場合は理由をエラーで返します
+func (u *TagUsecase) resolveTag(tagName string, aliases valueobject.TagAliases) (valueobject.Tag, error) {
+	tag, err := valueobject.NewTag(tagName)
+	if err != nil {
+		return valueobject.Tag{}, err
+	}
+	tag = aliases.Resolve(tag)
+	if !u.namespaces.Allows(tag) {
+		return valueobject.Tag{}, ErrNamespaceNotAllowed
+	}
+	return tag, nil
+}
+
+// RemoveTags は画像からタグを削除し、指定されたタグごとの処理結果を返します
+// 別名で指定されたタグは正規のタグを削除し、別名の登録前に付けられた別名のままのタグも削除します
+// タグが指定されていない場合はすべてのタグを削除し、Strict の場合は無効なタグが1つでもあれば何も変更せずに TagValidationError を返します
 func (u *TagUsecase) RemoveTags(ctx context.Context, request *dto.RemoveTagRequestDTO) (*dto.TagUpdateResponseDTO, error) {
 	// 画像の存在チェック
 	exists, err := u.tagRepository.ImageExists(ctx, request.ImageID)
@@ -158,6 +371,36 @@ func (u *TagUsecase) RemoveTags(ctx context.Context, request *dto.RemoveTagReque
 		return nil, ErrImageNotFound
 	}
 
+	// 追加時と同じく別名で指定されたタグは正規のタグとして削除する
+	aliases := valueobject.TagAliases{}
+	if len(request.Tags) > 0 {
+		aliases, err = u.loadAliases(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// タグを検証して別名を解決（別名の登録前に付けられたタグを削除できるよう、指定されたままのタグも保持する）
+	results := make([]dto.TagResultDTO, len(request.Tags))
+	tags := make([]valueobject.Tag, len(request.Tags))
+	literals := make([]valueobject.Tag, len(request.Tags))
+	for i, tagName := range request.Tags {
+		results[i].Input = tagName
+		tag, err := valueobject.NewTag(tagName)
+		if err != nil {
+			results[i].Status = dto.TagResultInvalid
+			results[i].Reason = err.Error()
+			continue
+		}
+		literals[i] = tag
+		tags[i] = aliases.Resolve(tag)
+		results[i].Tag = tags[i].Name()
+		results[i].Status = dto.TagResultValid
+	}
+	if request.Strict && hasInvalidTags(results) {
+		return nil, &TagValidationError{Results: results}
+	}
+
 	// タグ付き画像情報を取得
 	taggedImage, err := u.tagRepository.FindTaggedImage(ctx, request.ImageID)
 	if err != nil {
@@ -166,31 +409,63 @@ func (u *TagUsecase) RemoveTags(ctx context.Context, request *dto.RemoveTagReque
 
 	// タグ情報がない場合は処理不要
 	if taggedImage == nil {
+		for i := range results {
+			if results[i].Status != dto.TagResultInvalid {
+				results[i].Status = dto.TagResultNotFound
+				results[i].Reason = reasonNotTagged
+			}
+		}
 		return &dto.TagUpdateResponseDTO{
 			ImageID:  request.ImageID,
 			Message:  "No tags to remove",
 			Modified: 0,
+			Results:  results,
 		}, nil
 	}
 
 	// タグを削除
-	removedCount := 0
+	var removedTags []string
 	if len(request.Tags) == 0 {
 		// タグが指定されていない場合は全て削除
-		removedCount = taggedImage.ClearTags()
+		removedTags = taggedImage.GetTagNames()
+		taggedImage.ClearTags()
+		results = make([]dto.TagResultDTO, len(removedTags))
+		for i, name := range removedTags {
+			results[i] = dto.TagResultDTO{Input: name, Tag: name, Status: dto.TagResultRemoved}
+		}
 	} else {
 		// 指定されたタグを削除
-		for _, tagName := range request.Tags {
-			tag, err := valueobject.NewTag(tagName)
-			if err != nil {
+		requested := make(map[string]bool, len(request.Tags))
+		for i := range results {
+			if results[i].Status == dto.TagResultInvalid {
 				continue // 無効なタグはスキップ
 			}
 
-			if taggedImage.RemoveTag(tag) {
-				removedCount++
+			name := tags[i].Name()
+			if requested[name] {
+				results[i].Status = dto.TagResultDuplicate
+				results[i].Reason = reasonDuplicateInRequest
+				continue
+			}
+			requested[name] = true
+
+			// 正規のタグと、別名の登録前に付けられた指定されたままのタグの両方を削除
+			removed := false
+			for _, tag := range uniqueTags(tags[i], literals[i]) {
+				if taggedImage.RemoveTag(tag) {
+					removedTags = append(removedTags, tag.Name())
+					removed = true
+				}
+			}
+			if removed {
+				results[i].Status = dto.TagResultRemoved
+			} else {
+				results[i].Status = dto.TagResultNotFound
+				results[i].Reason = reasonNotTagged
 			}
 		}
 	}
+	removedCount := len(removedTags)
 
 	// 変更があった場合のみ保存
 	if removedCount > 0 {
@@ -200,7 +475,7 @@ func (u *TagUsecase) RemoveTags(ctx context.Context, request *dto.RemoveTagReque
 		}
 
 		// イベント発行
-		tagsUpdatedEvent := event.NewTagsUpdatedEvent(taggedImage)
+		tagsUpdatedEvent := event.NewTagsUpdatedEvent(taggedImage, request.ActorID, nil, removedTags)
 		u.eventDispatcher.Dispatch(ctx, tagsUpdatedEvent)
 	}
 
@@ -208,9 +483,28 @@ func (u *TagUsecase) RemoveTags(ctx context.Context, request *dto.RemoveTagReque
 		ImageID:  request.ImageID,
 		Message:  fmt.Sprintf("%d tags removed", removedCount),
 		Modified: removedCount,
+		Results:  results,
 	}, nil
 }
 
+// uniqueTags は同じタグを除いたタグの一覧を返します
+func uniqueTags(tag, other valueobject.Tag) []valueobject.Tag {
+	if tag.Name() == other.Name() {
+		return []valueobject.Tag{tag}
+	}
+	return []valueobject.Tag{tag, other}
+}
+
+// hasInvalidTags はタグごとの処理結果に無効なタグが含まれているかを判定します
+func hasInvalidTags(results []dto.TagResultDTO) bool {
+	for _, result := range results {
+		if result.Status == dto.TagResultInvalid {
+			return true
+		}
+	}
+	return false
+}
+
 // FindImagesByTag はタグで画像を検索します
 func (u *TagUsecase) FindImagesByTag(ctx context.Context, tagName string) ([]string, error) {
 	// タグの値オブジェクトを作成
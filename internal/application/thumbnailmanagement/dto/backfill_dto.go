@@ -0,0 +1,46 @@
+package dto
+
+// BackfillReason はサムネイルを再生成する理由を表します
+type BackfillReason string
+
+const (
+	// BackfillReasonMissing はサムネイルが存在しないことを表します
+	BackfillReasonMissing BackfillReason = "missing"
+	// BackfillReasonStale はサムネイルが古いレンディション設定または透かし設定で生成されていることを表します
+	BackfillReasonStale BackfillReason = "stale"
+	// BackfillReasonRequested は明示的に再生成が指定されたことを表します
+	BackfillReasonRequested BackfillReason = "requested"
+)
+
+// BackfillOptions はサムネイル一括再生成のオプション
+type BackfillOptions struct {
+	Concurrency int      // 同時に処理する画像数
+	PageSize    int      // 1回のスキャンで取得する画像数
+	Cursor      string   // 再開位置（空の場合は先頭から）
+	MaxImages   int      // 走査する画像数の上限（0は無制限、ページ単位で判定）
+	ImageIDs    []string // 明示的に再生成する画像ID（指定時は全件走査を行わない）
+	Force       bool     // 走査したすべての画像を再生成する
+	DryRun      bool     // 対象の判定のみ行い再生成しない
+
+	// OnCheckpoint はページの処理が完了するたびに次の再開位置を受け取ります（DryRun の場合は呼び出されません）
+	OnCheckpoint func(cursor string)
+}
+
+// BackfillFailureDTO は再生成に失敗した画像の情報
+type BackfillFailureDTO struct {
+	ImageID string `json:"imageId"`
+	Error   string `json:"error"`
+}
+
+// BackfillReportDTO はサムネイル一括再生成の結果
+type BackfillReportDTO struct {
+	ConfigVersion string                 `json:"configVersion"`
+	Scanned       int                    `json:"scanned"`
+	Candidates    map[BackfillReason]int `json:"candidates"`
+	Regenerated   int                    `json:"regenerated"`
+	Skipped       int                    `json:"skipped"`
+	Failed        []BackfillFailureDTO   `json:"failed,omitempty"`
+	DryRun        bool                   `json:"dryRun"`
+	NextCursor    string                 `json:"nextCursor,omitempty"`
+	Completed     bool                   `json:"completed"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+// RenderRequestDTO はオンデマンド画像変換リクエストのDTO
+type RenderRequestDTO struct {
+	Preset  string `json:"preset,omitempty"`
+	Width   int    `json:"width,omitempty"`
+	Height  int    `json:"height,omitempty"`
+	Fit     string `json:"fit,omitempty"`
+	Gravity string `json:"gravity,omitempty"`
+	Format  string `json:"format,omitempty"`
+	Quality int    `json:"quality,omitempty"`
+}
+
+// RenderResponseDTO はオンデマンド画像変換レスポンスのDTO
+type RenderResponseDTO struct {
+	ImageID     string `json:"imageId"`
+	URL         string `json:"url"`
+	ContentType string `json:"contentType"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	Cached      bool   `json:"cached"`
+}
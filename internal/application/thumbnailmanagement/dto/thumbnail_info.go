@@ -1,12 +1,31 @@
 package dto
 
+// RenditionDTO はサムネイルレンディションのデータ転送オブジェクト
+type RenditionDTO struct {
+	Name        string      `json:"name"`
+	URL         string      `json:"url"`
+	Width       int         `json:"width"`
+	Height      int         `json:"height"`
+	ContentType string      `json:"contentType"`
+	Crop        *CropBoxDTO `json:"crop,omitempty"`
+}
+
+// CropBoxDTO はレンディションの生成に使用した元画像の領域のデータ転送オブジェクト
+type CropBoxDTO struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
 // ThumbnailInfoDTO はサムネイル情報のデータ転送オブジェクト
 type ThumbnailInfoDTO struct {
-	ImageID      string `json:"imageId"`
-	ThumbnailURL string `json:"thumbnailUrl"`
-	Width        int    `json:"width"`
-	Height       int    `json:"height"`
-	ContentType  string `json:"contentType"`
+	ImageID      string         `json:"imageId"`
+	ThumbnailURL string         `json:"thumbnailUrl"`
+	Width        int            `json:"width"`
+	Height       int            `json:"height"`
+	ContentType  string         `json:"contentType"`
+	Renditions   []RenditionDTO `json:"renditions,omitempty"`
 }
 
 // ThumbnailGenerationRequestDTO はサムネイル生成リクエストのDTO
@@ -17,10 +36,19 @@ type ThumbnailGenerationRequestDTO struct {
 
 // ThumbnailGenerationResponseDTO はサムネイル生成レスポンスのDTO
 type ThumbnailGenerationResponseDTO struct {
-	Success      bool   `json:"success"`
-	ImageID      string `json:"imageId"`
-	ThumbnailURL string `json:"thumbnailUrl"`
-	Width        int    `json:"width"`
-	Height       int    `json:"height"`
-	Message      string `json:"message,omitempty"`
+	Success      bool           `json:"success"`
+	ImageID      string         `json:"imageId"`
+	ThumbnailURL string         `json:"thumbnailUrl"`
+	Width        int            `json:"width"`
+	Height       int            `json:"height"`
+	Renditions   []RenditionDTO `json:"renditions,omitempty"`
+	Message      string         `json:"message,omitempty"`
+}
+
+// ThumbnailRetryReportDTO はサムネイル生成の再試行結果
+type ThumbnailRetryReportDTO struct {
+	Due          int `json:"due"`
+	Succeeded    int `json:"succeeded"`
+	Failed       int `json:"failed"`
+	DeadLettered int `json:"deadLettered"`
 }
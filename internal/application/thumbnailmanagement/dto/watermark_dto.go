@@ -0,0 +1,26 @@
+package dto
+
+// WatermarkRequestDTO は透かし設定の登録リクエストのDTO
+type WatermarkRequestDTO struct {
+	Type       string   `json:"type"`
+	Text       string   `json:"text,omitempty"`
+	ImageID    string   `json:"imageId,omitempty"` // 透かしに使用するアップロード済み画像のID
+	Position   string   `json:"position,omitempty"`
+	Opacity    float64  `json:"opacity,omitempty"`
+	Scale      float64  `json:"scale,omitempty"`
+	Renditions []string `json:"renditions"`
+}
+
+// WatermarkSettingDTO は透かし設定のDTO
+type WatermarkSettingDTO struct {
+	Scope      string   `json:"scope"`
+	Type       string   `json:"type"`
+	Text       string   `json:"text,omitempty"`
+	ImageKey   string   `json:"imageKey,omitempty"`
+	Position   string   `json:"position"`
+	Opacity    float64  `json:"opacity"`
+	Scale      float64  `json:"scale"`
+	Renditions []string `json:"renditions"`
+	UpdatedAt  string   `json:"updatedAt"`
+	Inherited  bool     `json:"inherited"` // ユーザー設定がなくデフォルト設定を返している場合は true
+}
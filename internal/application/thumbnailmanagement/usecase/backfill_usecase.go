@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"cloudpix/internal/application/thumbnailmanagement/dto"
+	"cloudpix/internal/domain/imagemanagement/aggregate"
+	imagerepository "cloudpix/internal/domain/imagemanagement/repository"
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"cloudpix/internal/logging"
+	"context"
+	"fmt"
+	"sync"
+)
+
+const (
+	// DefaultBackfillConcurrency は同時に処理する画像数のデフォルト値
+	DefaultBackfillConcurrency = 4
+	// DefaultBackfillPageSize は1回のスキャンで取得する画像数のデフォルト値
+	DefaultBackfillPageSize = 100
+)
+
+// BackfillUsecase は既存画像のサムネイルを一括で再生成するユースケース
+type BackfillUsecase struct {
+	imageRepository  imagerepository.ImageRepository
+	thumbnailUsecase *ThumbnailGenerationUsecase
+	renditionSpecs   []valueobject.RenditionSpec
+	bucket           string
+	logger           logging.Logger
+}
+
+// NewBackfillUsecase は新しいサムネイル一括再生成ユースケースを作成します
+func NewBackfillUsecase(
+	imageRepository imagerepository.ImageRepository,
+	thumbnailUsecase *ThumbnailGenerationUsecase,
+	renditionSpecs []valueobject.RenditionSpec,
+	bucket string,
+	logger logging.Logger,
+) *BackfillUsecase {
+	return &BackfillUsecase{
+		imageRepository:  imageRepository,
+		thumbnailUsecase: thumbnailUsecase,
+		renditionSpecs:   renditionSpecs,
+		bucket:           bucket,
+		logger:           logger,
+	}
+}
+
+// backfillTarget は再生成の対象となる画像と理由
+type backfillTarget struct {
+	image  *aggregate.ImageAggregate
+	reason dto.BackfillReason
+}
+
+// Run はサムネイルが存在しない・古い・明示的に指定された画像のサムネイルを再生成します
+// コンテキストがキャンセルされた場合は処理中のページを完了してから終了し、再開位置を返します
+func (u *BackfillUsecase) Run(ctx context.Context, options dto.BackfillOptions) (*dto.BackfillReportDTO, error) {
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultBackfillConcurrency
+	}
+	if options.PageSize <= 0 {
+		options.PageSize = DefaultBackfillPageSize
+	}
+
+	report := &dto.BackfillReportDTO{
+		ConfigVersion: valueobject.RenditionConfigVersion(u.renditionSpecs),
+		Candidates:    make(map[dto.BackfillReason]int),
+		DryRun:        options.DryRun,
+	}
+
+	// 画像IDが指定された場合はその画像のみを再生成
+	if len(options.ImageIDs) > 0 {
+		targets := make([]backfillTarget, 0, len(options.ImageIDs))
+		for _, imageID := range options.ImageIDs {
+			image, err := u.imageRepository.FindByID(ctx, imageID)
+			if err != nil {
+				report.Failed = append(report.Failed, dto.BackfillFailureDTO{ImageID: imageID, Error: err.Error()})
+				continue
+			}
+			if image == nil {
+				report.Failed = append(report.Failed, dto.BackfillFailureDTO{ImageID: imageID, Error: "image not found"})
+				continue
+			}
+			targets = append(targets, backfillTarget{image: image, reason: dto.BackfillReasonRequested})
+		}
+		report.Scanned = len(options.ImageIDs)
+		u.process(ctx, targets, options, report)
+		report.Completed = true
+		return report, nil
+	}
+
+	// 中断の指示はページの境界でのみ確認し、処理中のページは最後まで完了させる
+	workCtx := context.WithoutCancel(ctx)
+
+	// 所有者ごとの現在の透かし設定の識別子（実行中は同じ設定を使用する）
+	watermarks := make(map[string]string)
+
+	cursor := options.Cursor
+	for {
+		// キャンセルされた場合は現在の位置で中断
+		if ctx.Err() != nil {
+			break
+		}
+
+		page, err := u.imageRepository.FindPage(workCtx, cursor, options.PageSize)
+		if err != nil {
+			report.NextCursor = cursor
+			return report, fmt.Errorf("failed to scan images: %w", err)
+		}
+
+		targets := make([]backfillTarget, 0, len(page.Images))
+		for _, image := range page.Images {
+			report.Scanned++
+			reason, ok, err := u.reasonFor(workCtx, image, report.ConfigVersion, watermarks, options.Force)
+			if err != nil {
+				report.NextCursor = cursor
+				return report, err
+			}
+			if ok {
+				targets = append(targets, backfillTarget{image: image, reason: reason})
+			} else {
+				report.Skipped++
+			}
+		}
+		u.process(workCtx, targets, options, report)
+
+		// ページ単位で再開位置を記録（再生成していない試行では記録しない）
+		cursor = page.NextCursor
+		if options.OnCheckpoint != nil && !options.DryRun {
+			options.OnCheckpoint(cursor)
+		}
+
+		if cursor == "" {
+			report.Completed = true
+			break
+		}
+		if options.MaxImages > 0 && report.Scanned >= options.MaxImages {
+			break
+		}
+	}
+
+	report.NextCursor = cursor
+	return report, nil
+}
+
+// reasonFor は画像のサムネイルを再生成する理由を判定します
+// 生成時の透かし設定が所有者の現在の設定と異なるサムネイルも古いものとして扱います
+func (u *BackfillUsecase) reasonFor(ctx context.Context, image *aggregate.ImageAggregate, configVersion string, watermarks map[string]string, force bool) (dto.BackfillReason, bool, error) {
+	if !image.Image.HasThumbnail {
+		return dto.BackfillReasonMissing, true, nil
+	}
+	if image.ThumbnailVersion != configVersion {
+		return dto.BackfillReasonStale, true, nil
+	}
+
+	watermark, err := u.currentWatermark(ctx, image.Image.Owner, watermarks)
+	if err != nil {
+		return "", false, err
+	}
+	if image.ThumbnailWatermark != watermark {
+		return dto.BackfillReasonStale, true, nil
+	}
+
+	if force {
+		return dto.BackfillReasonRequested, true, nil
+	}
+	return "", false, nil
+}
+
+// currentWatermark は所有者に現在適用される透かし設定の識別子を返します（取得済みの所有者はキャッシュから返します）
+func (u *BackfillUsecase) currentWatermark(ctx context.Context, owner string, watermarks map[string]string) (string, error) {
+	if watermark, ok := watermarks[owner]; ok {
+		return watermark, nil
+	}
+
+	setting, err := findWatermarkSetting(ctx, u.thumbnailUsecase.watermarkRepository, owner)
+	if err != nil {
+		return "", err
+	}
+	watermark := ""
+	if setting != nil {
+		watermark = setting.Fingerprint(u.renditionSpecs)
+	}
+	watermarks[owner] = watermark
+	return watermark, nil
+}
+
+// process は対象の画像を同時実行数の上限内で再生成し、結果をレポートに集計します
+func (u *BackfillUsecase) process(ctx context.Context, targets []backfillTarget, options dto.BackfillOptions, report *dto.BackfillReportDTO) {
+	for _, target := range targets {
+		report.Candidates[target.reason]++
+	}
+	if options.DryRun || len(targets) == 0 {
+		return
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, options.Concurrency)
+	)
+	for _, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(target backfillTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			imageID := target.image.GetImageID()
+			err := u.regenerate(ctx, target.image)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				u.logger.Error(err, "Failed to regenerate thumbnail", map[string]interface{}{
+					"imageId": imageID,
+					"reason":  string(target.reason),
+				})
+				report.Failed = append(report.Failed, dto.BackfillFailureDTO{ImageID: imageID, Error: err.Error()})
+				return
+			}
+			report.Regenerated++
+		}(target)
+	}
+	wg.Wait()
+}
+
+// regenerate は1つの画像のサムネイルを再生成します
+func (u *BackfillUsecase) regenerate(ctx context.Context, image *aggregate.ImageAggregate) error {
+	if image.Image.S3ObjectKey == "" {
+		return fmt.Errorf("image has no object key")
+	}
+
+	result, err := u.thumbnailUsecase.ProcessImage(ctx, u.bucket, image.Image.S3ObjectKey)
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		return fmt.Errorf("%s", result.Message)
+	}
+	return nil
+}
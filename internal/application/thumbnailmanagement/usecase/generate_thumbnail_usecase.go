@@ -2,48 +2,75 @@ package usecase
 
 import (
 	"cloudpix/internal/application/thumbnailmanagement/dto"
+	imagerepository "cloudpix/internal/domain/imagemanagement/repository"
+	imagevalueobject "cloudpix/internal/domain/imagemanagement/valueobject"
 	"cloudpix/internal/domain/shared/event/dispatcher"
 	"cloudpix/internal/domain/thumbnailmanagement/entity"
 	"cloudpix/internal/domain/thumbnailmanagement/event"
 	"cloudpix/internal/domain/thumbnailmanagement/repository"
 	"cloudpix/internal/domain/thumbnailmanagement/service"
 	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"cloudpix/internal/logging"
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // ThumbnailGenerationUsecase はサムネイル生成ユースケース
 type ThumbnailGenerationUsecase struct {
-	thumbnailRepo     repository.ThumbnailRepository
-	storageService    service.StorageService
-	processingService service.ImageProcessingService
-	eventDispatcher   dispatcher.EventDispatcher
-	thumbnailSize     int
-	awsRegion         string
+	thumbnailRepo        repository.ThumbnailRepository
+	similarityRepository imagerepository.SimilarityRepository
+	imageRepository      imagerepository.ImageRepository
+	watermarkRepository  repository.WatermarkRepository
+	stateRepository      repository.ProcessingStateRepository
+	storageService       service.StorageService
+	processingService    service.ImageProcessingService
+	processingMetrics    service.ProcessingMetrics
+	eventDispatcher      dispatcher.EventDispatcher
+	renditionSpecs       []valueobject.RenditionSpec
+	maxAttempts          int
+	awsRegion            string
 }
 
 // NewThumbnailGenerationUsecase は新しいサムネイル生成ユースケースを作成します
 func NewThumbnailGenerationUsecase(
 	thumbnailRepo repository.ThumbnailRepository,
+	similarityRepository imagerepository.SimilarityRepository,
+	imageRepository imagerepository.ImageRepository,
+	watermarkRepository repository.WatermarkRepository,
+	stateRepository repository.ProcessingStateRepository,
 	storageService service.StorageService,
 	processingService service.ImageProcessingService,
+	processingMetrics service.ProcessingMetrics,
 	eventDispatcher dispatcher.EventDispatcher,
-	thumbnailSize int,
+	renditionSpecs []valueobject.RenditionSpec,
+	maxAttempts int,
 	awsRegion string,
 ) *ThumbnailGenerationUsecase {
+	if maxAttempts <= 0 {
+		maxAttempts = entity.DefaultMaxProcessingAttempts
+	}
+
 	return &ThumbnailGenerationUsecase{
-		thumbnailRepo:     thumbnailRepo,
-		storageService:    storageService,
-		processingService: processingService,
-		eventDispatcher:   eventDispatcher,
-		thumbnailSize:     thumbnailSize,
-		awsRegion:         awsRegion,
+		thumbnailRepo:        thumbnailRepo,
+		similarityRepository: similarityRepository,
+		imageRepository:      imageRepository,
+		watermarkRepository:  watermarkRepository,
+		stateRepository:      stateRepository,
+		storageService:       storageService,
+		processingService:    processingService,
+		processingMetrics:    processingMetrics,
+		eventDispatcher:      eventDispatcher,
+		renditionSpecs:       renditionSpecs,
+		maxAttempts:          maxAttempts,
+		awsRegion:            awsRegion,
 	}
 }
 
-// ProcessImage はS3に保存された画像からサムネイルを生成します
+// ProcessImage はS3に保存された画像からサムネイルを生成し、処理状態を記録します
 func (u *ThumbnailGenerationUsecase) ProcessImage(ctx context.Context, bucket, key string) (*dto.ThumbnailGenerationResponseDTO, error) {
 	// アップロードディレクトリ以外は処理しない
 	if !strings.HasPrefix(key, "uploads/") {
@@ -53,81 +80,303 @@ func (u *ThumbnailGenerationUsecase) ProcessImage(ctx context.Context, bucket, k
 		}, nil
 	}
 
-	// S3から画像を取得
-	imageData, err := u.storageService.FetchImage(ctx, bucket, key)
+	// 画像IDを抽出
+	imageID, err := u.processingService.ExtractImageID(filepath.Base(key))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch image: %w", err)
+		return &dto.ThumbnailGenerationResponseDTO{
+			Success: false,
+			Message: "Could not extract image ID from filename",
+		}, nil
 	}
 
-	// 画像をデコード
-	width, height, err := u.processingService.DecodeImage(imageData)
-	if err != nil {
+	response, err := u.generate(ctx, bucket, key, imageID)
+	if errors.Is(err, imagevalueobject.ErrImageTooLarge) {
+		// 上限を超える画像は再試行しても処理できないため拒否として扱う
+		if stateErr := u.recordProcessingState(ctx, imageID, key, nil, err); stateErr != nil {
+			return nil, stateErr
+		}
 		return &dto.ThumbnailGenerationResponseDTO{
 			Success: false,
-			Message: fmt.Sprintf("Unsupported image format: %v", err),
+			ImageID: imageID,
+			Message: fmt.Sprintf("Image rejected: %v", err),
 		}, nil
 	}
-
-	// TODO: サムネイルを生成
-	if _, err := valueobject.NewDimensions(width, height); err != nil {
-		return nil, fmt.Errorf("invalid image dimensions: %w", err)
+	if stateErr := u.recordProcessingState(ctx, imageID, key, response, err); stateErr != nil && err == nil {
+		return nil, stateErr
 	}
+	return response, err
+}
 
-	// サムネイルを生成
-	thumbnailData, thumbnailDimensions, err := u.processingService.GenerateThumbnail(imageData, u.thumbnailSize)
+// recordProcessingState はサムネイル生成の結果を処理状態に記録します
+// エラーは一時的な失敗として再試行し、生成できなかった結果は再試行しても成功しないためデッドレターにします
+func (u *ThumbnailGenerationUsecase) recordProcessingState(ctx context.Context, imageID, key string, response *dto.ThumbnailGenerationResponseDTO, processErr error) error {
+	state, err := u.stateRepository.FindByImageID(ctx, imageID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate thumbnail: %w", err)
+		return fmt.Errorf("failed to find processing state: %w", err)
+	}
+	// 再試行中でなければ新しい処理として試行回数を数え直す
+	if state == nil || !state.IsRetrying() {
+		state = entity.NewProcessingState(imageID, key)
 	}
 
-	// サムネイルのS3キーを生成
-	filename := filepath.Base(key)
-	thumbnailKey := fmt.Sprintf("thumbnails/%s", filename)
+	now := time.Now()
+	switch {
+	case errors.Is(processErr, imagevalueobject.ErrImageTooLarge):
+		state.RecordRejection(processErr.Error(), now)
+	case processErr != nil:
+		state.RecordFailure(processErr.Error(), true, u.maxAttempts, now)
+	case !response.Success:
+		state.RecordFailure(response.Message, false, u.maxAttempts, now)
+	default:
+		state.RecordSuccess(now)
+	}
 
-	// サムネイルをS3にアップロード
-	err = u.storageService.UploadThumbnail(ctx, bucket, thumbnailKey, thumbnailData)
+	err = u.stateRepository.Save(ctx, state)
+	if errors.Is(err, repository.ErrProcessingStateImageNotFound) {
+		// 処理中に削除された画像は記録する対象がないため、再試行もしない
+		logging.FromContext(ctx).Info("Image was deleted during processing, processing state is not recorded", map[string]interface{}{
+			"imageId": imageID,
+		})
+		return nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload thumbnail: %w", err)
+		return fmt.Errorf("failed to record processing state: %w", err)
 	}
+	return nil
+}
 
-	// サムネイルのURLを生成
-	thumbnailURL := u.storageService.GetObjectURL(bucket, thumbnailKey)
+// generate は画像のサムネイルを生成して保存します
+func (u *ThumbnailGenerationUsecase) generate(ctx context.Context, bucket, key, imageID string) (*dto.ThumbnailGenerationResponseDTO, error) {
+	started := time.Now()
+	stopMemoryTracking := u.processingMetrics.TrackMemory()
+	defer stopMemoryTracking()
 
-	// 画像IDを抽出
-	imageID, err := u.processingService.ExtractImageID(filename)
+	// 透かし設定を対象のレンディションに適用
+	specs, watermark, err := u.watermarkedSpecs(ctx, bucket, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 元画像はメモリに読み込まずにストリームから一度だけデコードし、全レンディションで共有
+	decodeStarted := time.Now()
+	source, err := u.decodeSource(ctx, bucket, key, hasAnimatedSpec(specs))
+	if errors.Is(err, imagevalueobject.ErrImageTooLarge) || errors.Is(err, service.ErrSourceRead) {
+		return nil, err
+	}
 	if err != nil {
 		return &dto.ThumbnailGenerationResponseDTO{
 			Success: false,
-			Message: "Could not extract image ID from filename",
+			Message: fmt.Sprintf("Unsupported image format: %v", err),
 		}, nil
 	}
+	stats := service.ProcessingStats{DecodeLatency: time.Since(decodeStarted)}
+
+	// デコード済みの画像から全レンディションを生成
+	renditionStarted := time.Now()
+	result, err := u.processingService.GenerateRenditions(source, specs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate thumbnail: %w", err)
+	}
+	stats.RenditionLatency = time.Since(renditionStarted)
+	outputs := result.Renditions
+	if len(outputs) == 0 {
+		return nil, fmt.Errorf("no renditions configured")
+	}
+
+	// 各レンディションをS3にアップロード
+	uploadStarted := time.Now()
+	filename := filepath.Base(key)
+	renditions := make([]entity.Rendition, 0, len(outputs))
+	for _, output := range outputs {
+		renditionKey := fmt.Sprintf("thumbnails/%s/%s", output.Spec.Name(), filename)
 
-	// サムネイルエンティティを作成
+		err = u.storageService.UploadThumbnail(ctx, bucket, renditionKey, output.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload rendition %s: %w", output.Spec.Name(), err)
+		}
+
+		renditions = append(renditions, entity.NewRendition(
+			output.Spec.Name(),
+			renditionKey,
+			u.storageService.GetObjectURL(bucket, renditionKey),
+			output.Dimensions,
+			output.Data.ContentType,
+		))
+	}
+
+	stats.UploadLatency = time.Since(uploadStarted)
+
+	// 処理時間とピークメモリ使用量を記録
+	stats.PeakMemoryBytes = stopMemoryTracking()
+	stats.TotalLatency = time.Since(started)
+	u.processingMetrics.Record(ctx, stats)
+
+	// 最初のレンディションを代表サムネイルとしてエンティティを作成
+	primary := renditions[0]
 	thumbnail := entity.NewThumbnail(
 		imageID,
-		thumbnailKey,
-		thumbnailURL,
-		thumbnailDimensions,
+		primary.Key,
+		primary.URL,
+		primary.Dimensions,
 		key,
-		thumbnailData.ContentType,
+		primary.ContentType,
 	)
+	for _, rendition := range renditions {
+		thumbnail.AddRendition(rendition)
+	}
+	perceptualHash := imagevalueobject.NewPerceptualHash(result.PerceptualHash)
+	thumbnail.SetPerceptualHash(perceptualHash.String())
+	thumbnail.SetPalette(result.Palette)
+	thumbnail.SetPlaceholder(result.BlurHash, result.LQIP)
+	thumbnail.SetConfigVersion(valueobject.RenditionConfigVersion(u.renditionSpecs))
+	thumbnail.SetWatermark(watermark)
+
+	// 再生成の場合は以前の知覚ハッシュをインデックスから外すために取得しておく
+	var previousHash string
+	if previous, err := u.thumbnailRepo.FindByImageID(ctx, imageID); err == nil {
+		previousHash = previous.PerceptualHash
+	}
 
 	// サムネイル情報をリポジトリに保存
 	err = u.thumbnailRepo.Save(ctx, thumbnail)
+	if errors.Is(err, repository.ErrThumbnailImageNotFound) {
+		// 処理中に削除された画像は類似画像のインデックスにも登録せず、イベントも発行しない
+		logging.FromContext(ctx).Info("Image was deleted during processing, thumbnail is not recorded", map[string]interface{}{
+			"imageId": imageID,
+		})
+		return &dto.ThumbnailGenerationResponseDTO{
+			Success: false,
+			ImageID: imageID,
+			Message: "Image was deleted during processing",
+		}, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to save thumbnail metadata: %w", err)
 	}
 
+	// 類似画像検索のインデックスを更新
+	if err := u.updateSimilarityIndex(ctx, imageID, previousHash, perceptualHash); err != nil {
+		return nil, err
+	}
+
 	// イベントを発行
-	thumbnailEvent := event.NewThumbnailGeneratedEvent(thumbnail)
+	thumbnailEvent := event.NewThumbnailGeneratedEvent(thumbnail, source.Dimensions(), source.EXIF())
 	u.eventDispatcher.Dispatch(ctx, thumbnailEvent)
 
 	// レスポンスを作成
 	return &dto.ThumbnailGenerationResponseDTO{
 		Success:      true,
 		ImageID:      imageID,
-		ThumbnailURL: thumbnailURL,
+		ThumbnailURL: thumbnail.ThumbnailURL,
 		Width:        thumbnail.GetWidth(),
 		Height:       thumbnail.GetHeight(),
+		Renditions:   toRenditionDTOs(thumbnail.Renditions),
 		Message:      "Thumbnail generated successfully",
 	}, nil
 }
+
+// decodeSource は元画像をストリームとして開いてデコードします
+func (u *ThumbnailGenerationUsecase) decodeSource(ctx context.Context, bucket, key string, keepAnimation bool) (service.SourceImage, error) {
+	reader, contentType, err := u.storageService.OpenImage(ctx, bucket, key)
+	if errors.Is(err, imagevalueobject.ErrImageTooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrSourceRead, err)
+	}
+	defer reader.Close()
+
+	return u.processingService.Decode(reader, contentType, keepAnimation)
+}
+
+// hasAnimatedSpec はアニメーションを維持するレンディションが含まれるかを判定します
+func hasAnimatedSpec(specs []valueobject.RenditionSpec) bool {
+	for _, spec := range specs {
+		if spec.Animated() {
+			return true
+		}
+	}
+	return false
+}
+
+// watermarkedSpecs は画像の所有者の透かし設定（なければデフォルト設定）を対象のレンディションに設定します
+// 適用した透かし設定の識別子（透かしを適用しない場合は空文字列）も返します
+func (u *ThumbnailGenerationUsecase) watermarkedSpecs(ctx context.Context, bucket, imageID string) ([]valueobject.RenditionSpec, string, error) {
+	// 所有者が特定できない画像はデフォルト設定のみを適用
+	owner := ""
+	if imageAggregate, err := u.imageRepository.FindByID(ctx, imageID); err == nil {
+		owner = imageAggregate.Image.Owner
+	}
+
+	setting, err := findWatermarkSetting(ctx, u.watermarkRepository, owner)
+	if err != nil {
+		return nil, "", err
+	}
+	if setting == nil {
+		return u.renditionSpecs, "", nil
+	}
+
+	// 画像の透かしは透かし画像を読み込んでおく
+	watermark := setting.Watermark
+	if watermark.Type() == valueobject.WatermarkImage {
+		overlay, err := u.storageService.FetchImage(ctx, bucket, watermark.ImageKey())
+		if err != nil {
+			// 透かし画像が削除・破損していてもサムネイル生成は止めず、透かしなしで生成する
+			logging.FromContext(ctx).Error(err, "Failed to fetch watermark image, generating thumbnails without watermark", map[string]interface{}{
+				"imageId":  imageID,
+				"scope":    setting.Scope,
+				"imageKey": watermark.ImageKey(),
+			})
+			return u.renditionSpecs, "", nil
+		}
+		watermark = watermark.WithOverlay(overlay)
+	}
+
+	specs := make([]valueobject.RenditionSpec, len(u.renditionSpecs))
+	for i, spec := range u.renditionSpecs {
+		if setting.AppliesTo(spec.Name()) {
+			spec = spec.WithWatermark(watermark)
+		}
+		specs[i] = spec
+	}
+	return specs, setting.Fingerprint(u.renditionSpecs), nil
+}
+
+// updateSimilarityIndex は知覚ハッシュのインデックスを更新します
+func (u *ThumbnailGenerationUsecase) updateSimilarityIndex(ctx context.Context, imageID, previousHash string, hash imagevalueobject.PerceptualHash) error {
+	if previousHash != "" && previousHash != hash.String() {
+		if previous, err := imagevalueobject.ParsePerceptualHash(previousHash); err == nil {
+			if err := u.similarityRepository.Remove(ctx, imageID, previous); err != nil {
+				return fmt.Errorf("failed to remove previous perceptual hash: %w", err)
+			}
+		}
+	}
+
+	if err := u.similarityRepository.Index(ctx, imageID, hash); err != nil {
+		return fmt.Errorf("failed to index perceptual hash: %w", err)
+	}
+	return nil
+}
+
+// toRenditionDTOs はレンディションエンティティをDTOに変換します
+func toRenditionDTOs(renditions []entity.Rendition) []dto.RenditionDTO {
+	renditionDTOs := make([]dto.RenditionDTO, len(renditions))
+	for i, rendition := range renditions {
+		renditionDTOs[i] = dto.RenditionDTO{
+			Name:        rendition.Name,
+			URL:         rendition.URL,
+			Width:       rendition.GetWidth(),
+			Height:      rendition.GetHeight(),
+			ContentType: rendition.ContentType,
+		}
+		if cropBox := rendition.Dimensions.CropBox(); !cropBox.IsZero() {
+			renditionDTOs[i].Crop = &dto.CropBoxDTO{
+				X:      cropBox.X(),
+				Y:      cropBox.Y(),
+				Width:  cropBox.Width(),
+				Height: cropBox.Height(),
+			}
+		}
+	}
+	return renditionDTOs
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"cloudpix/internal/application/thumbnailmanagement/dto"
+	imagerepository "cloudpix/internal/domain/imagemanagement/repository"
+	imagevalueobject "cloudpix/internal/domain/imagemanagement/valueobject"
+	"cloudpix/internal/domain/thumbnailmanagement/repository"
+	"cloudpix/internal/domain/thumbnailmanagement/service"
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// 定義済みエラー
+var (
+	ErrImageNotFound        = errors.New("指定された画像が見つかりません")
+	ErrInvalidRenderOptions = errors.New("無効な変換パラメータです")
+	ErrRenderNotAllowed     = errors.New("許可されていない変換パラメータです")
+	ErrUnsupportedFormat    = errors.New("サポートされていない画像形式です")
+	ErrSourceTooLarge       = errors.New("元画像が処理できる大きさの上限を超えています")
+)
+
+// RenderUsecase はオンデマンド画像変換のユースケース
+type RenderUsecase struct {
+	imageRepository     imagerepository.ImageRepository
+	watermarkRepository repository.WatermarkRepository
+	storageService      service.StorageService
+	processingService   service.ImageProcessingService
+	presets             []valueobject.RenderPreset
+	bucketName          string
+}
+
+// NewRenderUsecase は新しい画像変換ユースケースを作成します
+func NewRenderUsecase(
+	imageRepository imagerepository.ImageRepository,
+	watermarkRepository repository.WatermarkRepository,
+	storageService service.StorageService,
+	processingService service.ImageProcessingService,
+	presets []valueobject.RenderPreset,
+	bucketName string,
+) *RenderUsecase {
+	return &RenderUsecase{
+		imageRepository:     imageRepository,
+		watermarkRepository: watermarkRepository,
+		storageService:      storageService,
+		processingService:   processingService,
+		presets:             presets,
+		bucketName:          bucketName,
+	}
+}
+
+// Render は画像を変換し、S3にキャッシュされた結果のURLを返します
+func (u *RenderUsecase) Render(ctx context.Context, imageID string, request *dto.RenderRequestDTO) (*dto.RenderResponseDTO, error) {
+	// 変換パラメータを解決
+	options, err := u.resolveOptions(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// 画像の存在チェック
+	exists, err := u.imageRepository.Exists(ctx, imageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check image existence: %w", err)
+	}
+	if !exists {
+		return nil, ErrImageNotFound
+	}
+
+	imageAggregate, err := u.imageRepository.FindByID(ctx, imageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find image: %w", err)
+	}
+	image := imageAggregate.Image
+
+	sourceContentType := image.ContentType.String()
+	if !u.processingService.IsSupported(sourceContentType) {
+		return nil, ErrUnsupportedFormat
+	}
+
+	// 所有者またはデフォルトの透かし設定を適用（透かしごとに別のキャッシュになる）
+	setting, err := findWatermarkSetting(ctx, u.watermarkRepository, image.Owner)
+	if err != nil {
+		return nil, err
+	}
+	if setting != nil && setting.AppliesToRenders() {
+		options = options.WithWatermark(setting.Watermark)
+	}
+
+	// パラメータから決定的なキャッシュキーを生成
+	outputContentType := options.OutputContentType(sourceContentType)
+	cacheKey := fmt.Sprintf("renders/%s/%s", imageID, options.CacheKey(sourceContentType))
+
+	// キャッシュ済みであればそのURLを返す
+	cached, err := u.storageService.ObjectExists(ctx, u.bucketName, cacheKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check render cache: %w", err)
+	}
+	if cached {
+		return &dto.RenderResponseDTO{
+			ImageID:     imageID,
+			URL:         u.storageService.GetObjectURL(u.bucketName, cacheKey),
+			ContentType: outputContentType,
+			Cached:      true,
+		}, nil
+	}
+
+	// 元画像をストリームから読み込みながらデコードして変換
+	source, err := u.decodeSource(ctx, image.S3ObjectKey, sourceContentType)
+	if errors.Is(err, imagevalueobject.ErrImageTooLarge) {
+		return nil, fmt.Errorf("%w: %v", ErrSourceTooLarge, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	// 画像の透かしは透かし画像を読み込んでおく
+	// 透かしのない変換結果をキャッシュしないよう、読み込めない場合は変換せずエラーにする
+	if watermark, ok := options.Watermark(); ok && watermark.Type() == valueobject.WatermarkImage {
+		overlay, err := u.storageService.FetchImage(ctx, u.bucketName, watermark.ImageKey())
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch watermark image: %w", err)
+		}
+		options = options.WithWatermark(watermark.WithOverlay(overlay))
+	}
+
+	renderedData, dimensions, err := u.processingService.Render(source, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render image: %w", err)
+	}
+
+	// 変換結果をキャッシュとして保存
+	if err := u.storageService.UploadThumbnail(ctx, u.bucketName, cacheKey, renderedData); err != nil {
+		return nil, fmt.Errorf("failed to store rendered image: %w", err)
+	}
+
+	return &dto.RenderResponseDTO{
+		ImageID:     imageID,
+		URL:         u.storageService.GetObjectURL(u.bucketName, cacheKey),
+		ContentType: renderedData.ContentType,
+		Width:       dimensions.Width(),
+		Height:      dimensions.Height(),
+		Cached:      false,
+	}, nil
+}
+
+// decodeSource は元画像をストリームとして開き、メタデータのコンテンツタイプとしてデコードします
+func (u *RenderUsecase) decodeSource(ctx context.Context, key, contentType string) (service.SourceImage, error) {
+	reader, _, err := u.storageService.OpenImage(ctx, u.bucketName, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return u.processingService.Decode(reader, contentType, false)
+}
+
+// resolveOptions はリクエストから変換パラメータを作成し、プリセットで許可されているか確認します
+func (u *RenderUsecase) resolveOptions(request *dto.RenderRequestDTO) (valueobject.RenderOptions, error) {
+	// プリセット名が指定された場合はその設定を使用
+	if request.Preset != "" {
+		for _, preset := range u.presets {
+			if preset.Name != request.Preset {
+				continue
+			}
+			if request.Gravity == "" {
+				return preset.Options, nil
+			}
+			options, err := preset.Options.WithGravity(request.Gravity)
+			if err != nil {
+				return valueobject.RenderOptions{}, fmt.Errorf("%w: %v", ErrInvalidRenderOptions, err)
+			}
+			return options, nil
+		}
+		return valueobject.RenderOptions{}, fmt.Errorf("%w: unknown preset %s", ErrRenderNotAllowed, request.Preset)
+	}
+
+	options, err := valueobject.NewRenderOptions(
+		request.Width,
+		request.Height,
+		request.Fit,
+		request.Gravity,
+		request.Format,
+		request.Quality,
+	)
+	if err != nil {
+		return valueobject.RenderOptions{}, fmt.Errorf("%w: %v", ErrInvalidRenderOptions, err)
+	}
+
+	// 任意サイズの生成による濫用を防ぐため、プリセットに一致するもののみ許可
+	for _, preset := range u.presets {
+		if preset.Options.Matches(options) {
+			return options, nil
+		}
+	}
+
+	return valueobject.RenderOptions{}, ErrRenderNotAllowed
+}
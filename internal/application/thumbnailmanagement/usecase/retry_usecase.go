@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"cloudpix/internal/application/thumbnailmanagement/dto"
+	"cloudpix/internal/domain/thumbnailmanagement/repository"
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"cloudpix/internal/logging"
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultRetryBatchSize は1回の実行で再試行する画像数のデフォルト値
+const DefaultRetryBatchSize = 50
+
+// ThumbnailRetryUsecase は失敗したサムネイル生成を再試行するユースケース
+type ThumbnailRetryUsecase struct {
+	stateRepository  repository.ProcessingStateRepository
+	thumbnailUsecase *ThumbnailGenerationUsecase
+	bucket           string
+	batchSize        int
+	logger           logging.Logger
+}
+
+// NewThumbnailRetryUsecase は新しい再試行ユースケースを作成します
+func NewThumbnailRetryUsecase(
+	stateRepository repository.ProcessingStateRepository,
+	thumbnailUsecase *ThumbnailGenerationUsecase,
+	bucket string,
+	batchSize int,
+	logger logging.Logger,
+) *ThumbnailRetryUsecase {
+	if batchSize <= 0 {
+		batchSize = DefaultRetryBatchSize
+	}
+	return &ThumbnailRetryUsecase{
+		stateRepository:  stateRepository,
+		thumbnailUsecase: thumbnailUsecase,
+		bucket:           bucket,
+		batchSize:        batchSize,
+		logger:           logger,
+	}
+}
+
+// RetryFailed は再試行時刻を過ぎた失敗中の画像のサムネイルを再生成します
+// 再試行の結果はサムネイル生成ユースケースが処理状態に記録します
+func (u *ThumbnailRetryUsecase) RetryFailed(ctx context.Context) (*dto.ThumbnailRetryReportDTO, error) {
+	states, err := u.stateRepository.FindDueForRetry(ctx, time.Now(), u.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find images to retry: %w", err)
+	}
+
+	report := &dto.ThumbnailRetryReportDTO{Due: len(states)}
+	for _, state := range states {
+		if ctx.Err() != nil {
+			break
+		}
+
+		fields := map[string]interface{}{
+			"imageId":  state.ImageID,
+			"attempts": state.Attempts,
+		}
+
+		result, err := u.thumbnailUsecase.ProcessImage(ctx, u.bucket, state.ObjectKey)
+		if err == nil && result.Success {
+			report.Succeeded++
+			u.logger.Info("Thumbnail retry succeeded", fields)
+			continue
+		}
+
+		report.Failed++
+		if err != nil {
+			u.logger.Error(err, "Thumbnail retry failed", fields)
+		} else {
+			u.logger.Warn("Thumbnail retry was not successful", map[string]interface{}{
+				"imageId": state.ImageID,
+				"message": result.Message,
+			})
+		}
+
+		// 上限に達してデッドレターになったかを確認
+		if updated, err := u.stateRepository.FindByImageID(ctx, state.ImageID); err == nil && updated != nil &&
+			updated.Status == valueobject.ProcessingStatusDeadLetter {
+			report.DeadLettered++
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"cloudpix/internal/application/thumbnailmanagement/dto"
+	imagerepository "cloudpix/internal/domain/imagemanagement/repository"
+	"cloudpix/internal/domain/thumbnailmanagement/entity"
+	"cloudpix/internal/domain/thumbnailmanagement/repository"
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 定義済みエラー
+var (
+	ErrInvalidWatermark       = errors.New("無効な透かし設定です")
+	ErrWatermarkNotFound      = errors.New("透かし設定が見つかりません")
+	ErrWatermarkImageNotFound = errors.New("透かしに使用する画像が見つかりません")
+	ErrUnknownRendition       = errors.New("存在しないレンディションが指定されています")
+)
+
+// WatermarkUsecase は透かし設定の管理のユースケース
+type WatermarkUsecase struct {
+	watermarkRepository repository.WatermarkRepository
+	imageRepository     imagerepository.ImageRepository
+	renditionSpecs      []valueobject.RenditionSpec
+}
+
+// NewWatermarkUsecase は新しい透かし設定ユースケースを作成します
+func NewWatermarkUsecase(
+	watermarkRepository repository.WatermarkRepository,
+	imageRepository imagerepository.ImageRepository,
+	renditionSpecs []valueobject.RenditionSpec,
+) *WatermarkUsecase {
+	return &WatermarkUsecase{
+		watermarkRepository: watermarkRepository,
+		imageRepository:     imageRepository,
+		renditionSpecs:      renditionSpecs,
+	}
+}
+
+// GetUserSetting はユーザーに適用される透かし設定を取得します
+// ユーザー自身の設定がない場合はデフォルト設定を返します
+func (u *WatermarkUsecase) GetUserSetting(ctx context.Context, userID string) (*dto.WatermarkSettingDTO, error) {
+	setting, err := u.watermarkRepository.FindByScope(ctx, entity.UserWatermarkScope(userID))
+	if err != nil {
+		return nil, err
+	}
+	if setting != nil {
+		settingDTO := toWatermarkSettingDTO(setting)
+		return &settingDTO, nil
+	}
+
+	settingDTO, err := u.GetDefaultSetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	settingDTO.Inherited = true
+	return settingDTO, nil
+}
+
+// PutUserSetting はユーザーの透かし設定を登録します
+// 透かしに使用する画像はユーザー自身がアップロードした画像である必要があります
+func (u *WatermarkUsecase) PutUserSetting(ctx context.Context, userID string, request *dto.WatermarkRequestDTO) (*dto.WatermarkSettingDTO, error) {
+	return u.put(ctx, entity.UserWatermarkScope(userID), userID, request)
+}
+
+// DeleteUserSetting はユーザーの透かし設定を削除します（以降はデフォルト設定が適用されます）
+func (u *WatermarkUsecase) DeleteUserSetting(ctx context.Context, userID string) error {
+	return u.watermarkRepository.Delete(ctx, entity.UserWatermarkScope(userID))
+}
+
+// GetDefaultSetting はデフォルトの透かし設定を取得します
+func (u *WatermarkUsecase) GetDefaultSetting(ctx context.Context) (*dto.WatermarkSettingDTO, error) {
+	setting, err := u.watermarkRepository.FindByScope(ctx, entity.DefaultWatermarkScope)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil {
+		return nil, ErrWatermarkNotFound
+	}
+
+	settingDTO := toWatermarkSettingDTO(setting)
+	return &settingDTO, nil
+}
+
+// PutDefaultSetting はデフォルトの透かし設定を登録します（管理者用）
+func (u *WatermarkUsecase) PutDefaultSetting(ctx context.Context, request *dto.WatermarkRequestDTO) (*dto.WatermarkSettingDTO, error) {
+	return u.put(ctx, entity.DefaultWatermarkScope, "", request)
+}
+
+// DeleteDefaultSetting はデフォルトの透かし設定を削除します（管理者用）
+func (u *WatermarkUsecase) DeleteDefaultSetting(ctx context.Context) error {
+	return u.watermarkRepository.Delete(ctx, entity.DefaultWatermarkScope)
+}
+
+// put は透かし設定を検証して保存します
+// owner が指定されている場合は透かし画像の所有者を確認します
+func (u *WatermarkUsecase) put(ctx context.Context, scope, owner string, request *dto.WatermarkRequestDTO) (*dto.WatermarkSettingDTO, error) {
+	if err := u.validateRenditions(request.Renditions); err != nil {
+		return nil, err
+	}
+
+	// 画像の透かしはアップロード済み画像のS3オブジェクトキーを使用
+	imageKey := ""
+	if request.ImageID != "" {
+		key, err := u.resolveImageKey(ctx, request.ImageID, owner)
+		if err != nil {
+			return nil, err
+		}
+		imageKey = key
+	}
+
+	opacity := request.Opacity
+	if opacity == 0 {
+		opacity = valueobject.DefaultWatermarkOpacity
+	}
+	scale := request.Scale
+	if scale == 0 {
+		scale = valueobject.DefaultWatermarkScale
+	}
+
+	watermark, err := valueobject.NewWatermark(request.Type, request.Text, imageKey, request.Position, opacity, scale)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWatermark, err)
+	}
+
+	setting := entity.NewWatermarkSetting(scope, watermark, request.Renditions)
+	if err := u.watermarkRepository.Save(ctx, setting); err != nil {
+		return nil, err
+	}
+
+	settingDTO := toWatermarkSettingDTO(setting)
+	return &settingDTO, nil
+}
+
+// validateRenditions は透かしを適用するレンディションが設定済みのものかを検証します
+func (u *WatermarkUsecase) validateRenditions(renditions []string) error {
+	if len(renditions) == 0 {
+		return fmt.Errorf("%w: 透かしを適用するレンディションを指定してください", ErrInvalidWatermark)
+	}
+	for _, name := range renditions {
+		known := false
+		for _, spec := range u.renditionSpecs {
+			if spec.Name() == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: %s", ErrUnknownRendition, name)
+		}
+	}
+	return nil
+}
+
+// resolveImageKey は透かしに使用する画像のS3オブジェクトキーを取得します
+func (u *WatermarkUsecase) resolveImageKey(ctx context.Context, imageID, owner string) (string, error) {
+	exists, err := u.imageRepository.Exists(ctx, imageID)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", ErrWatermarkImageNotFound
+	}
+
+	imageAggregate, err := u.imageRepository.FindByID(ctx, imageID)
+	if err != nil {
+		return "", err
+	}
+	// 他のユーザーの画像は透かしに使用できない
+	if owner != "" && imageAggregate.Image.Owner != owner {
+		return "", ErrWatermarkImageNotFound
+	}
+
+	return imageAggregate.Image.S3ObjectKey, nil
+}
+
+// toWatermarkSettingDTO は透かし設定をDTOに変換します
+func toWatermarkSettingDTO(setting *entity.WatermarkSetting) dto.WatermarkSettingDTO {
+	watermark := setting.Watermark
+	return dto.WatermarkSettingDTO{
+		Scope:      setting.Scope,
+		Type:       string(watermark.Type()),
+		Text:       watermark.Text(),
+		ImageKey:   watermark.ImageKey(),
+		Position:   string(watermark.Position()),
+		Opacity:    watermark.Opacity(),
+		Scale:      watermark.Scale(),
+		Renditions: setting.Renditions,
+		UpdatedAt:  setting.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// findWatermarkSetting は画像の所有者の透かし設定を取得し、なければデフォルト設定を返します（どちらもない場合は nil）
+// 所有者が特定できない画像はデフォルト設定のみを参照します
+func findWatermarkSetting(ctx context.Context, watermarkRepository repository.WatermarkRepository, owner string) (*entity.WatermarkSetting, error) {
+	if owner != "" {
+		setting, err := watermarkRepository.FindByScope(ctx, entity.UserWatermarkScope(owner))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find watermark setting: %w", err)
+		}
+		if setting != nil {
+			return setting, nil
+		}
+	}
+
+	setting, err := watermarkRepository.FindByScope(ctx, entity.DefaultWatermarkScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find watermark setting: %w", err)
+	}
+	return setting, nil
+}
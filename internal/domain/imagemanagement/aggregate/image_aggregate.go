@@ -3,15 +3,61 @@ package aggregate
 import (
 	"cloudpix/internal/domain/imagemanagement/entity"
 	"errors"
+	"time"
 )
 
+// ThumbnailStatusPending はサムネイル生成を待っている処理状態
+const ThumbnailStatusPending = "PENDING"
+
+// ThumbnailRendition はサムネイルのレンディション情報を表します
+type ThumbnailRendition struct {
+	Name        string
+	Key         string
+	URL         string
+	Width       int
+	Height      int
+	ContentType string
+	Crop        *CropBox
+}
+
+// CropBox はレンディションの生成に使用した元画像の領域を表します
+type CropBox struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// PaletteColor は画像の代表色とその占有率を表します
+type PaletteColor struct {
+	Color  string
+	Weight float64
+}
+
+// ThumbnailProcessing はサムネイル生成の処理状態を表します
+type ThumbnailProcessing struct {
+	Status      string
+	Attempts    int
+	LastError   string
+	NextRetryAt time.Time // 再試行を待っている場合のみ設定
+	UpdatedAt   time.Time
+}
+
 // ImageAggregate は画像とその関連情報を含む集約ルート
 type ImageAggregate struct {
-	Image           *entity.Image
-	ThumbnailURL    string
-	ThumbnailWidth  int
-	ThumbnailHeight int
-	Tags            []string
+	Image              *entity.Image
+	ThumbnailURL       string
+	ThumbnailWidth     int
+	ThumbnailHeight    int
+	Renditions         []ThumbnailRendition
+	PerceptualHash     string
+	Palette            []PaletteColor
+	BlurHash           string
+	LQIP               string
+	ThumbnailVersion   string
+	ThumbnailWatermark string // サムネイルの生成時に適用した透かし設定の識別子
+	Processing         *ThumbnailProcessing
+	Tags               []string
 }
 
 // NewImageAggregate は新しい画像集約を作成します
@@ -30,6 +76,14 @@ func (a *ImageAggregate) SetThumbnail(url string, width, height int) {
 	a.Image.SetThumbnail(true)
 }
 
+// MarkThumbnailPending はサムネイル生成を待っている状態にします
+func (a *ImageAggregate) MarkThumbnailPending() {
+	a.Processing = &ThumbnailProcessing{
+		Status:    ThumbnailStatusPending,
+		UpdatedAt: time.Now(),
+	}
+}
+
 // AddTag はタグを追加します（重複チェック付き）
 func (a *ImageAggregate) AddTag(tag string) error {
 	if tag == "" {
@@ -14,6 +14,7 @@ type Image struct {
 	UploadDate   valueobject.UploadDate
 	S3ObjectKey  string
 	DownloadURL  string
+	Owner        string
 	CreatedAt    time.Time
 	ModifiedAt   time.Time
 	HasThumbnail bool
@@ -43,6 +44,11 @@ func NewImage(
 	}
 }
 
+// SetOwner は画像の所有者（アップロードしたユーザーのID）を設定します
+func (i *Image) SetOwner(owner string) {
+	i.Owner = owner
+}
+
 // SetThumbnail はサムネイルが生成されたことを記録します
 func (i *Image) SetThumbnail(hasThumbnail bool) {
 	i.HasThumbnail = hasThumbnail
@@ -14,11 +14,14 @@ type ImageUploadedEvent struct {
 	UploadDate  string
 	S3ObjectKey string
 	Bucket      string
+	Owner       string
+	OwnerRoles  []string // アップロードしたユーザーのロール
 	UploadTime  time.Time
 }
 
 // NewImageUploadedEvent は画像エンティティから新しいイベントを作成します
-func NewImageUploadedEvent(image *entity.Image, bucket string) *ImageUploadedEvent {
+// ownerRoles はアップロードしたユーザーのロールです
+func NewImageUploadedEvent(image *entity.Image, bucket string, ownerRoles []string) *ImageUploadedEvent {
 	return &ImageUploadedEvent{
 		ImageID:     image.ID,
 		FileName:    image.FileName.String(),
@@ -27,6 +30,8 @@ func NewImageUploadedEvent(image *entity.Image, bucket string) *ImageUploadedEve
 		UploadDate:  image.UploadDate.String(),
 		S3ObjectKey: image.S3ObjectKey,
 		Bucket:      bucket,
+		Owner:       image.Owner,
+		OwnerRoles:  ownerRoles,
 		UploadTime:  time.Now(),
 	}
 }
@@ -2,7 +2,6 @@ package repository
 
 import (
 	"cloudpix/internal/domain/imagemanagement/aggregate"
-	"cloudpix/internal/domain/imagemanagement/entity"
 	"cloudpix/internal/domain/imagemanagement/valueobject"
 	"context"
 )
@@ -16,16 +15,33 @@ type ImageQueryOptions struct {
 	Offset           int
 }
 
+// ImagePage は画像集約の検索結果の1ページを表す構造体
+type ImagePage struct {
+	Images     []*aggregate.ImageAggregate
+	NextCursor string // 次のページの開始位置（最後のページの場合は空）
+}
+
 // ImageRepository は画像集約の永続化を担当するインターフェース
 type ImageRepository interface {
 	// FindByID は指定されたIDの画像集約を取得します
 	FindByID(ctx context.Context, id string) (*aggregate.ImageAggregate, error)
 
-	// FindByDate は指定された日付の画像を検索します
-	FindByDate(ctx context.Context, date valueobject.UploadDate) ([]*entity.Image, error)
+	// FindByDate は指定された日付の画像集約を検索します
+	FindByDate(ctx context.Context, date valueobject.UploadDate) ([]*aggregate.ImageAggregate, error)
+
+	// Find は条件に一致する画像集約を検索します
+	Find(ctx context.Context, options ImageQueryOptions) ([]*aggregate.ImageAggregate, error)
+
+	// FindPage はアーカイブされていない画像集約をカーソルの位置から1ページ分検索します
+	// カーソルが空の場合は先頭から検索します
+	FindPage(ctx context.Context, cursor string, limit int) (*ImagePage, error)
+
+	// FindByIDs は指定されたIDのうちアーカイブされていない画像集約をまとめて取得します
+	// 存在しない画像は結果に含まれず、結果は指定されたIDの順に並びます
+	FindByIDs(ctx context.Context, ids []string) ([]*aggregate.ImageAggregate, error)
 
-	// Find は条件に一致する画像を検索します
-	Find(ctx context.Context, options ImageQueryOptions) ([]*entity.Image, error)
+	// FindByOwner は指定されたユーザーが所有する画像集約を検索します
+	FindByOwner(ctx context.Context, owner string) ([]*aggregate.ImageAggregate, error)
 
 	// Save は画像集約を保存します
 	Save(ctx context.Context, imageAggregate *aggregate.ImageAggregate) error
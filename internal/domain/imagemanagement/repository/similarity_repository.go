@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"cloudpix/internal/domain/imagemanagement/valueobject"
+	"context"
+)
+
+// SimilarityEntry は知覚ハッシュのインデックスに登録された画像を表します
+type SimilarityEntry struct {
+	ImageID string
+	Hash    valueobject.PerceptualHash
+}
+
+// SimilarityRepository は知覚ハッシュによる類似画像インデックスを担当するインターフェース
+type SimilarityRepository interface {
+	// Index は画像の知覚ハッシュをインデックスに登録します
+	Index(ctx context.Context, imageID string, hash valueobject.PerceptualHash) error
+
+	// FindCandidates は指定されたバンドキーのいずれかに一致する画像を取得します
+	FindCandidates(ctx context.Context, bandKeys []string) ([]SimilarityEntry, error)
+
+	// Remove は画像をインデックスから削除します
+	Remove(ctx context.Context, imageID string, hash valueobject.PerceptualHash) error
+}
@@ -0,0 +1,7 @@
+package service
+
+// ImageInspector は画像全体をデコードせずに画像を検査するドメインサービス
+type ImageInspector interface {
+	// Inspect は画像のヘッダーから幅と高さを読み取り、大きさの上限を超えていないかを確認します
+	Inspect(contentType string, data []byte) (width, height int, err error)
+}
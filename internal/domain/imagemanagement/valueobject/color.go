@@ -0,0 +1,79 @@
+package valueobject
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 色の形式（#RRGGBB）
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Color はsRGBの色を表す値オブジェクト
+type Color struct {
+	r uint8
+	g uint8
+	b uint8
+}
+
+// ParseHexColor は #RRGGBB 形式の文字列から色を作成します（先頭の#は省略可能）
+func ParseHexColor(value string) (Color, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "#") {
+		value = "#" + value
+	}
+	if !hexColorPattern.MatchString(value) {
+		return Color{}, errors.New("色は #RRGGBB 形式である必要があります")
+	}
+
+	rgb, _ := strconv.ParseUint(value[1:], 16, 32)
+	return Color{r: uint8(rgb >> 16), g: uint8(rgb >> 8), b: uint8(rgb)}, nil
+}
+
+// Hex は色を #rrggbb 形式で返します
+func (c Color) Hex() string {
+	const digits = "0123456789abcdef"
+	return string([]byte{'#',
+		digits[c.r>>4], digits[c.r&0x0f],
+		digits[c.g>>4], digits[c.g&0x0f],
+		digits[c.b>>4], digits[c.b&0x0f],
+	})
+}
+
+// DeltaE は2つの色のCIELAB色空間での距離（CIE76）を返します
+// 目安として2.3程度で人が違いに気付き、10を超えると別の色に見えます
+func (c Color) DeltaE(other Color) float64 {
+	l1, a1, b1 := c.lab()
+	l2, a2, b2 := other.lab()
+	return math.Sqrt((l1-l2)*(l1-l2) + (a1-a2)*(a1-a2) + (b1-b2)*(b1-b2))
+}
+
+// lab はsRGB（D65）をCIELABに変換します
+func (c Color) lab() (float64, float64, float64) {
+	// ガンマ補正を外して線形RGBに変換
+	linear := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.04045 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	r, g, b := linear(c.r), linear(c.g), linear(c.b)
+
+	// XYZ（D65白色点で正規化）
+	x := (0.4124*r + 0.3576*g + 0.1805*b) / 0.95047
+	y := 0.2126*r + 0.7152*g + 0.0722*b
+	z := (0.0193*r + 0.1192*g + 0.9505*b) / 1.08883
+
+	f := func(t float64) float64 {
+		if t > 216.0/24389.0 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27.0*t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
@@ -0,0 +1,82 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultMaxImagePixels はデコードを許可する画像の画素数の上限のデフォルト値（50メガピクセル）
+	DefaultMaxImagePixels int64 = 50_000_000
+	// DefaultMaxImageBytes はデコードを許可する画像のバイト数の上限のデフォルト値（50MiB）
+	DefaultMaxImageBytes int64 = 50 << 20
+	// MaxAnimationPixelsFactor はアニメーションの全フレームの画素数の合計に許可する画素数の上限に対する倍率
+	MaxAnimationPixelsFactor int64 = 4
+)
+
+// ErrImageTooLarge は画像が処理できる大きさの上限を超えていることを表すエラー
+var ErrImageTooLarge = errors.New("image exceeds size limits")
+
+// ImageLimits はデコードを許可する画像の大きさの上限を表す値オブジェクト
+// 小さなファイルで巨大な画素数を宣言する画像（デコンプレッション爆弾）によるメモリ枯渇を防ぎます
+type ImageLimits struct {
+	maxPixels int64
+	maxBytes  int64
+}
+
+// NewImageLimits は画像の大きさの上限を作成します（0以下の値はデフォルト値を使用）
+func NewImageLimits(maxPixels, maxBytes int64) ImageLimits {
+	if maxPixels <= 0 {
+		maxPixels = DefaultMaxImagePixels
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxImageBytes
+	}
+	return ImageLimits{maxPixels: maxPixels, maxBytes: maxBytes}
+}
+
+// DefaultImageLimits はデフォルトの上限を返します
+func DefaultImageLimits() ImageLimits {
+	return NewImageLimits(0, 0)
+}
+
+// MaxPixels は画素数の上限を返します
+func (l ImageLimits) MaxPixels() int64 {
+	return l.maxPixels
+}
+
+// MaxBytes はバイト数の上限を返します
+func (l ImageLimits) MaxBytes() int64 {
+	return l.maxBytes
+}
+
+// CheckBytes はバイト数が上限以内かを確認します
+func (l ImageLimits) CheckBytes(size int64) error {
+	if size > l.maxBytes {
+		return fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes", ErrImageTooLarge, size, l.maxBytes)
+	}
+	return nil
+}
+
+// CheckDimensions は画素数が上限以内かを確認します
+func (l ImageLimits) CheckDimensions(width, height int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid image dimensions: %dx%d", width, height)
+	}
+	if int64(width)*int64(height) > l.maxPixels {
+		return fmt.Errorf("%w: %dx%d pixels exceeds the limit of %d pixels", ErrImageTooLarge, width, height, l.maxPixels)
+	}
+	return nil
+}
+
+// CheckFrames はアニメーションの全フレームを展開した画素数（フレーム数×論理スクリーンの画素数）が上限以内かを確認します
+// 1フレームの画素数が小さくても、大量のフレームを持つ画像で全フレームの展開時にメモリが枯渇するのを防ぎます
+func (l ImageLimits) CheckFrames(width, height, frames int) error {
+	if err := l.CheckDimensions(width, height); err != nil {
+		return err
+	}
+	if int64(frames)*int64(width)*int64(height) > l.maxPixels*MaxAnimationPixelsFactor {
+		return fmt.Errorf("%w: %d frames of %dx%d pixels exceeds the limit of %d pixels", ErrImageTooLarge, frames, width, height, l.maxPixels*MaxAnimationPixelsFactor)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+)
+
+// 知覚ハッシュを分割するバンドの数とビット幅
+const (
+	PerceptualHashBands    = 4
+	perceptualHashBandBits = 64 / PerceptualHashBands
+)
+
+// MaxSimilarityDistance は類似検索で指定できる最大のハミング距離
+// バンドごとに1ビットまでの違いを探索することで、全件走査せずに候補を取得できる範囲
+const MaxSimilarityDistance = PerceptualHashBands*2 - 1
+
+// PerceptualHash は画像の見た目に基づく64ビットの知覚ハッシュ（dHash）を表す値オブジェクト
+type PerceptualHash struct {
+	value uint64
+}
+
+// NewPerceptualHash は知覚ハッシュの値オブジェクトを作成します
+func NewPerceptualHash(value uint64) PerceptualHash {
+	return PerceptualHash{value: value}
+}
+
+// ParsePerceptualHash は16桁の16進文字列から知覚ハッシュを作成します
+func ParsePerceptualHash(value string) (PerceptualHash, error) {
+	if len(value) != 16 {
+		return PerceptualHash{}, errors.New("知覚ハッシュは16桁の16進数である必要があります")
+	}
+	parsed, err := strconv.ParseUint(value, 16, 64)
+	if err != nil {
+		return PerceptualHash{}, errors.New("知覚ハッシュは16桁の16進数である必要があります")
+	}
+	return PerceptualHash{value: parsed}, nil
+}
+
+// Value はハッシュ値を返します
+func (h PerceptualHash) Value() uint64 {
+	return h.value
+}
+
+// String はハッシュを16桁の16進文字列として返します
+func (h PerceptualHash) String() string {
+	return fmt.Sprintf("%016x", h.value)
+}
+
+// Distance は2つのハッシュ間のハミング距離を返します
+func (h PerceptualHash) Distance(other PerceptualHash) int {
+	return bits.OnesCount64(h.value ^ other.value)
+}
+
+// Bands はハッシュをバンドに分割したインデックスキーを返します
+func (h PerceptualHash) Bands() []string {
+	keys := make([]string, PerceptualHashBands)
+	for i := 0; i < PerceptualHashBands; i++ {
+		keys[i] = bandKey(i, h.band(i))
+	}
+	return keys
+}
+
+// CandidateBands は指定された距離以内のハッシュを見つけるために探索するバンドキーを返します
+// 距離 d 以内のハッシュは、少なくとも1つのバンドで d/バンド数 ビット以内の違いしかない（鳩の巣原理）
+func (h PerceptualHash) CandidateBands(maxDistance int) ([]string, error) {
+	if maxDistance < 0 || maxDistance > MaxSimilarityDistance {
+		return nil, fmt.Errorf("距離は0から%dの範囲である必要があります", MaxSimilarityDistance)
+	}
+
+	radius := maxDistance / PerceptualHashBands
+	keys := make([]string, 0)
+	for i := 0; i < PerceptualHashBands; i++ {
+		band := h.band(i)
+		keys = append(keys, bandKey(i, band))
+		if radius >= 1 {
+			// 1ビットだけ異なるバンド値も探索
+			for bit := 0; bit < perceptualHashBandBits; bit++ {
+				keys = append(keys, bandKey(i, band^(1<<uint(bit))))
+			}
+		}
+	}
+	return keys, nil
+}
+
+// band は指定されたバンドの値を返します
+func (h PerceptualHash) band(index int) uint64 {
+	shift := uint(index * perceptualHashBandBits)
+	return (h.value >> shift) & (1<<perceptualHashBandBits - 1)
+}
+
+// bandKey はバンドの番号と値からインデックスキーを作成します
+func bandKey(index int, value uint64) string {
+	return fmt.Sprintf("%d:%04x", index, value)
+}
@@ -0,0 +1,139 @@
+package valueobject
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestParsePerceptualHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    uint64
+		wantErr bool
+	}{
+		{name: "小文字", value: "0123456789abcdef", want: 0x0123456789abcdef},
+		{name: "大文字", value: "FFFFFFFFFFFFFFFF", want: 0xffffffffffffffff},
+		{name: "ゼロ", value: "0000000000000000", want: 0},
+		{name: "短すぎる", value: "123456789abcdef", wantErr: true},
+		{name: "長すぎる", value: "0123456789abcdef0", wantErr: true},
+		{name: "16進数でない", value: "0123456789abcdeg", wantErr: true},
+		{name: "符号付き", value: "+123456789abcdef", wantErr: true},
+		{name: "空", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePerceptualHash(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParsePerceptualHash(%q) = %s, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePerceptualHash(%q) error = %v", tt.value, err)
+			}
+			if got.Value() != tt.want {
+				t.Errorf("ParsePerceptualHash(%q) = %x, want %x", tt.value, got.Value(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPerceptualHashBands(t *testing.T) {
+	hash := NewPerceptualHash(0x0123456789abcdef)
+	want := []string{"0:cdef", "1:89ab", "2:4567", "3:0123"}
+	if got := hash.Bands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Bands() = %v, want %v", got, want)
+	}
+}
+
+func TestCandidateBands(t *testing.T) {
+	hash := NewPerceptualHash(0x0123456789abcdef)
+
+	tests := []struct {
+		name        string
+		maxDistance int
+		wantCount   int
+		wantErr     bool
+	}{
+		{name: "距離0はバンドのみ", maxDistance: 0, wantCount: PerceptualHashBands},
+		{name: "バンド数未満の距離はバンドのみ", maxDistance: PerceptualHashBands - 1, wantCount: PerceptualHashBands},
+		{name: "バンド数の距離は1ビット違いも探索", maxDistance: PerceptualHashBands, wantCount: PerceptualHashBands * (1 + perceptualHashBandBits)},
+		{name: "最大の距離", maxDistance: MaxSimilarityDistance, wantCount: PerceptualHashBands * (1 + perceptualHashBandBits)},
+		{name: "負の距離", maxDistance: -1, wantErr: true},
+		{name: "最大を超える距離", maxDistance: MaxSimilarityDistance + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := hash.CandidateBands(tt.maxDistance)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CandidateBands(%d) = %d keys, want error", tt.maxDistance, len(keys))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CandidateBands(%d) error = %v", tt.maxDistance, err)
+			}
+			if len(keys) != tt.wantCount {
+				t.Errorf("CandidateBands(%d) returned %d keys, want %d", tt.maxDistance, len(keys), tt.wantCount)
+			}
+
+			// キーは重複せず、元のハッシュのバンドを必ず含む
+			seen := make(map[string]bool)
+			for _, key := range keys {
+				if seen[key] {
+					t.Errorf("CandidateBands(%d) has duplicate key %s", tt.maxDistance, key)
+				}
+				seen[key] = true
+			}
+			for _, band := range hash.Bands() {
+				if !seen[band] {
+					t.Errorf("CandidateBands(%d) does not include own band %s", tt.maxDistance, band)
+				}
+			}
+		})
+	}
+}
+
+func TestCandidateBandsFindsHashesWithinDistance(t *testing.T) {
+	// 距離ごとに無作為なハッシュとビットを反転させたハッシュを作り、バンドが候補に含まれることを確認
+	random := rand.New(rand.NewSource(1))
+	for distance := 0; distance <= MaxSimilarityDistance; distance++ {
+		for trial := 0; trial < 500; trial++ {
+			hash := NewPerceptualHash(random.Uint64())
+			other := hash.Value()
+			for _, bit := range random.Perm(64)[:distance] {
+				other ^= 1 << uint(bit)
+			}
+			similar := NewPerceptualHash(other)
+			if got := hash.Distance(similar); got != distance {
+				t.Fatalf("Distance() = %d, want %d", got, distance)
+			}
+
+			keys, err := hash.CandidateBands(distance)
+			if err != nil {
+				t.Fatalf("CandidateBands(%d) error = %v", distance, err)
+			}
+			candidates := make(map[string]bool, len(keys))
+			for _, key := range keys {
+				candidates[key] = true
+			}
+
+			found := false
+			for _, band := range similar.Bands() {
+				if candidates[band] {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Fatalf("CandidateBands(%d) of %s does not find %s", distance, hash, similar)
+			}
+		}
+	}
+}
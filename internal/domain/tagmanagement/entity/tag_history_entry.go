@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// TagHistoryEntry は画像のタグの1回の変更を記録した履歴エントリ
+// 記録後に変更されることはありません
+type TagHistoryEntry struct {
+	ImageID     string
+	ActorID     string
+	AddedTags   []string
+	RemovedTags []string
+	OccurredAt  time.Time
+}
+
+// NewTagHistoryEntry は新しいタグの変更履歴エントリを作成します
+func NewTagHistoryEntry(imageID, actorID string, addedTags, removedTags []string, occurredAt time.Time) *TagHistoryEntry {
+	if addedTags == nil {
+		addedTags = []string{}
+	}
+	if removedTags == nil {
+		removedTags = []string{}
+	}
+	return &TagHistoryEntry{
+		ImageID:     imageID,
+		ActorID:     actorID,
+		AddedTags:   addedTags,
+		RemovedTags: removedTags,
+		OccurredAt:  occurredAt,
+	}
+}
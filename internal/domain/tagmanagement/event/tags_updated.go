@@ -8,15 +8,27 @@ import (
 // TagsUpdatedEvent はタグが更新されたイベント
 type TagsUpdatedEvent struct {
 	ImageID     string
-	Tags        []string
+	Tags        []string // 更新後のすべてのタグ
+	ActorID     string   // 更新したユーザーのID（CLIなどユーザー以外の場合は実行元の名前）
+	AddedTags   []string // 追加されたタグ
+	RemovedTags []string // 削除されたタグ
 	UpdatedTime time.Time
 }
 
 // NewTagsUpdatedEvent はタグ更新イベントを作成します
-func NewTagsUpdatedEvent(taggedImage *entity.TaggedImage) *TagsUpdatedEvent {
+func NewTagsUpdatedEvent(taggedImage *entity.TaggedImage, actorID string, addedTags, removedTags []string) *TagsUpdatedEvent {
+	if addedTags == nil {
+		addedTags = []string{}
+	}
+	if removedTags == nil {
+		removedTags = []string{}
+	}
 	return &TagsUpdatedEvent{
 		ImageID:     taggedImage.ImageID,
 		Tags:        taggedImage.GetTagNames(),
+		ActorID:     actorID,
+		AddedTags:   addedTags,
+		RemovedTags: removedTags,
 		UpdatedTime: time.Now(),
 	}
 }
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"cloudpix/internal/domain/tagmanagement/valueobject"
+	"context"
+)
+
+// TagAliasRepository はタグの別名の永続化を担当するインターフェース
+type TagAliasRepository interface {
+	// FindAll はすべての別名を取得します
+	FindAll(ctx context.Context) ([]valueobject.TagAlias, error)
+
+	// Save は別名を保存します（同じ別名がある場合は上書きします）
+	Save(ctx context.Context, alias valueobject.TagAlias) error
+
+	// Delete は別名を削除します
+	Delete(ctx context.Context, alias valueobject.Tag) error
+}
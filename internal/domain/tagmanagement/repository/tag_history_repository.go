@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"cloudpix/internal/domain/tagmanagement/entity"
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrInvalidHistoryCursor はリポジトリが返したものではない再開位置が指定された場合のエラー
+var ErrInvalidHistoryCursor = errors.New("invalid tag history cursor")
+
+// TagHistoryPage はタグの変更履歴の1ページ分の検索結果（新しい順）
+type TagHistoryPage struct {
+	Entries    []*entity.TagHistoryEntry
+	NextCursor string // 次のページの開始位置（最後のページの場合は空）
+}
+
+// TagHistoryQuery は期間を指定したタグの変更履歴の検索条件
+type TagHistoryQuery struct {
+	From    time.Time // 期間の開始（この時刻を含む）
+	To      time.Time // 期間の終了（この時刻を含む）
+	TagName string    // 指定した場合はこのタグを追加・削除した変更のみ
+	ActorID string    // 指定した場合はこのユーザーによる変更のみ
+	Cursor  string
+	Limit   int
+}
+
+// TagHistoryRepository はタグの変更履歴の永続化を担当するインターフェース
+type TagHistoryRepository interface {
+	// Save は変更履歴エントリを保存します
+	Save(ctx context.Context, entry *entity.TagHistoryEntry) error
+
+	// FindByImage は画像のタグの変更履歴を新しい順にカーソルの位置から1ページ分取得します
+	FindByImage(ctx context.Context, imageID string, cursor string, limit int) (*TagHistoryPage, error)
+
+	// FindByTimeRange は期間内のすべての画像のタグの変更履歴を新しい順にカーソルの位置から1ページ分取得します
+	FindByTimeRange(ctx context.Context, query TagHistoryQuery) (*TagHistoryPage, error)
+}
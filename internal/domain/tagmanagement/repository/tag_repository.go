@@ -6,17 +6,41 @@ import (
 	"context"
 )
 
+// TaggedImagePage はタグの付いた画像IDの1ページ分の検索結果
+type TaggedImagePage struct {
+	ImageIDs   []string
+	NextCursor string // 次のページの開始位置（最後のページの場合は空）
+}
+
 // TagRepository はタグ情報の永続化を担当するインターフェース
 type TagRepository interface {
 	// FindAllTags は全てのユニークなタグを取得します
 	FindAllTags(ctx context.Context) ([]valueobject.Tag, error)
 
+	// FindTagUsage は画像が1枚以上付けられているタグとその利用数を取得します
+	// owner が空の場合は全ユーザー、指定された場合はそのユーザーの画像での利用数を返します
+	FindTagUsage(ctx context.Context, owner string) ([]valueobject.TagUsage, error)
+
+	// FindTagUsageByPrefix は名前が前方一致するタグとその利用数を取得します
+	// owner の扱いは FindTagUsage と同じです
+	FindTagUsageByPrefix(ctx context.Context, owner string, prefix valueobject.TagPrefix) ([]valueobject.TagUsage, error)
+
+	// RebuildTagUsage はタグテーブル全体からタグの利用数を数え直し、更新した利用数の件数を返します
+	RebuildTagUsage(ctx context.Context) (int, error)
+
 	// FindTaggedImage は指定された画像IDのタグ情報を取得します
 	FindTaggedImage(ctx context.Context, imageID string) (*entity.TaggedImage, error)
 
+	// FindTagsByImageIDs は複数の画像のタグをまとめて取得し、画像IDごとのタグを返します
+	// タグのない画像は結果に含まれません
+	FindTagsByImageIDs(ctx context.Context, imageIDs []string) (map[string][]valueobject.Tag, error)
+
 	// FindImagesByTag は指定されたタグを持つ画像IDのリストを取得します
 	FindImagesByTag(ctx context.Context, tag valueobject.Tag) ([]string, error)
 
+	// FindImagesByTagPage は指定されたタグを持つ画像IDを画像ID順にカーソルの位置から1ページ分検索します
+	FindImagesByTagPage(ctx context.Context, tag valueobject.Tag, cursor string, limit int) (*TaggedImagePage, error)
+
 	// Save はタグ付き画像情報を保存します
 	Save(ctx context.Context, taggedImage *entity.TaggedImage) error
 
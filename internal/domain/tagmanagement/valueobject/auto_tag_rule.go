@@ -0,0 +1,286 @@
+package valueobject
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// AutoTagCondition は自動タグ付けのルールの条件
+// 指定されたすべての項目に一致した場合にルールが適用されます
+type AutoTagCondition struct {
+	FileName      string            // ファイル名のパターン（* と ? を使用可能、大文字小文字を区別しない）
+	ContentTypes  []string          // いずれかに一致するコンテンツタイプ
+	MinSize       int64             // 最小のバイト数
+	MaxSize       int64             // 最大のバイト数
+	MinWidth      int               // 最小の幅
+	MaxWidth      int               // 最大の幅
+	MinHeight     int               // 最小の高さ
+	MaxHeight     int               // 最大の高さ
+	EXIF          map[string]string // EXIFのフィールドごとの値のパターン（FileName と同じ形式）
+	UploaderRoles []string          // アップロードしたユーザーがいずれかを持つロール
+}
+
+// AutoTagRule は条件に一致した画像にタグを付ける自動タグ付けのルールを表す値オブジェクト
+type AutoTagRule struct {
+	name      string
+	condition AutoTagCondition
+	tags      []Tag
+}
+
+// AutoTagRules は設定された順に評価する自動タグ付けのルールの一覧
+type AutoTagRules []AutoTagRule
+
+// autoTagRuleDefinition は自動タグ付けのルールのJSON表現
+type autoTagRuleDefinition struct {
+	Name string `json:"name"`
+	When struct {
+		FileName      string            `json:"fileName"`
+		ContentTypes  []string          `json:"contentTypes"`
+		MinSize       int64             `json:"minSize"`
+		MaxSize       int64             `json:"maxSize"`
+		MinWidth      int               `json:"minWidth"`
+		MaxWidth      int               `json:"maxWidth"`
+		MinHeight     int               `json:"minHeight"`
+		MaxHeight     int               `json:"maxHeight"`
+		EXIF          map[string]string `json:"exif"`
+		UploaderRoles []string          `json:"uploaderRoles"`
+	} `json:"when"`
+	AddTags []string `json:"addTags"`
+}
+
+// ParseAutoTagRules はJSON配列のルール定義を解析します（空の場合はルールなし）
+// 形式: [{"name": "screenshots", "when": {"fileName": "screenshot*", "contentTypes": ["image/png"]}, "addTags": ["screenshot"]}]
+func ParseAutoTagRules(value string) (AutoTagRules, error) {
+	if strings.TrimSpace(value) == "" {
+		return AutoTagRules{}, nil
+	}
+
+	var definitions []autoTagRuleDefinition
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&definitions); err != nil {
+		return nil, fmt.Errorf("ルール定義はJSON配列である必要があります: %w", err)
+	}
+
+	rules := make(AutoTagRules, 0, len(definitions))
+	seen := make(map[string]bool)
+	for _, definition := range definitions {
+		when := definition.When
+		rule, err := NewAutoTagRule(definition.Name, AutoTagCondition{
+			FileName:      when.FileName,
+			ContentTypes:  when.ContentTypes,
+			MinSize:       when.MinSize,
+			MaxSize:       when.MaxSize,
+			MinWidth:      when.MinWidth,
+			MaxWidth:      when.MaxWidth,
+			MinHeight:     when.MinHeight,
+			MaxHeight:     when.MaxHeight,
+			EXIF:          when.EXIF,
+			UploaderRoles: when.UploaderRoles,
+		}, definition.AddTags)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[rule.name] {
+			return nil, fmt.Errorf("ルール名が重複しています: %s", rule.name)
+		}
+		seen[rule.name] = true
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// NewAutoTagRule は新しい自動タグ付けのルールを作成します
+func NewAutoTagRule(name string, condition AutoTagCondition, tagNames []string) (AutoTagRule, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return AutoTagRule{}, errors.New("ルール名は空にできません")
+	}
+	if err := condition.validate(); err != nil {
+		return AutoTagRule{}, fmt.Errorf("ルール %s の条件が不正です: %w", name, err)
+	}
+
+	if len(tagNames) == 0 {
+		return AutoTagRule{}, fmt.Errorf("ルール %s には付与するタグを1つ以上指定してください", name)
+	}
+	tags := make([]Tag, 0, len(tagNames))
+	for _, tagName := range tagNames {
+		tag, err := NewTag(tagName)
+		if err != nil {
+			return AutoTagRule{}, fmt.Errorf("ルール %s のタグ %q が不正です: %w", name, tagName, err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return AutoTagRule{name: name, condition: condition, tags: tags}, nil
+}
+
+// Name はルール名を返します
+func (r AutoTagRule) Name() string {
+	return r.name
+}
+
+// Condition はルールの条件を返します
+func (r AutoTagRule) Condition() AutoTagCondition {
+	return r.condition
+}
+
+// Tags は条件に一致した画像に付けるタグを返します
+func (r AutoTagRule) Tags() []Tag {
+	return r.tags
+}
+
+// Matches は画像がルールの条件に一致するかどうかを判定します
+func (r AutoTagRule) Matches(facts ImageFacts) bool {
+	return r.condition.Matches(facts)
+}
+
+// Match は画像が条件に一致するルールを設定された順に返します
+func (r AutoTagRules) Match(facts ImageFacts) AutoTagRules {
+	matched := make(AutoTagRules, 0)
+	for _, rule := range r {
+		if rule.Matches(facts) {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
+
+// Tags はルールで付けるタグを重複を除いて設定された順に返します
+func (r AutoTagRules) Tags() []Tag {
+	seen := make(map[string]bool)
+	tags := make([]Tag, 0)
+	for _, rule := range r {
+		for _, tag := range rule.tags {
+			if !seen[tag.Name()] {
+				seen[tag.Name()] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// validate は条件が1つ以上指定され、各項目が正しいかをチェックします
+func (c AutoTagCondition) validate() error {
+	if c.FileName == "" && len(c.ContentTypes) == 0 && c.MinSize == 0 && c.MaxSize == 0 &&
+		c.MinWidth == 0 && c.MaxWidth == 0 && c.MinHeight == 0 && c.MaxHeight == 0 &&
+		len(c.EXIF) == 0 && len(c.UploaderRoles) == 0 {
+		return errors.New("条件を1つ以上指定してください")
+	}
+
+	// ロールはアップロード時、幅・高さ・EXIFはサムネイル生成後にしか分からないため、同じルールでは一致しない
+	if len(c.UploaderRoles) > 0 && c.usesProcessedFacts() {
+		return errors.New("アップロードしたユーザーのロールと幅・高さ・EXIFは同じルールに指定できません")
+	}
+
+	if c.FileName != "" {
+		if _, err := path.Match(c.FileName, ""); err != nil {
+			return fmt.Errorf("ファイル名のパターン %q が不正です", c.FileName)
+		}
+	}
+	if c.MinSize < 0 || c.MaxSize < 0 || (c.MaxSize > 0 && c.MinSize > c.MaxSize) {
+		return errors.New("サイズの範囲が不正です")
+	}
+	if c.MinWidth < 0 || c.MaxWidth < 0 || (c.MaxWidth > 0 && c.MinWidth > c.MaxWidth) {
+		return errors.New("幅の範囲が不正です")
+	}
+	if c.MinHeight < 0 || c.MaxHeight < 0 || (c.MaxHeight > 0 && c.MinHeight > c.MaxHeight) {
+		return errors.New("高さの範囲が不正です")
+	}
+	for field, pattern := range c.EXIF {
+		if !isEXIFField(field) {
+			return fmt.Errorf("EXIFのフィールド %q には対応していません（%s）", field, strings.Join(EXIFFields, ", "))
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("EXIFの %s のパターン %q が不正です", field, pattern)
+		}
+	}
+	return nil
+}
+
+// usesProcessedFacts は条件にサムネイル生成後にしか分からない項目（幅・高さ・EXIF）が含まれるかを判定します
+func (c AutoTagCondition) usesProcessedFacts() bool {
+	return c.MinWidth > 0 || c.MaxWidth > 0 || c.MinHeight > 0 || c.MaxHeight > 0 || len(c.EXIF) > 0
+}
+
+// Matches は画像が条件のすべての項目に一致するかどうかを判定します
+// 画像の属性が分からない項目には一致しません
+func (c AutoTagCondition) Matches(facts ImageFacts) bool {
+	if c.FileName != "" && (facts.FileName == "" || !matchPattern(c.FileName, facts.FileName)) {
+		return false
+	}
+	if len(c.ContentTypes) > 0 && !containsFold(c.ContentTypes, facts.ContentType) {
+		return false
+	}
+	if (c.MinSize > 0 || c.MaxSize > 0) && !inRange(facts.Size, c.MinSize, c.MaxSize) {
+		return false
+	}
+	if (c.MinWidth > 0 || c.MaxWidth > 0) && !inRange(int64(facts.Width), int64(c.MinWidth), int64(c.MaxWidth)) {
+		return false
+	}
+	if (c.MinHeight > 0 || c.MaxHeight > 0) && !inRange(int64(facts.Height), int64(c.MinHeight), int64(c.MaxHeight)) {
+		return false
+	}
+	for field, pattern := range c.EXIF {
+		value, ok := facts.EXIF[field]
+		if !ok || !matchPattern(pattern, value) {
+			return false
+		}
+	}
+	if len(c.UploaderRoles) > 0 {
+		matched := false
+		for _, role := range facts.UploaderRoles {
+			if containsFold(c.UploaderRoles, role) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPattern は値が * と ? を使ったパターンに大文字小文字を区別せずに一致するかを判定します
+func matchPattern(pattern, value string) bool {
+	matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+	return err == nil && matched
+}
+
+// containsFold は大文字小文字を区別せずに値が含まれているかを判定します
+func containsFold(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// inRange は値が範囲内かを判定します（0の値は不明、0の上限は上限なし）
+func inRange(value, min, max int64) bool {
+	if value <= 0 {
+		return false
+	}
+	return value >= min && (max == 0 || value <= max)
+}
+
+// isEXIFField は条件に指定できるEXIFのフィールドかどうかを判定します
+func isEXIFField(field string) bool {
+	for _, name := range EXIFFields {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package valueobject
+
+// 自動タグ付けのルールで条件に指定できるEXIFのフィールド
+const (
+	EXIFMake             = "Make"
+	EXIFModel            = "Model"
+	EXIFLensMake         = "LensMake"
+	EXIFLensModel        = "LensModel"
+	EXIFSoftware         = "Software"
+	EXIFArtist           = "Artist"
+	EXIFDateTimeOriginal = "DateTimeOriginal"
+)
+
+// EXIFFields は自動タグ付けのルールで条件に指定できるEXIFのフィールドの一覧
+var EXIFFields = []string{
+	EXIFMake,
+	EXIFModel,
+	EXIFLensMake,
+	EXIFLensModel,
+	EXIFSoftware,
+	EXIFArtist,
+	EXIFDateTimeOriginal,
+}
+
+// ImageFacts は自動タグ付けのルールの判定に使用する画像の属性
+// 判定する時点で分からない属性はゼロ値（EXIF・ロールは nil）とし、その属性の条件には一致しません
+// 例えばアップロード時は幅・高さ・EXIF、サムネイル生成後はアップロードしたユーザーのロールが分かりません
+type ImageFacts struct {
+	FileName      string
+	ContentType   string
+	Size          int64             // バイト数（0は不明）
+	Width         int               // EXIFの向きを考慮した幅（0は不明）
+	Height        int               // EXIFの向きを考慮した高さ（0は不明）
+	EXIF          map[string]string // フィールド名と値（EXIFがない画像は空）
+	UploaderRoles []string          // アップロードしたユーザーのロール
+}
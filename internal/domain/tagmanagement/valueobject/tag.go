@@ -3,9 +3,18 @@ package valueobject
 import (
 	"errors"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
 )
 
+// NamespaceSeparator はタグの名前空間と値を区切る文字（例: place:tokyo）
+const NamespaceSeparator = ":"
+
 // Tag はタグを表す値オブジェクト
+// 名前空間つきのタグは「名前空間:値」の形式で表します
 type Tag struct {
 	name string
 }
@@ -13,32 +22,55 @@ type Tag struct {
 // NewTag は新しいタグを作成します
 func NewTag(name string) (Tag, error) {
 	// タグ名をトリムして正規化
-	normalizedName := strings.TrimSpace(strings.ToLower(name))
+	normalizedName := normalizeTagName(name)
 
 	// 空のタグをチェック
 	if normalizedName == "" {
 		return Tag{}, errors.New("タグ名は空にできません")
 	}
 
-	// タグの長さをチェック
-	if len(normalizedName) > 50 {
+	// タグの長さをチェック（バイト数ではなく文字数で数える）
+	if utf8.RuneCountInString(normalizedName) > 50 {
 		return Tag{}, errors.New("タグ名は50文字以下である必要があります")
 	}
 
+	// 名前空間つきの場合は名前空間と値をそれぞれチェック
+	namespace, value, namespaced := strings.Cut(normalizedName, NamespaceSeparator)
+	if namespaced && (namespace == "" || value == "") {
+		return Tag{}, errors.New("名前空間つきのタグは「名前空間:値」の形式である必要があります")
+	}
+
 	// 不正な文字をチェック
-	for _, c := range normalizedName {
+	if !isValidTagText(namespace) || !isValidTagText(value) {
+		return Tag{}, errors.New("タグ名に不正な文字が含まれています")
+	}
+
+	return Tag{name: normalizedName}, nil
+}
+
+// isValidTagText は名前空間・値に使用できない文字が含まれていないかをチェックします
+func isValidTagText(text string) bool {
+	for _, c := range text {
 		if !isValidTagChar(c) {
-			return Tag{}, errors.New("タグ名に不正な文字が含まれています")
+			return false
 		}
 	}
+	return true
+}
 
-	return Tag{name: normalizedName}, nil
+// normalizeTagName はタグ名を比較・保存用の形式に正規化します
+// NFKC正規化で全角英数字・記号を半角にそろえてから大文字小文字を畳み込むため、
+// 「ＴＯＫＹＯ」と「tokyo」は同じタグになります（ASCIIのタグは従来どおり小文字化のみ）
+func normalizeTagName(name string) string {
+	return strings.TrimSpace(cases.Fold().String(norm.NFKC.String(name)))
 }
 
 // isValidTagChar はタグ名に使用できる文字かどうかをチェックします
+// 各言語の文字・数字（結合文字を含む）と '-', '_', '.' を使用できます
 func isValidTagChar(c rune) bool {
-	return (c >= 'a' && c <= 'z') ||
-		(c >= '0' && c <= '9') ||
+	return unicode.IsLetter(c) ||
+		unicode.IsMark(c) ||
+		unicode.IsDigit(c) ||
 		c == '-' || c == '_' || c == '.'
 }
 
@@ -47,6 +79,24 @@ func (t Tag) Name() string {
 	return t.name
 }
 
+// Namespace はタグの名前空間を返します（名前空間がない場合は空）
+func (t Tag) Namespace() string {
+	namespace, _, namespaced := strings.Cut(t.name, NamespaceSeparator)
+	if !namespaced {
+		return ""
+	}
+	return namespace
+}
+
+// Value は名前空間を除いたタグの値を返します
+func (t Tag) Value() string {
+	_, value, namespaced := strings.Cut(t.name, NamespaceSeparator)
+	if !namespaced {
+		return t.name
+	}
+	return value
+}
+
 // Equals は2つのタグが等しいかどうかを判定します
 func (t Tag) Equals(other Tag) bool {
 	return t.name == other.name
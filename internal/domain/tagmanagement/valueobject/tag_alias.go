@@ -0,0 +1,80 @@
+package valueobject
+
+import (
+	"errors"
+	"sort"
+)
+
+// TagAlias はタグの別名（同義語）とその正規のタグの対応を表す値オブジェクト
+type TagAlias struct {
+	alias     Tag
+	canonical Tag
+}
+
+// NewTagAlias は新しいタグの別名を作成します
+func NewTagAlias(alias Tag, canonical Tag) (TagAlias, error) {
+	if alias.Equals(canonical) {
+		return TagAlias{}, errors.New("別名と正規のタグには異なるタグを指定してください")
+	}
+	return TagAlias{alias: alias, canonical: canonical}, nil
+}
+
+// Alias は別名を返します
+func (a TagAlias) Alias() Tag {
+	return a.alias
+}
+
+// Canonical は正規のタグを返します
+func (a TagAlias) Canonical() Tag {
+	return a.canonical
+}
+
+// TagAliases は別名の一覧から正規のタグへの解決と、検索するタグの展開を行う値オブジェクト
+type TagAliases struct {
+	canonicals map[string]Tag
+	aliases    map[string][]Tag
+}
+
+// NewTagAliases は別名の一覧から TagAliases を作成します
+func NewTagAliases(aliases []TagAlias) TagAliases {
+	result := TagAliases{
+		canonicals: make(map[string]Tag, len(aliases)),
+		aliases:    make(map[string][]Tag),
+	}
+	for _, alias := range aliases {
+		result.canonicals[alias.Alias().Name()] = alias.Canonical()
+		result.aliases[alias.Canonical().Name()] = append(result.aliases[alias.Canonical().Name()], alias.Alias())
+	}
+	for _, tags := range result.aliases {
+		sort.Slice(tags, func(i, j int) bool {
+			return tags[i].Name() < tags[j].Name()
+		})
+	}
+	return result
+}
+
+// Resolve はタグが別名の場合は正規のタグを、それ以外の場合はタグをそのまま返します
+func (a TagAliases) Resolve(tag Tag) Tag {
+	if canonical, ok := a.canonicals[tag.Name()]; ok {
+		return canonical
+	}
+	return tag
+}
+
+// IsAlias はタグが別名として登録されているかどうかを判定します
+func (a TagAliases) IsAlias(tag Tag) bool {
+	_, ok := a.canonicals[tag.Name()]
+	return ok
+}
+
+// HasAliases はタグを正規のタグとする別名があるかどうかを判定します
+func (a TagAliases) HasAliases(tag Tag) bool {
+	return len(a.aliases[tag.Name()]) > 0
+}
+
+// Expand はタグを正規のタグとその別名に展開します（先頭が正規のタグ）
+// 別名が登録される前に別名のまま付けられたタグも検索できるようにするために使用します
+func (a TagAliases) Expand(tag Tag) []Tag {
+	canonical := a.Resolve(tag)
+	return append([]Tag{canonical}, a.aliases[canonical.Name()]...)
+}
@@ -0,0 +1,46 @@
+package valueobject
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TagNamespaces は付与を許可するタグの名前空間の一覧を表す値オブジェクト
+// 名前空間のないタグは常に許可されます
+type TagNamespaces struct {
+	allowed map[string]bool
+}
+
+// ParseTagNamespaces はカンマ区切りの名前空間の一覧を解析します（例: "place,person"）
+// 空の場合は名前空間つきのタグを許可しません
+func ParseTagNamespaces(value string) (TagNamespaces, error) {
+	allowed := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		namespace := normalizeTagName(name)
+		if namespace == "" {
+			continue
+		}
+		if !isValidTagText(namespace) {
+			return TagNamespaces{}, fmt.Errorf("名前空間に不正な文字が含まれています: %q", name)
+		}
+		allowed[namespace] = true
+	}
+	return TagNamespaces{allowed: allowed}, nil
+}
+
+// Allows はタグの名前空間が許可されているかどうかを判定します
+func (n TagNamespaces) Allows(tag Tag) bool {
+	namespace := tag.Namespace()
+	return namespace == "" || n.allowed[namespace]
+}
+
+// Names は許可されている名前空間を名前順に返します
+func (n TagNamespaces) Names() []string {
+	names := make([]string, 0, len(n.allowed))
+	for namespace := range n.allowed {
+		names = append(names, namespace)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,58 @@
+package valueobject
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// TagPrefix はタグの入力補完に使用するタグ名の前方一致条件を表す値オブジェクト
+// タグ名と同じ正規化を行うため、保存されたタグ名とそのまま比較できます
+type TagPrefix struct {
+	value string
+}
+
+// NewTagPrefix は新しいタグの前方一致条件を作成します
+func NewTagPrefix(prefix string) (TagPrefix, error) {
+	normalizedPrefix := normalizeTagName(prefix)
+
+	// 空の条件をチェック
+	if normalizedPrefix == "" {
+		return TagPrefix{}, errors.New("前方一致の条件は空にできません")
+	}
+
+	// タグ名より長い条件には一致するタグがない
+	if utf8.RuneCountInString(normalizedPrefix) > 50 {
+		return TagPrefix{}, errors.New("前方一致の条件は50文字以下である必要があります")
+	}
+
+	// 不正な文字をチェック（名前空間の区切りより後は値の途中まででもよい）
+	namespace, value, namespaced := strings.Cut(normalizedPrefix, NamespaceSeparator)
+	if namespaced && namespace == "" {
+		return TagPrefix{}, errors.New("名前空間を指定してください")
+	}
+	if !isValidTagText(namespace) || !isValidTagText(value) {
+		return TagPrefix{}, errors.New("前方一致の条件に不正な文字が含まれています")
+	}
+
+	return TagPrefix{value: normalizedPrefix}, nil
+}
+
+// NewNamespacePrefix は名前空間のすべてのタグに一致する前方一致条件を作成します
+func NewNamespacePrefix(namespace string) (TagPrefix, error) {
+	normalizedNamespace := normalizeTagName(namespace)
+	if normalizedNamespace == "" || !isValidTagText(normalizedNamespace) {
+		return TagPrefix{}, errors.New("無効な名前空間です")
+	}
+	return NewTagPrefix(normalizedNamespace + NamespaceSeparator)
+}
+
+// Value は正規化された前方一致の条件を返します
+func (p TagPrefix) Value() string {
+	return p.value
+}
+
+// Matches はタグが前方一致の条件に一致するかどうかを判定します
+func (p TagPrefix) Matches(tag Tag) bool {
+	return strings.HasPrefix(tag.Name(), p.value)
+}
@@ -0,0 +1,22 @@
+package valueobject
+
+// TagUsage はタグとそのタグが付けられた画像の数を表す値オブジェクト
+type TagUsage struct {
+	tag   Tag
+	count int
+}
+
+// NewTagUsage は新しいタグの利用数を作成します
+func NewTagUsage(tag Tag, count int) TagUsage {
+	return TagUsage{tag: tag, count: count}
+}
+
+// Tag はタグを返します
+func (u TagUsage) Tag() Tag {
+	return u.tag
+}
+
+// Count はタグが付けられた画像の数を返します
+func (u TagUsage) Count() int {
+	return u.count
+}
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"time"
+)
+
+const (
+	// DefaultMaxProcessingAttempts はデッドレターにするまでの試行回数のデフォルト値
+	DefaultMaxProcessingAttempts = 5
+	// RetryBaseDelay は最初の再試行までの待ち時間
+	RetryBaseDelay = 2 * time.Minute
+	// RetryMaxDelay は再試行までの待ち時間の上限
+	RetryMaxDelay = 6 * time.Hour
+)
+
+// ProcessingState は画像ごとのサムネイル生成の処理状態を表します
+type ProcessingState struct {
+	ImageID     string
+	ObjectKey   string
+	Status      valueobject.ProcessingStatus
+	Attempts    int
+	LastError   string
+	NextRetryAt time.Time // 再試行を待っている場合のみ設定
+	UpdatedAt   time.Time
+}
+
+// NewProcessingState はサムネイル生成を待っている処理状態を作成します
+func NewProcessingState(imageID, objectKey string) *ProcessingState {
+	return &ProcessingState{
+		ImageID:   imageID,
+		ObjectKey: objectKey,
+		Status:    valueobject.ProcessingStatusPending,
+		UpdatedAt: time.Now(),
+	}
+}
+
+// IsRetrying は再試行を待っている状態かどうかを判定します
+func (s *ProcessingState) IsRetrying() bool {
+	return s.Status == valueobject.ProcessingStatusFailed
+}
+
+// RecordSuccess は試行が成功したことを記録します
+func (s *ProcessingState) RecordSuccess(now time.Time) {
+	s.Attempts++
+	s.Status = valueobject.ProcessingStatusSucceeded
+	s.LastError = ""
+	s.NextRetryAt = time.Time{}
+	s.UpdatedAt = now
+}
+
+// RecordFailure は試行が失敗したことを記録します
+// 再試行できない失敗か試行回数が上限に達した場合はデッドレターにし、それ以外は指数バックオフで次の再試行時刻を設定します
+func (s *ProcessingState) RecordFailure(reason string, retryable bool, maxAttempts int, now time.Time) {
+	s.Attempts++
+	s.LastError = reason
+	s.UpdatedAt = now
+
+	if !retryable || s.Attempts >= maxAttempts {
+		s.Status = valueobject.ProcessingStatusDeadLetter
+		s.NextRetryAt = time.Time{}
+		return
+	}
+
+	s.Status = valueobject.ProcessingStatusFailed
+	s.NextRetryAt = now.Add(RetryDelay(s.Attempts))
+}
+
+// RecordRejection は画像が処理の対象外として拒否されたことを記録します（再試行しません）
+func (s *ProcessingState) RecordRejection(reason string, now time.Time) {
+	s.Attempts++
+	s.Status = valueobject.ProcessingStatusRejected
+	s.LastError = reason
+	s.NextRetryAt = time.Time{}
+	s.UpdatedAt = now
+}
+
+// RetryDelay は指定された回数失敗した後、次の再試行までの待ち時間を返します
+func RetryDelay(attempts int) time.Duration {
+	delay := RetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= RetryMaxDelay {
+			return RetryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+)
+
+// Rendition はサムネイルの個々のレンディションを表します
+type Rendition struct {
+	Name        string
+	Key         string
+	URL         string
+	Dimensions  valueobject.Dimensions
+	ContentType string
+}
+
+// NewRendition は新しいレンディションを作成します
+func NewRendition(
+	name string,
+	key string,
+	url string,
+	dimensions valueobject.Dimensions,
+	contentType string,
+) Rendition {
+	return Rendition{
+		Name:        name,
+		Key:         key,
+		URL:         url,
+		Dimensions:  dimensions,
+		ContentType: contentType,
+	}
+}
+
+// GetWidth はレンディションの幅を返します
+func (r Rendition) GetWidth() int {
+	return r.Dimensions.Width()
+}
+
+// GetHeight はレンディションの高さを返します
+func (r Rendition) GetHeight() int {
+	return r.Dimensions.Height()
+}
@@ -7,13 +7,20 @@ import (
 
 // Thumbnail エンティティはサムネイル情報を表します
 type Thumbnail struct {
-	ImageID      string
-	ThumbnailKey string
-	ThumbnailURL string
-	Dimensions   valueobject.Dimensions
-	OriginalKey  string
-	ContentType  string
-	CreatedAt    time.Time
+	ImageID        string
+	ThumbnailKey   string
+	ThumbnailURL   string
+	Dimensions     valueobject.Dimensions
+	OriginalKey    string
+	ContentType    string
+	Renditions     []Rendition
+	PerceptualHash string
+	Palette        []valueobject.PaletteColor
+	BlurHash       string
+	LQIP           string
+	ConfigVersion  string
+	Watermark      string // 生成時に適用した透かし設定の識別子（透かしなしは空）
+	CreatedAt      time.Time
 }
 
 // NewThumbnail は新しいサムネイルエンティティを作成します
@@ -32,6 +39,7 @@ func NewThumbnail(
 		Dimensions:   dimensions,
 		OriginalKey:  originalKey,
 		ContentType:  contentType,
+		Renditions:   make([]Rendition, 0),
 		CreatedAt:    time.Now(),
 	}
 }
@@ -50,3 +58,50 @@ func (t *Thumbnail) GetHeight() int {
 func (t *Thumbnail) IsSquare() bool {
 	return t.Dimensions.IsSquare()
 }
+
+// AddRendition はレンディションを追加します（同名のレンディションは置き換えます）
+func (t *Thumbnail) AddRendition(rendition Rendition) {
+	for i, existing := range t.Renditions {
+		if existing.Name == rendition.Name {
+			t.Renditions[i] = rendition
+			return
+		}
+	}
+	t.Renditions = append(t.Renditions, rendition)
+}
+
+// SetPerceptualHash は知覚ハッシュを設定します
+func (t *Thumbnail) SetPerceptualHash(hash string) {
+	t.PerceptualHash = hash
+}
+
+// SetPalette は代表色を設定します
+func (t *Thumbnail) SetPalette(palette []valueobject.PaletteColor) {
+	t.Palette = palette
+}
+
+// SetPlaceholder は読み込み中に表示するBlurHashとLQIPを設定します
+func (t *Thumbnail) SetPlaceholder(blurHash, lqip string) {
+	t.BlurHash = blurHash
+	t.LQIP = lqip
+}
+
+// SetConfigVersion は生成に使用したレンディション設定のバージョンを設定します
+func (t *Thumbnail) SetConfigVersion(version string) {
+	t.ConfigVersion = version
+}
+
+// SetWatermark は生成時に適用した透かし設定の識別子を設定します
+func (t *Thumbnail) SetWatermark(fingerprint string) {
+	t.Watermark = fingerprint
+}
+
+// GetRendition は指定された名前のレンディションを返します
+func (t *Thumbnail) GetRendition(name string) (Rendition, bool) {
+	for _, rendition := range t.Renditions {
+		if rendition.Name == name {
+			return rendition, true
+		}
+	}
+	return Rendition{}, false
+}
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultWatermarkScope は管理者が設定するデフォルトの透かし設定のスコープ
+	DefaultWatermarkScope = "default"
+	// ユーザーごとの透かし設定のスコープの接頭辞
+	userWatermarkScopePrefix = "user:"
+)
+
+// UserWatermarkScope はユーザーごとの透かし設定のスコープを返します
+func UserWatermarkScope(userID string) string {
+	return userWatermarkScopePrefix + userID
+}
+
+// WatermarkSetting は透かしとそれを適用するレンディションの設定を表します
+type WatermarkSetting struct {
+	Scope      string
+	Watermark  valueobject.Watermark
+	Renditions []string
+	UpdatedAt  time.Time
+}
+
+// NewWatermarkSetting は新しい透かし設定を作成します
+func NewWatermarkSetting(scope string, watermark valueobject.Watermark, renditions []string) *WatermarkSetting {
+	return &WatermarkSetting{
+		Scope:      scope,
+		Watermark:  watermark,
+		Renditions: renditions,
+		UpdatedAt:  time.Now(),
+	}
+}
+
+// AppliesTo は指定されたレンディションに透かしを適用するかを判定します
+func (s *WatermarkSetting) AppliesTo(renditionName string) bool {
+	for _, name := range s.Renditions {
+		if name == renditionName {
+			return true
+		}
+	}
+	return false
+}
+
+// AppliesToRenders はオンデマンド変換の結果に透かしを適用するかを判定します
+// いずれかのレンディションに透かしを適用する設定では、変換で透かしのない画像を取得できないよう変換結果にも適用します
+func (s *WatermarkSetting) AppliesToRenders() bool {
+	return len(s.Renditions) > 0
+}
+
+// Fingerprint は指定されたレンディションに適用する透かしの識別子を返します
+// 透かしや適用するレンディションが変わると識別子も変わるため、古い透かしで生成されたサムネイルの検出に使用します
+// いずれのレンディションにも適用しない場合は空文字列を返します
+func (s *WatermarkSetting) Fingerprint(specs []valueobject.RenditionSpec) string {
+	applied := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		if s.AppliesTo(spec.Name()) {
+			applied = append(applied, spec.Name())
+		}
+	}
+	if len(applied) == 0 {
+		return ""
+	}
+	return s.Watermark.Fingerprint() + ":" + strings.Join(applied, ",")
+}
@@ -2,6 +2,7 @@ package event
 
 import (
 	"cloudpix/internal/domain/thumbnailmanagement/entity"
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
 	"time"
 )
 
@@ -12,17 +13,23 @@ type ThumbnailGeneratedEvent struct {
 	Width         int
 	Height        int
 	ContentType   string
+	SourceWidth   int               // 元画像の表示上の幅
+	SourceHeight  int               // 元画像の表示上の高さ
+	EXIF          map[string]string // 元画像のEXIFから読み取ったフィールド
 	GeneratedTime time.Time
 }
 
-// NewThumbnailGeneratedEvent はサムネイルエンティティから新しいイベントを作成します
-func NewThumbnailGeneratedEvent(thumbnail *entity.Thumbnail) *ThumbnailGeneratedEvent {
+// NewThumbnailGeneratedEvent はサムネイルエンティティと元画像の情報から新しいイベントを作成します
+func NewThumbnailGeneratedEvent(thumbnail *entity.Thumbnail, source valueobject.Dimensions, exif map[string]string) *ThumbnailGeneratedEvent {
 	return &ThumbnailGeneratedEvent{
 		ImageID:       thumbnail.ImageID,
 		ThumbnailURL:  thumbnail.ThumbnailURL,
 		Width:         thumbnail.GetWidth(),
 		Height:        thumbnail.GetHeight(),
 		ContentType:   thumbnail.ContentType,
+		SourceWidth:   source.Width(),
+		SourceHeight:  source.Height(),
+		EXIF:          exif,
 		GeneratedTime: time.Now(),
 	}
 }
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"cloudpix/internal/domain/thumbnailmanagement/entity"
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrProcessingStateImageNotFound は処理状態を保存する画像のメタデータが存在しない（削除された）場合のエラー
+var ErrProcessingStateImageNotFound = errors.New("image for processing state not found")
+
+// ProcessingStateRepository はサムネイル生成の処理状態の永続化を担当するインターフェース
+type ProcessingStateRepository interface {
+	// FindByImageID は指定された画像IDの処理状態を取得します（記録がない場合はnilを返します）
+	FindByImageID(ctx context.Context, imageID string) (*entity.ProcessingState, error)
+
+	// FindDueForRetry は再試行時刻を過ぎた失敗中の処理状態を最大limit件取得します
+	FindDueForRetry(ctx context.Context, now time.Time, limit int) ([]*entity.ProcessingState, error)
+
+	// Save は処理状態を保存します
+	// 画像のメタデータが存在しない場合は ErrProcessingStateImageNotFound を返し、処理状態だけのアイテムは作成しません
+	Save(ctx context.Context, state *entity.ProcessingState) error
+}
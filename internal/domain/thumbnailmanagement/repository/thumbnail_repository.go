@@ -3,11 +3,16 @@ package repository
 import (
 	"cloudpix/internal/domain/thumbnailmanagement/entity"
 	"context"
+	"errors"
 )
 
+// ErrThumbnailImageNotFound はサムネイル情報を保存する画像のメタデータが存在しない（削除された）場合のエラー
+var ErrThumbnailImageNotFound = errors.New("image for thumbnail not found")
+
 // ThumbnailRepository はサムネイル情報の永続化を担当するインターフェース
 type ThumbnailRepository interface {
 	// Save はサムネイル情報を保存します
+	// 画像のメタデータが存在しない場合は ErrThumbnailImageNotFound を返し、サムネイル属性だけのアイテムは作成しません
 	Save(ctx context.Context, thumbnail *entity.Thumbnail) error
 
 	// FindByImageID は指定された画像IDのサムネイルを取得します
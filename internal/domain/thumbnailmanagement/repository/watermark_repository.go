@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"cloudpix/internal/domain/thumbnailmanagement/entity"
+	"context"
+)
+
+// WatermarkRepository は透かし設定の永続化を担当するインターフェース
+type WatermarkRepository interface {
+	// FindByScope は指定されたスコープの透かし設定を取得します（存在しない場合は nil を返します）
+	FindByScope(ctx context.Context, scope string) (*entity.WatermarkSetting, error)
+
+	// Save は透かし設定を保存します
+	Save(ctx context.Context, setting *entity.WatermarkSetting) error
+
+	// Delete は指定されたスコープの透かし設定を削除します
+	Delete(ctx context.Context, scope string) error
+}
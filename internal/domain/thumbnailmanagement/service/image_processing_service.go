@@ -2,15 +2,53 @@ package service
 
 import (
 	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"errors"
+	"io"
 )
 
+// ErrSourceRead は元画像の読み込み中に発生したエラー（画像の内容ではなく転送の問題）を表します
+var ErrSourceRead = errors.New("failed to read source image")
+
+// RenditionOutput は生成されたレンディションの画像データとサイズを表します
+type RenditionOutput struct {
+	Spec       valueobject.RenditionSpec
+	Data       valueobject.ImageData
+	Dimensions valueobject.Dimensions
+}
+
+// ProcessingResult は一度のデコードで得られた画像処理の結果を表します
+type ProcessingResult struct {
+	Renditions     []RenditionOutput
+	PerceptualHash uint64
+	Palette        []valueobject.PaletteColor
+	BlurHash       string
+	LQIP           string // data URI形式の極小画像
+}
+
+// SourceImage はデコード済みの元画像を表します
+// 一度のデコード結果をサイズの取得、全レンディション、知覚ハッシュ、代表色の抽出で共有します
+type SourceImage interface {
+	// Dimensions はEXIFの向きを考慮した表示上の幅と高さを返します
+	Dimensions() valueobject.Dimensions
+
+	// ContentType は元画像のコンテンツタイプを返します
+	ContentType() string
+
+	// EXIF は元画像のEXIFから読み取った撮影機材などのフィールドを返します（EXIFがない場合は空）
+	EXIF() map[string]string
+}
+
 // ImageProcessingService は画像処理サービスのインターフェース
 type ImageProcessingService interface {
-	// DecodeImage は画像データをデコードして幅と高さを取得します
-	DecodeImage(data valueobject.ImageData) (int, int, error)
+	// Decode は元画像をストリームから一度だけデコードします
+	// keepAnimation が true の場合はアニメーションGIFの全フレームも保持します
+	Decode(r io.Reader, contentType string, keepAnimation bool) (SourceImage, error)
+
+	// GenerateRenditions はデコード済みの画像から指定されたすべてのレンディションと知覚ハッシュ、代表色、プレースホルダーを生成します
+	GenerateRenditions(source SourceImage, specs []valueobject.RenditionSpec) (*ProcessingResult, error)
 
-	// GenerateThumbnail はサムネイルを生成します
-	GenerateThumbnail(data valueobject.ImageData, targetWidth int) (valueobject.ImageData, valueobject.Dimensions, error)
+	// Render はデコード済みの画像を変換パラメータに従ってリサイズ・切り取りします
+	Render(source SourceImage, options valueobject.RenderOptions) (valueobject.ImageData, valueobject.Dimensions, error)
 
 	// ExtractImageID は画像キーから画像IDを抽出します
 	ExtractImageID(key string) (string, error)
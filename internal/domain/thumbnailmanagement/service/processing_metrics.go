@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// ProcessingStats は1枚の画像のサムネイル生成の計測結果を表します
+type ProcessingStats struct {
+	DecodeLatency    time.Duration
+	RenditionLatency time.Duration
+	UploadLatency    time.Duration
+	TotalLatency     time.Duration
+	PeakMemoryBytes  uint64
+}
+
+// ProcessingMetrics はサムネイル生成の処理時間とメモリ使用量を記録するインターフェース
+type ProcessingMetrics interface {
+	// TrackMemory はメモリ使用量の計測を開始し、計測を停止してピーク値を返す関数を返します
+	TrackMemory() func() uint64
+
+	// Record は計測結果を記録します
+	Record(ctx context.Context, stats ProcessingStats)
+}
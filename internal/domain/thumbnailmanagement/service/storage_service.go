@@ -3,6 +3,7 @@ package service
 import (
 	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
 	"context"
+	"io"
 )
 
 // StorageService はサムネイル画像のストレージサービスインターフェース
@@ -10,9 +11,15 @@ type StorageService interface {
 	// FetchImage はストレージから画像を取得します
 	FetchImage(ctx context.Context, bucket, key string) (valueobject.ImageData, error)
 
+	// OpenImage はストレージの画像をメモリに読み込まずにストリームとして開き、コンテンツタイプとともに返します
+	OpenImage(ctx context.Context, bucket, key string) (io.ReadCloser, string, error)
+
 	// UploadThumbnail はサムネイルをアップロードします
 	UploadThumbnail(ctx context.Context, bucket, key string, data valueobject.ImageData) error
 
+	// ObjectExists はオブジェクトが存在するかを確認します
+	ObjectExists(ctx context.Context, bucket, key string) (bool, error)
+
 	// GetObjectURL はオブジェクトの公開URLを生成します
 	GetObjectURL(bucket, key string) string
 
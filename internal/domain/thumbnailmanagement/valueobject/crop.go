@@ -0,0 +1,100 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CropMode はレンディションをターゲットサイズに合わせる切り取り方法を表します
+type CropMode string
+
+const (
+	// CropFit は縦横比を維持して最大サイズに収めます（切り取りなし）
+	CropFit CropMode = "fit"
+	// CropFill は中央を基準に切り取ってターゲットサイズを埋めます
+	CropFill CropMode = "fill"
+	// CropSmart はエッジの多い領域を残すように切り取ってターゲットサイズを埋めます
+	CropSmart CropMode = "smart"
+	// CropPad は縦横比を維持して収め、余白を背景色で埋めます
+	CropPad CropMode = "pad"
+)
+
+// パディングのデフォルト背景色
+const DefaultPadBackground = "#ffffff"
+
+// 背景色の形式（#RRGGBB）
+var backgroundPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// parseCropMode は切り取り方法を解析します（空の場合は fit）
+func parseCropMode(value string) (CropMode, error) {
+	mode := CropMode(strings.ToLower(strings.TrimSpace(value)))
+	switch mode {
+	case "":
+		return CropFit, nil
+	case CropFit, CropFill, CropSmart, CropPad:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("サポートされていない切り取り方法です: %s", value)
+	}
+}
+
+// normalizeBackground は背景色を #rrggbb 形式に正規化します（空の場合はデフォルト）
+func normalizeBackground(value string) (string, error) {
+	background := strings.ToLower(strings.TrimSpace(value))
+	if background == "" {
+		return DefaultPadBackground, nil
+	}
+	if !strings.HasPrefix(background, "#") {
+		background = "#" + background
+	}
+	if !backgroundPattern.MatchString(background) {
+		return "", fmt.Errorf("背景色は #RRGGBB 形式である必要があります: %s", value)
+	}
+	return background, nil
+}
+
+// CropBox は元画像のうちレンディションに使用した領域を表す値オブジェクト
+type CropBox struct {
+	x      int
+	y      int
+	width  int
+	height int
+}
+
+// NewCropBox は新しい切り取り領域を作成します
+func NewCropBox(x, y, width, height int) (CropBox, error) {
+	if x < 0 || y < 0 {
+		return CropBox{}, errors.New("切り取り位置は0以上である必要があります")
+	}
+	if width <= 0 || height <= 0 {
+		return CropBox{}, errors.New("切り取りサイズは正の値である必要があります")
+	}
+	return CropBox{x: x, y: y, width: width, height: height}, nil
+}
+
+// X は左端の位置を返します
+func (c CropBox) X() int {
+	return c.x
+}
+
+// Y は上端の位置を返します
+func (c CropBox) Y() int {
+	return c.y
+}
+
+// Width は幅を返します
+func (c CropBox) Width() int {
+	return c.width
+}
+
+// Height は高さを返します
+func (c CropBox) Height() int {
+	return c.height
+}
+
+// IsZero は切り取り領域が未設定かどうかを判定します
+func (c CropBox) IsZero() bool {
+	return c.width == 0 || c.height == 0
+}
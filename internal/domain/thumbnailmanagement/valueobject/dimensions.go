@@ -4,8 +4,9 @@ import "errors"
 
 // Dimensions はサムネイルの幅と高さを表す値オブジェクト
 type Dimensions struct {
-	width  int
-	height int
+	width   int
+	height  int
+	cropBox CropBox
 }
 
 // NewDimensions は新しいサイズの値オブジェクトを作成します
@@ -26,6 +27,17 @@ func (d Dimensions) Height() int {
 	return d.height
 }
 
+// CropBox は元画像のうち使用した領域を返します（未設定の場合はゼロ値）
+func (d Dimensions) CropBox() CropBox {
+	return d.cropBox
+}
+
+// WithCropBox は切り取り領域を設定した新しいサイズを返します
+func (d Dimensions) WithCropBox(cropBox CropBox) Dimensions {
+	d.cropBox = cropBox
+	return d
+}
+
 // AspectRatio はアスペクト比（幅/高さ）を返します
 func (d Dimensions) AspectRatio() float64 {
 	return float64(d.width) / float64(d.height)
@@ -0,0 +1,44 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PaletteColor は画像の代表色とその占有率を表す値オブジェクト
+type PaletteColor struct {
+	r      uint8
+	g      uint8
+	b      uint8
+	weight float64
+}
+
+// NewPaletteColor は新しい代表色を作成します
+func NewPaletteColor(r, g, b uint8, weight float64) (PaletteColor, error) {
+	if weight < 0 || weight > 1 {
+		return PaletteColor{}, errors.New("占有率は0から1の範囲である必要があります")
+	}
+	return PaletteColor{r: r, g: g, b: b, weight: weight}, nil
+}
+
+// Hex は色を #rrggbb 形式で返します
+func (c PaletteColor) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b)
+}
+
+// Weight は画像内での占有率（0〜1）を返します
+func (c PaletteColor) Weight() float64 {
+	return c.weight
+}
+
+// ParsePaletteColor は #rrggbb 形式の色と占有率から代表色を作成します
+func ParsePaletteColor(hex string, weight float64) (PaletteColor, error) {
+	var r, g, b uint8
+	if len(hex) != 7 || hex[0] != '#' {
+		return PaletteColor{}, errors.New("色は #rrggbb 形式である必要があります")
+	}
+	if _, err := fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return PaletteColor{}, errors.New("色は #rrggbb 形式である必要があります")
+	}
+	return NewPaletteColor(r, g, b, weight)
+}
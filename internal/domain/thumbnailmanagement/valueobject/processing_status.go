@@ -0,0 +1,34 @@
+package valueobject
+
+import "fmt"
+
+// ProcessingStatus はサムネイル生成の処理状態を表します
+type ProcessingStatus string
+
+const (
+	// ProcessingStatusPending はサムネイル生成を待っている状態
+	ProcessingStatusPending ProcessingStatus = "PENDING"
+	// ProcessingStatusSucceeded はサムネイル生成が成功した状態
+	ProcessingStatusSucceeded ProcessingStatus = "SUCCEEDED"
+	// ProcessingStatusFailed はサムネイル生成に失敗し、再試行を待っている状態
+	ProcessingStatusFailed ProcessingStatus = "FAILED"
+	// ProcessingStatusDeadLetter は再試行しても成功しないため処理を打ち切った状態
+	ProcessingStatusDeadLetter ProcessingStatus = "DEAD_LETTER"
+	// ProcessingStatusRejected は画像が処理できる大きさの上限を超えているため処理しない状態
+	ProcessingStatusRejected ProcessingStatus = "REJECTED"
+)
+
+// ParseProcessingStatus は文字列から処理状態を作成します
+func ParseProcessingStatus(value string) (ProcessingStatus, error) {
+	switch status := ProcessingStatus(value); status {
+	case ProcessingStatusPending, ProcessingStatusSucceeded, ProcessingStatusFailed, ProcessingStatusDeadLetter,
+		ProcessingStatusRejected:
+		return status, nil
+	}
+	return "", fmt.Errorf("unknown processing status: %s", value)
+}
+
+// String は処理状態の文字列表現を返します
+func (s ProcessingStatus) String() string {
+	return string(s)
+}
@@ -0,0 +1,258 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FitMode は画像をターゲットサイズに合わせる方法を表します
+type FitMode string
+
+const (
+	// FitContain は縦横比を維持してターゲットサイズに収めます
+	FitContain FitMode = "contain"
+	// FitCover は縦横比を維持してターゲットサイズを覆い、はみ出した部分を切り取ります
+	FitCover FitMode = "cover"
+	// FitFill は縦横比を無視してターゲットサイズに引き伸ばします
+	FitFill FitMode = "fill"
+)
+
+// Gravity は切り取り時に残す位置を表します
+type Gravity string
+
+const (
+	GravityCenter    Gravity = "center"
+	GravityNorth     Gravity = "north"
+	GravitySouth     Gravity = "south"
+	GravityEast      Gravity = "east"
+	GravityWest      Gravity = "west"
+	GravityNorthEast Gravity = "northeast"
+	GravityNorthWest Gravity = "northwest"
+	GravitySouthEast Gravity = "southeast"
+	GravitySouthWest Gravity = "southwest"
+)
+
+// 変換後の最大辺（ピクセル）
+const maxRenderSize = 4096
+
+// RenderOptions はオンデマンド画像変換のパラメータを表す値オブジェクト
+type RenderOptions struct {
+	width     int
+	height    int
+	fit       FitMode
+	gravity   Gravity
+	format    string
+	quality   int
+	watermark *Watermark
+}
+
+// NewRenderOptions は新しい変換パラメータを作成します
+// 空の fit と gravity はそれぞれ contain と center になり、空の format は元画像の形式を維持します
+func NewRenderOptions(width, height int, fit, gravity, format string, quality int) (RenderOptions, error) {
+	if width <= 0 || width > maxRenderSize {
+		return RenderOptions{}, fmt.Errorf("幅は1から%dの範囲である必要があります", maxRenderSize)
+	}
+	if height < 0 || height > maxRenderSize {
+		return RenderOptions{}, fmt.Errorf("高さは0から%dの範囲である必要があります", maxRenderSize)
+	}
+
+	fitMode := FitMode(strings.ToLower(fit))
+	switch fitMode {
+	case "":
+		fitMode = FitContain
+	case FitContain:
+	case FitCover, FitFill:
+		if height == 0 {
+			return RenderOptions{}, fmt.Errorf("fit=%s には高さの指定が必要です", fitMode)
+		}
+	default:
+		return RenderOptions{}, fmt.Errorf("サポートされていない fit です: %s", fit)
+	}
+
+	gravityValue := Gravity(strings.ToLower(gravity))
+	switch gravityValue {
+	case "":
+		gravityValue = GravityCenter
+	case GravityCenter, GravityNorth, GravitySouth, GravityEast, GravityWest,
+		GravityNorthEast, GravityNorthWest, GravitySouthEast, GravitySouthWest:
+	default:
+		return RenderOptions{}, fmt.Errorf("サポートされていない gravity です: %s", gravity)
+	}
+
+	if quality == 0 {
+		quality = DefaultRenditionQuality
+	}
+	if quality < 1 || quality > 100 {
+		return RenderOptions{}, errors.New("品質は1から100の範囲である必要があります")
+	}
+
+	contentType, err := normalizeRenditionFormat(format)
+	if err != nil {
+		return RenderOptions{}, err
+	}
+
+	return RenderOptions{
+		width:   width,
+		height:  height,
+		fit:     fitMode,
+		gravity: gravityValue,
+		format:  contentType,
+		quality: quality,
+	}, nil
+}
+
+// Width は幅を返します
+func (o RenderOptions) Width() int {
+	return o.width
+}
+
+// Height は高さを返します（0は幅に合わせて自動計算）
+func (o RenderOptions) Height() int {
+	return o.height
+}
+
+// Fit はフィットモードを返します
+func (o RenderOptions) Fit() FitMode {
+	return o.fit
+}
+
+// Gravity は切り取り位置を返します
+func (o RenderOptions) Gravity() Gravity {
+	return o.gravity
+}
+
+// Format は出力形式のコンテンツタイプを返します（空の場合は元画像の形式）
+func (o RenderOptions) Format() string {
+	return o.format
+}
+
+// Quality はエンコード品質を返します
+func (o RenderOptions) Quality() int {
+	return o.quality
+}
+
+// OutputContentType は元画像のコンテンツタイプを考慮した出力コンテンツタイプを返します
+func (o RenderOptions) OutputContentType(sourceContentType string) string {
+	return resolveOutputContentType(o.format, sourceContentType)
+}
+
+// Watermark は変換結果に重ねる透かしを返します（透かしがない場合は false）
+func (o RenderOptions) Watermark() (Watermark, bool) {
+	if o.watermark == nil {
+		return Watermark{}, false
+	}
+	return *o.watermark, true
+}
+
+// WithWatermark は透かしを設定した新しい変換パラメータを返します
+func (o RenderOptions) WithWatermark(watermark Watermark) RenderOptions {
+	o.watermark = &watermark
+	return o
+}
+
+// WithGravity は切り取り位置を差し替えた新しい変換パラメータを返します
+func (o RenderOptions) WithGravity(gravity string) (RenderOptions, error) {
+	return NewRenderOptions(o.width, o.height, string(o.fit), gravity, o.format, o.quality)
+}
+
+// CacheKey は変換パラメータから決定的なキャッシュキーを生成します
+// 透かしを重ねる場合は透かしの設定ごとに別のキーになります
+func (o RenderOptions) CacheKey(sourceContentType string) string {
+	extension := "jpg"
+	outputContentType := o.OutputContentType(sourceContentType)
+	if strings.Contains(outputContentType, "png") {
+		extension = "png"
+	} else if strings.Contains(outputContentType, "gif") {
+		extension = "gif"
+	}
+	if o.watermark != nil {
+		return fmt.Sprintf("w%d_h%d_%s_%s_q%d_wm%s.%s", o.width, o.height, o.fit, o.gravity, o.quality, o.watermark.Fingerprint(), extension)
+	}
+	return fmt.Sprintf("w%d_h%d_%s_%s_q%d.%s", o.width, o.height, o.fit, o.gravity, o.quality, extension)
+}
+
+// Matches は切り取り位置を除くパラメータが一致するかを判定します
+func (o RenderOptions) Matches(other RenderOptions) bool {
+	return o.width == other.width &&
+		o.height == other.height &&
+		o.fit == other.fit &&
+		o.format == other.format &&
+		o.quality == other.quality
+}
+
+// RenderPreset は許可された変換パラメータの名前付きプリセット
+type RenderPreset struct {
+	Name    string
+	Options RenderOptions
+}
+
+// DefaultRenderPresets はデフォルトの変換プリセットを返します
+func DefaultRenderPresets() []RenderPreset {
+	square, _ := NewRenderOptions(300, 300, string(FitCover), "", "", 0)
+	card, _ := NewRenderOptions(800, 600, string(FitContain), "", "", 0)
+	og, _ := NewRenderOptions(1200, 630, string(FitCover), "", "jpeg", 0)
+	return []RenderPreset{
+		{Name: "square", Options: square},
+		{Name: "card", Options: card},
+		{Name: "og", Options: og},
+	}
+}
+
+// ParseRenderPresets はカンマ区切りのプリセット定義を解析します
+// 形式: name=WIDTHxHEIGHT[:fit[:format[:quality]]]（例: "square=300x300:cover,og=1200x630:cover:jpeg:80"）
+func ParseRenderPresets(value string) ([]RenderPreset, error) {
+	if strings.TrimSpace(value) == "" {
+		return DefaultRenderPresets(), nil
+	}
+
+	presets := make([]RenderPreset, 0)
+	seen := make(map[string]bool)
+	for _, definition := range strings.Split(value, ",") {
+		nameAndSpec := strings.SplitN(strings.TrimSpace(definition), "=", 2)
+		if len(nameAndSpec) != 2 || !renditionNamePattern.MatchString(nameAndSpec[0]) {
+			return nil, fmt.Errorf("プリセット定義が不正です: %q", definition)
+		}
+
+		parts := strings.Split(nameAndSpec[1], ":")
+		if len(parts) > 4 {
+			return nil, fmt.Errorf("プリセット定義が不正です: %q", definition)
+		}
+
+		width, height, err := parseRenditionSize(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("プリセット定義が不正です: %q: %w", definition, err)
+		}
+
+		fit, format := "", ""
+		if len(parts) >= 2 {
+			fit = parts[1]
+		}
+		if len(parts) >= 3 {
+			format = parts[2]
+		}
+
+		quality := 0
+		if len(parts) == 4 {
+			quality, err = strconv.Atoi(parts[3])
+			if err != nil {
+				return nil, fmt.Errorf("プリセット定義の品質が不正です: %q", definition)
+			}
+		}
+
+		options, err := NewRenderOptions(width, height, fit, "", format, quality)
+		if err != nil {
+			return nil, fmt.Errorf("プリセット定義が不正です: %q: %w", definition, err)
+		}
+
+		if seen[nameAndSpec[0]] {
+			return nil, fmt.Errorf("プリセット名が重複しています: %s", nameAndSpec[0])
+		}
+		seen[nameAndSpec[0]] = true
+
+		presets = append(presets, RenderPreset{Name: nameAndSpec[0], Options: options})
+	}
+
+	return presets, nil
+}
@@ -0,0 +1,165 @@
+package valueobject
+
+import (
+	"testing"
+)
+
+func TestParseRenderPresets(t *testing.T) {
+	// wantPreset は解析結果として期待するプリセット
+	type wantPreset struct {
+		name    string
+		width   int
+		height  int
+		fit     FitMode
+		gravity Gravity
+		format  string
+		quality int
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  []wantPreset
+	}{
+		{
+			name:  "空はデフォルト",
+			value: "",
+			want: []wantPreset{
+				{name: "square", width: 300, height: 300, fit: FitCover, gravity: GravityCenter, quality: DefaultRenditionQuality},
+				{name: "card", width: 800, height: 600, fit: FitContain, gravity: GravityCenter, quality: DefaultRenditionQuality},
+				{name: "og", width: 1200, height: 630, fit: FitCover, gravity: GravityCenter, format: "image/jpeg", quality: DefaultRenditionQuality},
+			},
+		},
+		{
+			name:  "サイズのみ",
+			value: "card=800x600",
+			want: []wantPreset{
+				{name: "card", width: 800, height: 600, fit: FitContain, gravity: GravityCenter, quality: DefaultRenditionQuality},
+			},
+		},
+		{
+			name:  "幅のみ",
+			value: "wide=1024",
+			want: []wantPreset{
+				{name: "wide", width: 1024, fit: FitContain, gravity: GravityCenter, quality: DefaultRenditionQuality},
+			},
+		},
+		{
+			name:  "すべての項目",
+			value: "og=1200x630:cover:jpeg:80",
+			want: []wantPreset{
+				{name: "og", width: 1200, height: 630, fit: FitCover, gravity: GravityCenter, format: "image/jpeg", quality: 80},
+			},
+		},
+		{
+			name:  "複数のプリセットと表記ゆれ",
+			value: " square=300x300:COVER , banner=1200X400:fill:PNG ",
+			want: []wantPreset{
+				{name: "square", width: 300, height: 300, fit: FitCover, gravity: GravityCenter, quality: DefaultRenditionQuality},
+				{name: "banner", width: 1200, height: 400, fit: FitFill, gravity: GravityCenter, format: "image/png", quality: DefaultRenditionQuality},
+			},
+		},
+		{
+			name:  "最大サイズ",
+			value: "max=4096x4096",
+			want: []wantPreset{
+				{name: "max", width: 4096, height: 4096, fit: FitContain, gravity: GravityCenter, quality: DefaultRenditionQuality},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			presets, err := ParseRenderPresets(tt.value)
+			if err != nil {
+				t.Fatalf("ParseRenderPresets(%q) error = %v", tt.value, err)
+			}
+			if len(presets) != len(tt.want) {
+				t.Fatalf("ParseRenderPresets(%q) returned %d presets, want %d", tt.value, len(presets), len(tt.want))
+			}
+			for i, preset := range presets {
+				options := preset.Options
+				got := wantPreset{
+					name:    preset.Name,
+					width:   options.Width(),
+					height:  options.Height(),
+					fit:     options.Fit(),
+					gravity: options.Gravity(),
+					format:  options.Format(),
+					quality: options.Quality(),
+				}
+				if got != tt.want[i] {
+					t.Errorf("preset[%d] = %+v, want %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseRenderPresetsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "名前がない", value: "=300x300"},
+		{name: "区切りがない", value: "square:300x300"},
+		{name: "名前に使用できない文字", value: "Square=300x300"},
+		{name: "項目が多すぎる", value: "og=1200x630:cover:jpeg:80:extra"},
+		{name: "幅が数値でない", value: "square=widex300"},
+		{name: "幅が0", value: "square=0x300"},
+		{name: "最大サイズを超える幅", value: "huge=4097x100"},
+		{name: "最大サイズを超える高さ", value: "huge=100x4097"},
+		{name: "サポートされていないfit", value: "square=300x300:stretch"},
+		{name: "高さのないcover", value: "square=300:cover"},
+		{name: "高さのないfill", value: "square=300:fill"},
+		{name: "サポートされていない形式", value: "square=300x300:cover:webp"},
+		{name: "品質が数値でない", value: "square=300x300:cover:jpeg:best"},
+		{name: "品質が負", value: "square=300x300:cover:jpeg:-1"},
+		{name: "品質が大きすぎる", value: "square=300x300:cover:jpeg:101"},
+		{name: "名前が重複", value: "square=300x300,square=600x600"},
+		{name: "空の定義", value: "square=300x300,,card=800x600"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if presets, err := ParseRenderPresets(tt.value); err == nil {
+				t.Errorf("ParseRenderPresets(%q) = %d presets, want error", tt.value, len(presets))
+			}
+		})
+	}
+}
+
+func TestRenderOptionsCacheKey(t *testing.T) {
+	options, err := NewRenderOptions(300, 200, "cover", "north", "", 0)
+	if err != nil {
+		t.Fatalf("NewRenderOptions() error = %v", err)
+	}
+	watermark, err := NewWatermark("text", "CloudPix", "", "", DefaultWatermarkOpacity, DefaultWatermarkScale)
+	if err != nil {
+		t.Fatalf("NewWatermark() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		options     RenderOptions
+		contentType string
+		want        string
+	}{
+		{name: "元画像の形式を維持", options: options, contentType: "image/png", want: "w300_h200_cover_north_q85.png"},
+		{name: "出力に対応していない形式はJPEG", options: options, contentType: "image/webp", want: "w300_h200_cover_north_q85.jpg"},
+		{
+			name:        "透かしごとに別のキー",
+			options:     options.WithWatermark(watermark),
+			contentType: "image/gif",
+			want:        "w300_h200_cover_north_q85_wm" + watermark.Fingerprint() + ".gif",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.options.CacheKey(tt.contentType); got != tt.want {
+				t.Errorf("CacheKey(%q) = %s, want %s", tt.contentType, got, tt.want)
+			}
+		})
+	}
+}
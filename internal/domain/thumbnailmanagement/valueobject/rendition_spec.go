@@ -0,0 +1,281 @@
+package valueobject
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// レンディション名に使用できる文字
+var renditionNamePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// デフォルトのエンコード品質
+const DefaultRenditionQuality = 85
+
+// RenditionSpec はサムネイルのレンディション（サイズ違いの派生画像）の仕様を表す値オブジェクト
+type RenditionSpec struct {
+	name       string
+	maxWidth   int
+	maxHeight  int
+	format     string
+	quality    int
+	animated   bool
+	crop       CropMode
+	background string
+	watermark  *Watermark
+}
+
+// NewRenditionSpec は新しいレンディション仕様を作成します
+// maxHeight が0の場合は幅のみで制限し、format が空の場合は元画像の形式を維持します
+func NewRenditionSpec(name string, maxWidth, maxHeight int, format string, quality int) (RenditionSpec, error) {
+	if !renditionNamePattern.MatchString(name) {
+		return RenditionSpec{}, fmt.Errorf("レンディション名が不正です: %q", name)
+	}
+	if maxWidth <= 0 {
+		return RenditionSpec{}, errors.New("最大幅は正の値である必要があります")
+	}
+	if maxHeight < 0 {
+		return RenditionSpec{}, errors.New("最大高さは0以上である必要があります")
+	}
+	if quality == 0 {
+		quality = DefaultRenditionQuality
+	}
+	if quality < 1 || quality > 100 {
+		return RenditionSpec{}, errors.New("品質は1から100の範囲である必要があります")
+	}
+
+	contentType, err := normalizeRenditionFormat(format)
+	if err != nil {
+		return RenditionSpec{}, err
+	}
+
+	return RenditionSpec{
+		name:       name,
+		maxWidth:   maxWidth,
+		maxHeight:  maxHeight,
+		format:     contentType,
+		quality:    quality,
+		crop:       CropFit,
+		background: DefaultPadBackground,
+	}, nil
+}
+
+// normalizeRenditionFormat は出力形式をコンテンツタイプに正規化します
+func normalizeRenditionFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "":
+		return "", nil
+	case "jpeg", "jpg", "image/jpeg", "image/jpg":
+		return "image/jpeg", nil
+	case "png", "image/png":
+		return "image/png", nil
+	case "gif", "image/gif":
+		return "image/gif", nil
+	default:
+		return "", fmt.Errorf("サポートされていない出力形式です: %s", format)
+	}
+}
+
+// resolveOutputContentType は出力形式と元画像の形式から出力コンテンツタイプを決定します
+// 出力形式が未指定で元画像が出力に対応していない形式（WebP・BMP・TIFFなど）の場合はJPEGになります
+func resolveOutputContentType(format, sourceContentType string) string {
+	if format != "" {
+		return format
+	}
+	if contentType, err := normalizeRenditionFormat(sourceContentType); err == nil && contentType != "" {
+		return contentType
+	}
+	return "image/jpeg"
+}
+
+// Name はレンディション名を返します
+func (r RenditionSpec) Name() string {
+	return r.name
+}
+
+// MaxWidth は最大幅を返します
+func (r RenditionSpec) MaxWidth() int {
+	return r.maxWidth
+}
+
+// MaxHeight は最大高さを返します（0は制限なし）
+func (r RenditionSpec) MaxHeight() int {
+	return r.maxHeight
+}
+
+// Format は出力形式のコンテンツタイプを返します（空の場合は元画像の形式）
+func (r RenditionSpec) Format() string {
+	return r.format
+}
+
+// Quality はエンコード品質を返します
+func (r RenditionSpec) Quality() int {
+	return r.quality
+}
+
+// Animated はアニメーションGIFのアニメーションを維持するかを返します
+func (r RenditionSpec) Animated() bool {
+	return r.animated
+}
+
+// WithAnimated はアニメーション維持の設定を変更した新しいレンディション仕様を返します
+func (r RenditionSpec) WithAnimated(animated bool) RenditionSpec {
+	r.animated = animated
+	return r
+}
+
+// Crop は切り取り方法を返します
+func (r RenditionSpec) Crop() CropMode {
+	return r.crop
+}
+
+// Background はパディングの背景色（#rrggbb）を返します
+func (r RenditionSpec) Background() string {
+	return r.background
+}
+
+// WithCrop は切り取り方法と背景色を設定した新しいレンディション仕様を返します
+// fit 以外の切り取り方法では最大高さの指定が必要です
+func (r RenditionSpec) WithCrop(crop, background string) (RenditionSpec, error) {
+	mode, err := parseCropMode(crop)
+	if err != nil {
+		return RenditionSpec{}, err
+	}
+	if mode != CropFit && r.maxHeight == 0 {
+		return RenditionSpec{}, fmt.Errorf("切り取り方法 %s には高さの指定が必要です", mode)
+	}
+
+	normalized, err := normalizeBackground(background)
+	if err != nil {
+		return RenditionSpec{}, err
+	}
+
+	r.crop = mode
+	r.background = normalized
+	return r, nil
+}
+
+// Watermark はレンディションに重ねる透かしを返します（透かしがない場合は false）
+func (r RenditionSpec) Watermark() (Watermark, bool) {
+	if r.watermark == nil {
+		return Watermark{}, false
+	}
+	return *r.watermark, true
+}
+
+// WithWatermark は透かしを設定した新しいレンディション仕様を返します
+func (r RenditionSpec) WithWatermark(watermark Watermark) RenditionSpec {
+	r.watermark = &watermark
+	return r
+}
+
+// OutputContentType は元画像のコンテンツタイプを考慮した出力コンテンツタイプを返します
+func (r RenditionSpec) OutputContentType(sourceContentType string) string {
+	return resolveOutputContentType(r.format, sourceContentType)
+}
+
+// DefaultRenditionSpecs はデフォルトのレンディション仕様を返します
+func DefaultRenditionSpecs() []RenditionSpec {
+	return []RenditionSpec{
+		{name: "small", maxWidth: 150, maxHeight: 150, quality: DefaultRenditionQuality, crop: CropFit, background: DefaultPadBackground},
+		{name: "medium", maxWidth: 600, maxHeight: 600, quality: DefaultRenditionQuality, crop: CropFit, background: DefaultPadBackground},
+		{name: "large", maxWidth: 1600, maxHeight: 1600, quality: DefaultRenditionQuality, crop: CropFit, background: DefaultPadBackground},
+	}
+}
+
+// ParseRenditionSpecs はカンマ区切りのレンディション定義を解析します
+// 形式: name:WIDTHxHEIGHT[:format[:quality[:crop[:background]]]]
+// （例: "small:150x150:::smart,medium:600x600:jpeg:80,banner:1200x400:png::pad:#000000"）
+func ParseRenditionSpecs(value string) ([]RenditionSpec, error) {
+	if strings.TrimSpace(value) == "" {
+		return DefaultRenditionSpecs(), nil
+	}
+
+	specs := make([]RenditionSpec, 0)
+	seen := make(map[string]bool)
+	for _, definition := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(definition), ":")
+		if len(parts) < 2 || len(parts) > 6 {
+			return nil, fmt.Errorf("レンディション定義が不正です: %q", definition)
+		}
+
+		width, height, err := parseRenditionSize(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("レンディション定義が不正です: %q: %w", definition, err)
+		}
+
+		format := ""
+		if len(parts) >= 3 {
+			format = parts[2]
+		}
+
+		quality := 0
+		if len(parts) >= 4 && parts[3] != "" {
+			quality, err = strconv.Atoi(parts[3])
+			if err != nil {
+				return nil, fmt.Errorf("レンディション定義の品質が不正です: %q", definition)
+			}
+		}
+
+		spec, err := NewRenditionSpec(parts[0], width, height, format, quality)
+		if err != nil {
+			return nil, err
+		}
+
+		crop, background := "", ""
+		if len(parts) >= 5 {
+			crop = parts[4]
+		}
+		if len(parts) == 6 {
+			background = parts[5]
+		}
+		spec, err = spec.WithCrop(crop, background)
+		if err != nil {
+			return nil, fmt.Errorf("レンディション定義が不正です: %q: %w", definition, err)
+		}
+
+		if seen[spec.Name()] {
+			return nil, fmt.Errorf("レンディション名が重複しています: %s", spec.Name())
+		}
+		seen[spec.Name()] = true
+
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+// RenditionConfigVersion はレンディション定義から設定のバージョンを計算します
+// 定義が変わるとバージョンも変わるため、古い設定で生成されたサムネイルの検出に使用します
+func RenditionConfigVersion(specs []RenditionSpec) string {
+	hash := sha256.New()
+	for _, spec := range specs {
+		fmt.Fprintf(hash, "%s:%dx%d:%s:%d:%s:%s:%t;",
+			spec.name, spec.maxWidth, spec.maxHeight, spec.format, spec.quality, spec.crop, spec.background, spec.animated)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+// parseRenditionSize は "WIDTHxHEIGHT" または "WIDTH" 形式のサイズを解析します
+func parseRenditionSize(value string) (int, int, error) {
+	sizes := strings.SplitN(strings.ToLower(value), "x", 2)
+
+	width, err := strconv.Atoi(sizes[0])
+	if err != nil {
+		return 0, 0, errors.New("幅が数値ではありません")
+	}
+
+	height := 0
+	if len(sizes) == 2 {
+		height, err = strconv.Atoi(sizes[1])
+		if err != nil {
+			return 0, 0, errors.New("高さが数値ではありません")
+		}
+	}
+
+	return width, height, nil
+}
@@ -0,0 +1,158 @@
+package valueobject
+
+import (
+	"testing"
+)
+
+func TestParseRenditionSpecs(t *testing.T) {
+	// wantSpec は解析結果として期待するレンディション仕様
+	type wantSpec struct {
+		name       string
+		width      int
+		height     int
+		format     string
+		quality    int
+		crop       CropMode
+		background string
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  []wantSpec
+	}{
+		{
+			name:  "空はデフォルト",
+			value: "  ",
+			want: []wantSpec{
+				{name: "small", width: 150, height: 150, quality: DefaultRenditionQuality, crop: CropFit, background: DefaultPadBackground},
+				{name: "medium", width: 600, height: 600, quality: DefaultRenditionQuality, crop: CropFit, background: DefaultPadBackground},
+				{name: "large", width: 1600, height: 1600, quality: DefaultRenditionQuality, crop: CropFit, background: DefaultPadBackground},
+			},
+		},
+		{
+			name:  "名前とサイズのみ",
+			value: "small:150x150",
+			want: []wantSpec{
+				{name: "small", width: 150, height: 150, quality: DefaultRenditionQuality, crop: CropFit, background: DefaultPadBackground},
+			},
+		},
+		{
+			name:  "幅のみ",
+			value: "wide:800",
+			want: []wantSpec{
+				{name: "wide", width: 800, quality: DefaultRenditionQuality, crop: CropFit, background: DefaultPadBackground},
+			},
+		},
+		{
+			name:  "すべての項目",
+			value: "banner:1200x400:png:70:pad:#000000",
+			want: []wantSpec{
+				{name: "banner", width: 1200, height: 400, format: "image/png", quality: 70, crop: CropPad, background: "#000000"},
+			},
+		},
+		{
+			name:  "形式・切り取り方法・背景色の表記ゆれを正規化",
+			value: "a:100x100:JPG::SMART, b:200X100:::pad:FF8800",
+			want: []wantSpec{
+				{name: "a", width: 100, height: 100, format: "image/jpeg", quality: DefaultRenditionQuality, crop: CropSmart, background: DefaultPadBackground},
+				{name: "b", width: 200, height: 100, quality: DefaultRenditionQuality, crop: CropPad, background: "#ff8800"},
+			},
+		},
+		{
+			name:  "品質0はデフォルト",
+			value: "small:150x150::0:fill",
+			want: []wantSpec{
+				{name: "small", width: 150, height: 150, quality: DefaultRenditionQuality, crop: CropFill, background: DefaultPadBackground},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs, err := ParseRenditionSpecs(tt.value)
+			if err != nil {
+				t.Fatalf("ParseRenditionSpecs(%q) error = %v", tt.value, err)
+			}
+			if len(specs) != len(tt.want) {
+				t.Fatalf("ParseRenditionSpecs(%q) returned %d specs, want %d", tt.value, len(specs), len(tt.want))
+			}
+			for i, spec := range specs {
+				got := wantSpec{
+					name:       spec.Name(),
+					width:      spec.MaxWidth(),
+					height:     spec.MaxHeight(),
+					format:     spec.Format(),
+					quality:    spec.Quality(),
+					crop:       spec.Crop(),
+					background: spec.Background(),
+				}
+				if got != tt.want[i] {
+					t.Errorf("spec[%d] = %+v, want %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseRenditionSpecsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "サイズがない", value: "small"},
+		{name: "項目が多すぎる", value: "small:150x150:jpeg:80:pad:#ffffff:extra"},
+		{name: "幅が数値でない", value: "small:abcx150"},
+		{name: "高さが数値でない", value: "small:150xabc"},
+		{name: "高さが空", value: "small:150x"},
+		{name: "幅が0", value: "small:0x150"},
+		{name: "高さが負", value: "small:150x-1"},
+		{name: "名前に大文字", value: "Small:150x150"},
+		{name: "名前が空", value: ":150x150"},
+		{name: "サポートされていない形式", value: "small:150x150:webp"},
+		{name: "品質が数値でない", value: "small:150x150::high"},
+		{name: "品質が範囲外", value: "small:150x150::101"},
+		{name: "サポートされていない切り取り方法", value: "small:150x150:::zoom"},
+		{name: "高さのない切り取り", value: "small:150:::fill"},
+		{name: "背景色の形式が不正", value: "small:150x150:::pad:#12345"},
+		{name: "名前が重複", value: "small:150x150,small:300x300"},
+		{name: "末尾の空の定義", value: "small:150x150,"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if specs, err := ParseRenditionSpecs(tt.value); err == nil {
+				t.Errorf("ParseRenditionSpecs(%q) = %d specs, want error", tt.value, len(specs))
+			}
+		})
+	}
+}
+
+func TestRenditionConfigVersion(t *testing.T) {
+	parse := func(value string) []RenditionSpec {
+		t.Helper()
+		specs, err := ParseRenditionSpecs(value)
+		if err != nil {
+			t.Fatalf("ParseRenditionSpecs(%q) error = %v", value, err)
+		}
+		return specs
+	}
+
+	base := RenditionConfigVersion(parse("small:150x150,medium:600x600"))
+	if got := RenditionConfigVersion(parse("small:150x150, medium:600x600:::fit")); got != base {
+		t.Errorf("equivalent definitions have different versions: %s, %s", got, base)
+	}
+
+	changed := []string{
+		"small:150x150,medium:600x600::80",
+		"small:150x150,medium:600x600:png",
+		"small:150x150,medium:600x600:::smart",
+		"small:150x150,medium:600x400",
+		"medium:600x600,small:150x150",
+	}
+	for _, value := range changed {
+		if got := RenditionConfigVersion(parse(value)); got == base {
+			t.Errorf("RenditionConfigVersion(%q) = %s, want different from %s", value, got, base)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package valueobject
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WatermarkType は透かしの種類を表します
+type WatermarkType string
+
+const (
+	// WatermarkText は文字列の透かし
+	WatermarkText WatermarkType = "text"
+	// WatermarkImage は画像（ロゴなど）の透かし
+	WatermarkImage WatermarkType = "image"
+)
+
+// WatermarkPosition は透かしを配置する位置を表します
+type WatermarkPosition string
+
+const (
+	WatermarkTopLeft     WatermarkPosition = "top-left"
+	WatermarkTop         WatermarkPosition = "top"
+	WatermarkTopRight    WatermarkPosition = "top-right"
+	WatermarkLeft        WatermarkPosition = "left"
+	WatermarkCenter      WatermarkPosition = "center"
+	WatermarkRight       WatermarkPosition = "right"
+	WatermarkBottomLeft  WatermarkPosition = "bottom-left"
+	WatermarkBottom      WatermarkPosition = "bottom"
+	WatermarkBottomRight WatermarkPosition = "bottom-right"
+)
+
+const (
+	// DefaultWatermarkOpacity はデフォルトの不透明度
+	DefaultWatermarkOpacity = 0.5
+	// DefaultWatermarkScale はデフォルトの画像幅に対する透かしの幅の割合
+	DefaultWatermarkScale = 0.25
+	// MaxWatermarkTextLength は透かし文字列の最大文字数
+	MaxWatermarkTextLength = 64
+)
+
+// Watermark はレンディションに重ねる透かしを表す値オブジェクト
+type Watermark struct {
+	kind     WatermarkType
+	text     string
+	imageKey string
+	position WatermarkPosition
+	opacity  float64
+	scale    float64
+	overlay  ImageData
+}
+
+// NewWatermark は新しい透かしを作成します
+// 文字列の透かしは text、画像の透かしは imageKey（S3のオブジェクトキー）を指定します
+func NewWatermark(kind, text, imageKey, position string, opacity, scale float64) (Watermark, error) {
+	watermarkType := WatermarkType(strings.ToLower(strings.TrimSpace(kind)))
+	switch watermarkType {
+	case WatermarkText:
+		if err := validateWatermarkText(text); err != nil {
+			return Watermark{}, err
+		}
+		imageKey = ""
+	case WatermarkImage:
+		if imageKey == "" {
+			return Watermark{}, errors.New("画像の透かしには画像の指定が必要です")
+		}
+		text = ""
+	default:
+		return Watermark{}, fmt.Errorf("サポートされていない透かしの種類です: %s", kind)
+	}
+
+	watermarkPosition, err := parseWatermarkPosition(position)
+	if err != nil {
+		return Watermark{}, err
+	}
+	if opacity <= 0 || opacity > 1 {
+		return Watermark{}, errors.New("不透明度は0より大きく1以下である必要があります")
+	}
+	if scale <= 0 || scale > 1 {
+		return Watermark{}, errors.New("透かしの大きさは0より大きく1以下である必要があります")
+	}
+
+	return Watermark{
+		kind:     watermarkType,
+		text:     text,
+		imageKey: imageKey,
+		position: watermarkPosition,
+		opacity:  opacity,
+		scale:    scale,
+	}, nil
+}
+
+// validateWatermarkText は透かし文字列を検証します
+// 透かしは1行で描画するため、改行などの制御文字は使用できません
+func validateWatermarkText(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return errors.New("文字列の透かしには文字列の指定が必要です")
+	}
+	if !utf8.ValidString(text) {
+		return errors.New("透かし文字列はUTF-8である必要があります")
+	}
+	if utf8.RuneCountInString(text) > MaxWatermarkTextLength {
+		return fmt.Errorf("透かし文字列は%d文字以内である必要があります", MaxWatermarkTextLength)
+	}
+	for _, r := range text {
+		if unicode.IsControl(r) {
+			return errors.New("透かし文字列には制御文字を使用できません")
+		}
+	}
+	return nil
+}
+
+// parseWatermarkPosition は透かしの位置を解析します（空の場合は右下）
+func parseWatermarkPosition(value string) (WatermarkPosition, error) {
+	position := WatermarkPosition(strings.ToLower(strings.TrimSpace(value)))
+	switch position {
+	case "":
+		return WatermarkBottomRight, nil
+	case WatermarkTopLeft, WatermarkTop, WatermarkTopRight,
+		WatermarkLeft, WatermarkCenter, WatermarkRight,
+		WatermarkBottomLeft, WatermarkBottom, WatermarkBottomRight:
+		return position, nil
+	default:
+		return "", fmt.Errorf("サポートされていない透かしの位置です: %s", value)
+	}
+}
+
+// Type は透かしの種類を返します
+func (w Watermark) Type() WatermarkType {
+	return w.kind
+}
+
+// Text は透かし文字列を返します
+func (w Watermark) Text() string {
+	return w.text
+}
+
+// ImageKey は透かし画像のS3オブジェクトキーを返します
+func (w Watermark) ImageKey() string {
+	return w.imageKey
+}
+
+// Position は透かしの位置を返します
+func (w Watermark) Position() WatermarkPosition {
+	return w.position
+}
+
+// Opacity は不透明度（0〜1）を返します
+func (w Watermark) Opacity() float64 {
+	return w.opacity
+}
+
+// Scale は画像幅に対する透かしの幅の割合を返します
+func (w Watermark) Scale() float64 {
+	return w.scale
+}
+
+// Overlay は読み込み済みの透かし画像を返します
+func (w Watermark) Overlay() ImageData {
+	return w.overlay
+}
+
+// WithOverlay は透かし画像のデータを設定した新しい透かしを返します
+func (w Watermark) WithOverlay(overlay ImageData) Watermark {
+	w.overlay = overlay
+	return w
+}
+
+// Fingerprint は透かしの設定から決定的な短い識別子を生成します（読み込み済みの透かし画像は含みません）
+func (w Watermark) Fingerprint() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%s|%g|%g", w.kind, w.text, w.imageKey, w.position, w.opacity, w.scale)))
+	return hex.EncodeToString(sum[:6])
+}
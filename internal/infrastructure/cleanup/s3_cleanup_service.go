@@ -1,7 +1,10 @@
 package cleanup
 
 import (
+	"cloudpix/internal/domain/imagemanagement/repository"
 	"cloudpix/internal/domain/imagemanagement/service"
+	"cloudpix/internal/domain/imagemanagement/valueobject"
+	tagrepository "cloudpix/internal/domain/tagmanagement/repository"
 	"cloudpix/internal/logging"
 	"context"
 	"fmt"
@@ -19,7 +22,9 @@ type S3CleanupService struct {
 	dynamoClient  *dynamodb.DynamoDB
 	bucketName    string
 	metadataTable string
-	tagsTableName string
+
+	tagRepository        tagrepository.TagRepository
+	similarityRepository repository.SimilarityRepository
 }
 
 // NewS3CleanupService は新しいS3クリーンアップサービスを作成
@@ -28,14 +33,16 @@ func NewS3CleanupService(
 	dynamoClient *dynamodb.DynamoDB,
 	bucketName string,
 	metadataTable string,
-	tagsTableName string,
+	tagRepository tagrepository.TagRepository,
+	similarityRepository repository.SimilarityRepository,
 ) service.CleanupService {
 	return &S3CleanupService{
-		s3Client:      s3Client,
-		dynamoClient:  dynamoClient,
-		bucketName:    bucketName,
-		metadataTable: metadataTable,
-		tagsTableName: tagsTableName,
+		s3Client:             s3Client,
+		dynamoClient:         dynamoClient,
+		bucketName:           bucketName,
+		metadataTable:        metadataTable,
+		tagRepository:        tagRepository,
+		similarityRepository: similarityRepository,
 	}
 }
 
@@ -69,6 +76,26 @@ func (s *S3CleanupService) getObjectKey(metadata map[string]*dynamodb.AttributeV
 	return "", fmt.Errorf("S3ObjectKey not found for image: %s", imageID)
 }
 
+// getRenditionKeys はメタデータからレンディションのS3オブジェクトキーを抽出する共通メソッド
+func (s *S3CleanupService) getRenditionKeys(metadata map[string]*dynamodb.AttributeValue) []string {
+	keys := make([]string, 0)
+	val, ok := metadata["Renditions"]
+	if !ok || val.L == nil {
+		return keys
+	}
+
+	for _, rendition := range val.L {
+		if rendition.M == nil {
+			continue
+		}
+		if key, ok := rendition.M["Key"]; ok && key.S != nil {
+			keys = append(keys, *key.S)
+		}
+	}
+
+	return keys
+}
+
 // ArchiveImage は画像をアーカイブバケットに移動
 func (s *S3CleanupService) ArchiveImage(ctx context.Context, imageID string) error {
 	// DynamoDBから画像メタデータを取得
@@ -173,11 +200,32 @@ func (s *S3CleanupService) DeleteImage(ctx context.Context, imageID string) erro
 				"imageid": imageID,
 			})
 		}
+
+		// 各レンディションを削除
+		for _, renditionKey := range s.getRenditionKeys(metadata) {
+			if err := s.deleteS3Object(ctx, renditionKey); err != nil {
+				logger.Warn(fmt.Sprintf("Failed to delete rendition: %v", err), map[string]interface{}{
+					"imageid": imageID,
+					"key":     renditionKey,
+				})
+			}
+		}
 	}
 
-	// タグ情報を削除
-	if err := s.deleteImageTags(ctx, imageID); err != nil {
-		return err
+	// タグ情報を削除（利用数もあわせて減らすため、メタデータより先にリポジトリ経由で削除する）
+	if err := s.tagRepository.Delete(ctx, imageID); err != nil {
+		return fmt.Errorf("failed to delete tags: %w", err)
+	}
+
+	// 類似画像インデックスから削除
+	if val, ok := metadata["PerceptualHash"]; ok && val.S != nil {
+		if hash, err := valueobject.ParsePerceptualHash(*val.S); err == nil {
+			if err := s.similarityRepository.Remove(ctx, imageID, hash); err != nil {
+				logger.Warn(fmt.Sprintf("Failed to remove perceptual hash: %v", err), map[string]interface{}{
+					"imageid": imageID,
+				})
+			}
+		}
 	}
 
 	// メタデータを削除
@@ -215,74 +263,6 @@ func (s *S3CleanupService) deleteThumbnail(ctx context.Context, logger logging.L
 	return nil
 }
 
-// deleteImageTags は画像に関連するタグを削除する共通メソッド
-func (s *S3CleanupService) deleteImageTags(ctx context.Context, imageID string) error {
-	// タグ情報を検索
-	queryResult, err := s.dynamoClient.QueryWithContext(ctx, &dynamodb.QueryInput{
-		TableName:              aws.String(s.tagsTableName),
-		IndexName:              aws.String("ImageIDIndex"),
-		KeyConditionExpression: aws.String("ImageID = :imageId"),
-		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-			":imageId": {
-				S: aws.String(imageID),
-			},
-		},
-	})
-
-	if err != nil {
-		return fmt.Errorf("failed to query tags for image: %w", err)
-	}
-
-	// タグのバッチ削除（存在する場合）
-	if len(queryResult.Items) > 0 {
-		// バッチ書き込みリクエストを作成
-		var writeRequests []*dynamodb.WriteRequest
-
-		for _, item := range queryResult.Items {
-			// TagNameを取得
-			var tagName string
-			if val, ok := item["TagName"]; ok && val.S != nil {
-				tagName = *val.S
-			} else {
-				continue
-			}
-
-			// 削除リクエストを追加
-			writeRequests = append(writeRequests, &dynamodb.WriteRequest{
-				DeleteRequest: &dynamodb.DeleteRequest{
-					Key: map[string]*dynamodb.AttributeValue{
-						"TagName": {
-							S: aws.String(tagName),
-						},
-						"ImageID": {
-							S: aws.String(imageID),
-						},
-					},
-				},
-			})
-		}
-
-		// バッチ処理（25件ずつ）
-		const batchSize = 25
-		for i := 0; i < len(writeRequests); i += batchSize {
-			end := min(i+batchSize, len(writeRequests))
-
-			batch := writeRequests[i:end]
-			_, err := s.dynamoClient.BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{
-				RequestItems: map[string][]*dynamodb.WriteRequest{
-					s.tagsTableName: batch,
-				},
-			})
-
-			if err != nil {
-				return fmt.Errorf("failed to delete tags: %w", err)
-			}
-		}
-	}
-
-	return nil
-}
-
 // deleteImageMetadata は画像メタデータを削除する共通メソッド
 func (s *S3CleanupService) deleteImageMetadata(ctx context.Context, imageID string) error {
 	_, err := s.dynamoClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
@@ -0,0 +1,205 @@
+package imaging
+
+import (
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"encoding/hex"
+	"image"
+	"image/color"
+
+	"github.com/disintegration/imaging"
+)
+
+// スマートクロップでエッジ量を計算する際の作業画像の最大辺
+const smartCropWorkSize = 256
+
+// cropRegion は切り取り方法に応じて元画像のうち使用する領域を決定します
+// fit と pad は画像全体を使用します
+func cropRegion(img image.Image, spec valueobject.RenditionSpec) image.Rectangle {
+	bounds := img.Bounds()
+
+	switch spec.Crop() {
+	case valueobject.CropFill:
+		return centerRegion(bounds, spec.MaxWidth(), spec.MaxHeight())
+	case valueobject.CropSmart:
+		return smartRegion(img, spec.MaxWidth(), spec.MaxHeight())
+	default:
+		return bounds
+	}
+}
+
+// applyCrop は決定済みの領域を使用してレンディションの画像を生成します
+func applyCrop(img image.Image, region image.Rectangle, spec valueobject.RenditionSpec) image.Image {
+	switch spec.Crop() {
+	case valueobject.CropFill, valueobject.CropSmart:
+		cropped := imaging.Crop(img, region)
+		// 切り取った領域がターゲットより小さい場合は拡大せず、縦横比を維持して領域の大きさのまま使用
+		if region.Dx() < spec.MaxWidth() || region.Dy() < spec.MaxHeight() {
+			return fitWithin(cropped, spec.MaxWidth(), spec.MaxHeight())
+		}
+		return imaging.Resize(cropped, spec.MaxWidth(), spec.MaxHeight(), imaging.Lanczos)
+	case valueobject.CropPad:
+		return padTo(fitWithin(img, spec.MaxWidth(), spec.MaxHeight()), spec.MaxWidth(), spec.MaxHeight(), parseBackground(spec.Background()))
+	default:
+		// 最大サイズに収まるよう縮小（拡大はしない）
+		return fitWithin(img, spec.MaxWidth(), spec.MaxHeight())
+	}
+}
+
+// toCropBox は切り取り領域を値オブジェクトに変換します
+func toCropBox(bounds, region image.Rectangle) valueobject.CropBox {
+	cropBox, _ := valueobject.NewCropBox(
+		region.Min.X-bounds.Min.X,
+		region.Min.Y-bounds.Min.Y,
+		region.Dx(),
+		region.Dy(),
+	)
+	return cropBox
+}
+
+// centerRegion はターゲットの縦横比で中央を切り取る領域を返します
+func centerRegion(bounds image.Rectangle, targetWidth, targetHeight int) image.Rectangle {
+	width, height := cropSize(bounds.Dx(), bounds.Dy(), targetWidth, targetHeight)
+	x := bounds.Min.X + (bounds.Dx()-width)/2
+	y := bounds.Min.Y + (bounds.Dy()-height)/2
+	return image.Rect(x, y, x+width, y+height)
+}
+
+// cropSize は元画像に収まる最大のターゲット縦横比の領域サイズを返します
+func cropSize(srcWidth, srcHeight, targetWidth, targetHeight int) (int, int) {
+	// 元画像の方が横長の場合は幅を、縦長の場合は高さを切り詰める
+	if srcWidth*targetHeight > srcHeight*targetWidth {
+		width := srcHeight * targetWidth / targetHeight
+		if width < 1 {
+			width = 1
+		}
+		return width, srcHeight
+	}
+
+	height := srcWidth * targetHeight / targetWidth
+	if height < 1 {
+		height = 1
+	}
+	return srcWidth, height
+}
+
+// smartRegion はエッジ量が最大となるようにターゲットの縦横比で切り取る領域を返します
+func smartRegion(img image.Image, targetWidth, targetHeight int) image.Rectangle {
+	bounds := img.Bounds()
+	width, height := cropSize(bounds.Dx(), bounds.Dy(), targetWidth, targetHeight)
+
+	// 切り取りが不要な場合は画像全体
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return bounds
+	}
+
+	// 作業用に縮小したグレースケール画像でエッジ量を計算
+	scale := 1.0
+	if longest := maxInt(bounds.Dx(), bounds.Dy()); longest > smartCropWorkSize {
+		scale = float64(smartCropWorkSize) / float64(longest)
+	}
+	workWidth := maxInt(1, int(float64(bounds.Dx())*scale))
+	workHeight := maxInt(1, int(float64(bounds.Dy())*scale))
+	work := imaging.Grayscale(imaging.Resize(img, workWidth, workHeight, imaging.Box))
+
+	// 切り詰める方向（横または縦）ごとのエッジ量の合計
+	horizontal := width < bounds.Dx()
+	profile := edgeProfile(work, horizontal)
+
+	// 作業画像上での窓サイズをスライドしてエッジ量が最大の位置を探す
+	window := int(float64(height) * scale)
+	if horizontal {
+		window = int(float64(width) * scale)
+	}
+	window = maxInt(1, minInt(window, len(profile)))
+
+	best, bestOffset, sum := -1.0, 0, 0.0
+	for i := 0; i < len(profile); i++ {
+		sum += profile[i]
+		if i >= window {
+			sum -= profile[i-window]
+		}
+		if i >= window-1 && sum > best {
+			best = sum
+			bestOffset = i - window + 1
+		}
+	}
+
+	// 元画像の座標に戻して範囲内に収める
+	offset := int(float64(bestOffset) / scale)
+	if horizontal {
+		x := bounds.Min.X + minInt(offset, bounds.Dx()-width)
+		return image.Rect(x, bounds.Min.Y, x+width, bounds.Min.Y+height)
+	}
+	y := bounds.Min.Y + minInt(offset, bounds.Dy()-height)
+	return image.Rect(bounds.Min.X, y, bounds.Min.X+width, y+height)
+}
+
+// edgeProfile はグレースケール画像の各列（horizontal が false の場合は各行）のエッジ量を返します
+func edgeProfile(gray *image.NRGBA, horizontal bool) []float64 {
+	bounds := gray.Bounds()
+	length := bounds.Dy()
+	if horizontal {
+		length = bounds.Dx()
+	}
+	profile := make([]float64, length)
+
+	luminance := func(x, y int) int {
+		return int(gray.Pix[gray.PixOffset(x, y)])
+	}
+
+	for y := bounds.Min.Y + 1; y < bounds.Max.Y-1; y++ {
+		for x := bounds.Min.X + 1; x < bounds.Max.X-1; x++ {
+			// 隣接ピクセルとの輝度差の絶対値をエッジ量とする
+			gx := luminance(x+1, y) - luminance(x-1, y)
+			gy := luminance(x, y+1) - luminance(x, y-1)
+			magnitude := float64(absInt(gx) + absInt(gy))
+
+			if horizontal {
+				profile[x-bounds.Min.X] += magnitude
+			} else {
+				profile[y-bounds.Min.Y] += magnitude
+			}
+		}
+	}
+
+	return profile
+}
+
+// padTo は画像をターゲットサイズの背景の中央に配置します
+func padTo(img image.Image, width, height int, background color.Color) image.Image {
+	canvas := imaging.New(width, height, background)
+	return imaging.PasteCenter(canvas, img)
+}
+
+// parseBackground は #rrggbb 形式の背景色を色に変換します
+func parseBackground(value string) color.Color {
+	if len(value) != 7 {
+		return color.White
+	}
+	rgb, err := hex.DecodeString(value[1:])
+	if err != nil {
+		return color.White
+	}
+	return color.NRGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 0xff}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
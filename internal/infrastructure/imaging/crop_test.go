@@ -0,0 +1,213 @@
+package imaging
+
+import (
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/disintegration/imaging"
+)
+
+// cropTestSpec は切り取り方法と背景色を指定したテスト用のレンディション仕様を返します
+func cropTestSpec(t *testing.T, width, height int, crop, background string) valueobject.RenditionSpec {
+	t.Helper()
+	spec, err := valueobject.NewRenditionSpec("test", width, height, "", 0)
+	if err != nil {
+		t.Fatalf("NewRenditionSpec() error = %v", err)
+	}
+	spec, err = spec.WithCrop(crop, background)
+	if err != nil {
+		t.Fatalf("WithCrop() error = %v", err)
+	}
+	return spec
+}
+
+func TestCropSize(t *testing.T) {
+	tests := []struct {
+		name                      string
+		srcWidth, srcHeight       int
+		targetWidth, targetHeight int
+		wantWidth, wantHeight     int
+	}{
+		{name: "横長の画像を正方形に", srcWidth: 800, srcHeight: 600, targetWidth: 150, targetHeight: 150, wantWidth: 600, wantHeight: 600},
+		{name: "縦長の画像を正方形に", srcWidth: 600, srcHeight: 800, targetWidth: 150, targetHeight: 150, wantWidth: 600, wantHeight: 600},
+		{name: "同じ縦横比は切り詰めない", srcWidth: 1600, srcHeight: 900, targetWidth: 160, targetHeight: 90, wantWidth: 1600, wantHeight: 900},
+		{name: "ターゲットより小さい画像", srcWidth: 100, srcHeight: 50, targetWidth: 600, targetHeight: 600, wantWidth: 50, wantHeight: 50},
+		{name: "極端に横長の画像", srcWidth: 10000, srcHeight: 10, targetWidth: 150, targetHeight: 150, wantWidth: 10, wantHeight: 10},
+		{name: "極端に縦長の画像", srcWidth: 10, srcHeight: 10000, targetWidth: 1200, targetHeight: 400, wantWidth: 10, wantHeight: 3},
+		{name: "切り詰めた辺は1ピクセル以上", srcWidth: 1, srcHeight: 100, targetWidth: 300, targetHeight: 100, wantWidth: 1, wantHeight: 1},
+		{name: "極端に横長のターゲット", srcWidth: 1, srcHeight: 1, targetWidth: 1000, targetHeight: 1, wantWidth: 1, wantHeight: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := cropSize(tt.srcWidth, tt.srcHeight, tt.targetWidth, tt.targetHeight)
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("cropSize() = %dx%d, want %dx%d", width, height, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestCropRegion(t *testing.T) {
+	striped := stripedImage(400, 100, image.Rect(300, 0, 400, 100))
+	// 原点が(0,0)でない画像
+	offset := imaging.New(400, 200, color.White).SubImage(image.Rect(100, 50, 300, 150))
+
+	tests := []struct {
+		name string
+		img  image.Image
+		spec valueobject.RenditionSpec
+		want image.Rectangle
+	}{
+		{name: "fitは画像全体", img: striped, spec: cropTestSpec(t, 100, 100, "fit", ""), want: image.Rect(0, 0, 400, 100)},
+		{name: "padは画像全体", img: striped, spec: cropTestSpec(t, 100, 100, "pad", ""), want: image.Rect(0, 0, 400, 100)},
+		{name: "fillは中央", img: striped, spec: cropTestSpec(t, 100, 100, "fill", ""), want: image.Rect(150, 0, 250, 100)},
+		{name: "fillは原点からずれた画像でも中央", img: offset, spec: cropTestSpec(t, 100, 100, "fill", ""), want: image.Rect(150, 50, 250, 150)},
+		{name: "smartは切り取り不要なら画像全体", img: striped, spec: cropTestSpec(t, 200, 50, "smart", ""), want: image.Rect(0, 0, 400, 100)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cropRegion(tt.img, tt.spec); got != tt.want {
+				t.Errorf("cropRegion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// stripedImage は白い画像の指定した領域にだけ縞模様を描いた画像を返します
+func stripedImage(width, height int, detail image.Rectangle) *image.NRGBA {
+	img := imaging.New(width, height, color.White)
+	for y := detail.Min.Y; y < detail.Max.Y; y++ {
+		for x := detail.Min.X; x < detail.Max.X; x++ {
+			if (x+y)%2 == 0 {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+	return img
+}
+
+func TestSmartRegion(t *testing.T) {
+	tests := []struct {
+		name         string
+		width        int
+		height       int
+		detail       image.Rectangle
+		targetWidth  int
+		targetHeight int
+		// 切り取った領域が縞模様の大部分を含むこと（画像端のエッジは計算しないため数ピクセルの誤差を許容）
+		wantMin image.Point
+		wantMax image.Point
+	}{
+		{
+			name: "横長の画像の右側", width: 400, height: 100, detail: image.Rect(300, 0, 400, 100),
+			targetWidth: 100, targetHeight: 100, wantMin: image.Pt(290, 0), wantMax: image.Pt(300, 0),
+		},
+		{
+			name: "横長の画像の左側", width: 400, height: 100, detail: image.Rect(0, 0, 100, 100),
+			targetWidth: 100, targetHeight: 100, wantMin: image.Pt(0, 0), wantMax: image.Pt(10, 0),
+		},
+		{
+			name: "縦長の画像の下側", width: 100, height: 400, detail: image.Rect(0, 300, 100, 400),
+			targetWidth: 100, targetHeight: 100, wantMin: image.Pt(0, 290), wantMax: image.Pt(0, 300),
+		},
+		{
+			name: "作業画像より大きい画像", width: 2000, height: 500, detail: image.Rect(1000, 0, 1500, 500),
+			targetWidth: 200, targetHeight: 200, wantMin: image.Pt(980, 0), wantMax: image.Pt(1020, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := smartRegion(stripedImage(tt.width, tt.height, tt.detail), tt.targetWidth, tt.targetHeight)
+			width, height := cropSize(tt.width, tt.height, tt.targetWidth, tt.targetHeight)
+			if got.Dx() != width || got.Dy() != height {
+				t.Errorf("smartRegion() size = %dx%d, want %dx%d", got.Dx(), got.Dy(), width, height)
+			}
+			if got.Min.X < tt.wantMin.X || got.Min.X > tt.wantMax.X || got.Min.Y < tt.wantMin.Y || got.Min.Y > tt.wantMax.Y {
+				t.Errorf("smartRegion() = %v, want origin between %v and %v", got, tt.wantMin, tt.wantMax)
+			}
+			if !got.In(image.Rect(0, 0, tt.width, tt.height)) {
+				t.Errorf("smartRegion() = %v, outside of the image", got)
+			}
+		})
+	}
+}
+
+func TestApplyCrop(t *testing.T) {
+	tests := []struct {
+		name                string
+		srcWidth, srcHeight int
+		spec                valueobject.RenditionSpec
+		wantWidth           int
+		wantHeight          int
+	}{
+		{name: "fitは縦横比を維持して縮小", srcWidth: 800, srcHeight: 600, spec: cropTestSpec(t, 200, 200, "fit", ""), wantWidth: 200, wantHeight: 150},
+		{name: "fitは幅のみの指定", srcWidth: 800, srcHeight: 600, spec: cropTestSpec(t, 300, 0, "fit", ""), wantWidth: 300, wantHeight: 225},
+		{name: "fitは小さい画像を拡大しない", srcWidth: 100, srcHeight: 50, spec: cropTestSpec(t, 200, 200, "fit", ""), wantWidth: 100, wantHeight: 50},
+		{name: "fillはターゲットの大きさ", srcWidth: 800, srcHeight: 600, spec: cropTestSpec(t, 200, 200, "fill", ""), wantWidth: 200, wantHeight: 200},
+		{name: "fillは小さい画像を拡大しない", srcWidth: 100, srcHeight: 50, spec: cropTestSpec(t, 200, 200, "fill", ""), wantWidth: 50, wantHeight: 50},
+		{name: "fillは片方の辺だけ小さい画像を拡大しない", srcWidth: 1000, srcHeight: 150, spec: cropTestSpec(t, 300, 200, "fill", ""), wantWidth: 225, wantHeight: 150},
+		{name: "fillは極端に横長の画像", srcWidth: 4000, srcHeight: 10, spec: cropTestSpec(t, 150, 150, "fill", ""), wantWidth: 10, wantHeight: 10},
+		{name: "smartはターゲットの大きさ", srcWidth: 800, srcHeight: 600, spec: cropTestSpec(t, 200, 200, "smart", ""), wantWidth: 200, wantHeight: 200},
+		{name: "smartは小さい画像を拡大しない", srcWidth: 100, srcHeight: 50, spec: cropTestSpec(t, 200, 200, "smart", ""), wantWidth: 50, wantHeight: 50},
+		{name: "smartは極端に縦長の画像", srcWidth: 10, srcHeight: 4000, spec: cropTestSpec(t, 1200, 400, "smart", ""), wantWidth: 10, wantHeight: 3},
+		{name: "padはターゲットの大きさ", srcWidth: 800, srcHeight: 400, spec: cropTestSpec(t, 200, 200, "pad", ""), wantWidth: 200, wantHeight: 200},
+		{name: "padは小さい画像でもターゲットの大きさ", srcWidth: 100, srcHeight: 50, spec: cropTestSpec(t, 200, 200, "pad", ""), wantWidth: 200, wantHeight: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := imaging.New(tt.srcWidth, tt.srcHeight, color.NRGBA{R: 0x33, G: 0x66, B: 0x99, A: 0xff})
+			got := applyCrop(img, cropRegion(img, tt.spec), tt.spec).Bounds()
+			if got.Dx() != tt.wantWidth || got.Dy() != tt.wantHeight {
+				t.Errorf("applyCrop() = %dx%d, want %dx%d", got.Dx(), got.Dy(), tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestApplyCropPadBackground(t *testing.T) {
+	source := color.NRGBA{R: 0x33, G: 0x66, B: 0x99, A: 0xff}
+	img := imaging.New(100, 50, source)
+	spec := cropTestSpec(t, 200, 200, "pad", "#ff0000")
+
+	got := imaging.Clone(applyCrop(img, cropRegion(img, spec), spec))
+	if c := got.NRGBAAt(0, 0); c != (color.NRGBA{R: 0xff, A: 0xff}) {
+		t.Errorf("corner = %v, want background #ff0000", c)
+	}
+	// 小さい画像は拡大せず中央に配置される
+	if c := got.NRGBAAt(100, 100); c != source {
+		t.Errorf("center = %v, want source color %v", c, source)
+	}
+	if c := got.NRGBAAt(100, 70); c != (color.NRGBA{R: 0xff, A: 0xff}) {
+		t.Errorf("above the source = %v, want background #ff0000", c)
+	}
+}
+
+func TestParseBackground(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  color.Color
+	}{
+		{name: "小文字", value: "#ff8000", want: color.NRGBA{R: 0xff, G: 0x80, A: 0xff}},
+		{name: "大文字", value: "#00FF7F", want: color.NRGBA{G: 0xff, B: 0x7f, A: 0xff}},
+		{name: "黒", value: "#000000", want: color.NRGBA{A: 0xff}},
+		{name: "空は白", value: "", want: color.White},
+		{name: "短すぎる値は白", value: "#fff", want: color.White},
+		{name: "#のない値は白", value: "ff8000", want: color.White},
+		{name: "16進数でない値は白", value: "#gg0000", want: color.White},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseBackground(tt.value); got != tt.want {
+				t.Errorf("parseBackground(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
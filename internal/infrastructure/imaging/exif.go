@@ -0,0 +1,99 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+// EXIFのフィールドの読み取りで使用するタグと型
+const (
+	exifTagExifIFDPointer = 0x8769
+	exifTypeASCII         = 2
+	exifTypeLong          = 4
+)
+
+// IFD0から読み取る文字列のフィールド
+var exifIFD0Fields = map[uint16]string{
+	0x010F: "Make",
+	0x0110: "Model",
+	0x0131: "Software",
+	0x013B: "Artist",
+}
+
+// Exif IFDから読み取る文字列のフィールド
+var exifSubIFDFields = map[uint16]string{
+	0x9003: "DateTimeOriginal",
+	0xA433: "LensMake",
+	0xA434: "LensModel",
+}
+
+// readEXIFFields はJPEGデータのEXIFから撮影機材などの文字列のフィールドを読み取ります
+// EXIFが存在しない、または解析できない場合は空のマップを返します
+func readEXIFFields(data []byte) map[string]string {
+	fields := make(map[string]string)
+
+	tiff := findEXIF(data)
+	if tiff == nil {
+		return fields
+	}
+	order, ok := tiffByteOrder(tiff)
+	if !ok {
+		return fields
+	}
+
+	// IFD0の後にExif IFDを読む（IFD0を指し返す不正なポインタは無視）
+	ifd0Offset := int(order.Uint32(tiff[4:8]))
+	exifIFDOffset := readIFDStrings(tiff, order, ifd0Offset, exifIFD0Fields, fields)
+	if exifIFDOffset > 0 && exifIFDOffset != ifd0Offset {
+		readIFDStrings(tiff, order, exifIFDOffset, exifSubIFDFields, fields)
+	}
+	return fields
+}
+
+// readIFDStrings はIFDのエントリから対象の文字列のフィールドを読み取り、Exif IFDの位置（ない場合は0）を返します
+func readIFDStrings(tiff []byte, order binary.ByteOrder, ifdOffset int, targets map[uint16]string, fields map[string]string) int {
+	if ifdOffset < 8 || ifdOffset+2 > len(tiff) {
+		return 0
+	}
+	entryCount := int(order.Uint16(tiff[ifdOffset : ifdOffset+2]))
+
+	exifIFDOffset := 0
+	for i := 0; i < entryCount; i++ {
+		entry := ifdOffset + 2 + i*exifIFDEntrySize
+		if entry+exifIFDEntrySize > len(tiff) {
+			break
+		}
+
+		tag := order.Uint16(tiff[entry : entry+2])
+		valueType := order.Uint16(tiff[entry+2 : entry+4])
+		count := int(order.Uint32(tiff[entry+4 : entry+8]))
+
+		if tag == exifTagExifIFDPointer && valueType == exifTypeLong {
+			exifIFDOffset = int(order.Uint32(tiff[entry+8 : entry+12]))
+			continue
+		}
+
+		name, ok := targets[tag]
+		if !ok || valueType != exifTypeASCII || count <= 0 {
+			continue
+		}
+
+		// 4バイト以下の値はエントリ内、それより長い値はオフセットの位置に格納されている
+		var raw []byte
+		if count <= 4 {
+			raw = tiff[entry+8 : entry+8+count]
+		} else {
+			offset := int(order.Uint32(tiff[entry+8 : entry+12]))
+			if offset < 0 || count > len(tiff) || offset > len(tiff)-count {
+				continue
+			}
+			raw = tiff[offset : offset+count]
+		}
+
+		value := strings.TrimSpace(strings.TrimRight(string(raw), "\x00"))
+		if value != "" {
+			fields[name] = value
+		}
+	}
+	return exifIFDOffset
+}
@@ -0,0 +1,224 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// exifTestEntry はテスト用のTIFFを組み立てるIFDのエントリ
+// ifd が0以上の場合は、その番号のIFDを指すExif IFDポインタとして書き込みます
+type exifTestEntry struct {
+	tag   uint16
+	value string
+	count uint32 // 0の場合は value の長さ
+	ifd   int
+}
+
+// asciiEntry はNUL終端の文字列のエントリを返します
+func asciiEntry(tag uint16, value string) exifTestEntry {
+	return exifTestEntry{tag: tag, value: value + "\x00", ifd: -1}
+}
+
+// buildTIFF はIFDを先頭から順に配置し、4バイトを超える値をIFDの後ろに配置したTIFFを返します
+func buildTIFF(order binary.ByteOrder, ifds ...[]exifTestEntry) []byte {
+	// 各IFDの位置を決める（エントリ数2バイト・エントリ12バイト・次のIFDの位置4バイト）
+	offsets := make([]int, len(ifds))
+	end := 8
+	for i, entries := range ifds {
+		offsets[i] = end
+		end += 2 + len(entries)*exifIFDEntrySize + 4
+	}
+
+	tiff := make([]byte, end)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:4], 42)
+	order.PutUint32(tiff[4:8], uint32(offsets[0]))
+
+	for i, entries := range ifds {
+		order.PutUint16(tiff[offsets[i]:], uint16(len(entries)))
+		for j, e := range entries {
+			entry := offsets[i] + 2 + j*exifIFDEntrySize
+			order.PutUint16(tiff[entry:], e.tag)
+
+			if e.ifd >= 0 {
+				order.PutUint16(tiff[entry+2:], exifTypeLong)
+				order.PutUint32(tiff[entry+4:], 1)
+				order.PutUint32(tiff[entry+8:], uint32(offsets[e.ifd]))
+				continue
+			}
+
+			count := e.count
+			if count == 0 {
+				count = uint32(len(e.value))
+			}
+			order.PutUint16(tiff[entry+2:], exifTypeASCII)
+			order.PutUint32(tiff[entry+4:], count)
+			if len(e.value) <= 4 {
+				copy(tiff[entry+8:entry+12], e.value)
+				continue
+			}
+			order.PutUint32(tiff[entry+8:], uint32(len(tiff)))
+			tiff = append(tiff, e.value...)
+		}
+	}
+	return tiff
+}
+
+// jpegWithEXIF はTIFFをAPP1セグメントのEXIFとして含むJPEGの先頭部分を返します
+func jpegWithEXIF(tiff []byte) []byte {
+	segment := append([]byte(exifHeaderSignature), tiff...)
+	data := []byte{0xFF, jpegMarkerSOI, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(data[4:6], uint16(len(segment)+2))
+	data = append(data, segment...)
+	return append(data, 0xFF, jpegMarkerSOS, 0, 2)
+}
+
+func TestReadEXIFFields(t *testing.T) {
+	// IFD0にMake・Model、Exif IFDにLensModelを持つTIFF
+	camera := func(order binary.ByteOrder) []byte {
+		return buildTIFF(order,
+			[]exifTestEntry{
+				asciiEntry(0x010F, "Canon"),
+				asciiEntry(0x0110, "EOS R5"),
+				{tag: exifTagExifIFDPointer, ifd: 1},
+			},
+			[]exifTestEntry{
+				asciiEntry(0xA434, "RF24-70mm F2.8 L IS USM"),
+			},
+		)
+	}
+	want := map[string]string{
+		"Make":      "Canon",
+		"Model":     "EOS R5",
+		"LensModel": "RF24-70mm F2.8 L IS USM",
+	}
+
+	tests := []struct {
+		name string
+		tiff []byte
+		want map[string]string
+	}{
+		{
+			name: "リトルエンディアン",
+			tiff: camera(binary.LittleEndian),
+			want: want,
+		},
+		{
+			name: "ビッグエンディアン",
+			tiff: camera(binary.BigEndian),
+			want: want,
+		},
+		{
+			name: "4バイト以下の値はエントリ内から読む",
+			tiff: buildTIFF(binary.LittleEndian, []exifTestEntry{
+				asciiEntry(0x010F, "JVC"),
+				{tag: 0x0110, value: "A7IV", ifd: -1},
+				asciiEntry(0x0131, "X"),
+			}),
+			want: map[string]string{"Make": "JVC", "Model": "A7IV", "Software": "X"},
+		},
+		{
+			name: "途中で切れたIFDは読めたエントリまで",
+			tiff: camera(binary.BigEndian)[:8+2+exifIFDEntrySize+6],
+			want: map[string]string{},
+		},
+		{
+			name: "エントリ数がデータより多いIFDは読めたエントリまで",
+			tiff: func() []byte {
+				tiff := buildTIFF(binary.LittleEndian, []exifTestEntry{
+					asciiEntry(0x0131, "Pix"),
+				})
+				binary.LittleEndian.PutUint16(tiff[8:10], 500)
+				return tiff
+			}(),
+			want: map[string]string{"Software": "Pix"},
+		},
+		{
+			name: "末尾を超える値のオフセットは無視",
+			tiff: func() []byte {
+				tiff := camera(binary.LittleEndian)
+				// Make の値のオフセットを末尾の先に書き換える
+				binary.LittleEndian.PutUint32(tiff[8+2+8:], uint32(len(tiff)+10))
+				return tiff
+			}(),
+			want: map[string]string{"Model": "EOS R5", "LensModel": "RF24-70mm F2.8 L IS USM"},
+		},
+		{
+			name: "末尾を超える値の長さは無視",
+			tiff: buildTIFF(binary.BigEndian, []exifTestEntry{
+				{tag: 0x013B, value: "Photographer", count: 0xFFFFFFF0, ifd: -1},
+				asciiEntry(0x010F, "Nikon"),
+			}),
+			want: map[string]string{"Make": "Nikon"},
+		},
+		{
+			name: "末尾を超えるExif IFDポインタは無視",
+			tiff: func() []byte {
+				tiff := camera(binary.BigEndian)
+				binary.BigEndian.PutUint32(tiff[8+2+2*exifIFDEntrySize+8:], uint32(len(tiff)))
+				return tiff
+			}(),
+			want: map[string]string{"Make": "Canon", "Model": "EOS R5"},
+		},
+		{
+			name: "IFD0を指し返すExif IFDポインタは無視",
+			tiff: buildTIFF(binary.LittleEndian, []exifTestEntry{
+				asciiEntry(0x010F, "Canon"),
+				{tag: exifTagExifIFDPointer, ifd: 0},
+			}),
+			want: map[string]string{"Make": "Canon"},
+		},
+		{
+			name: "自身を指し返すExif IFDは一度だけ読む",
+			tiff: buildTIFF(binary.LittleEndian,
+				[]exifTestEntry{
+					{tag: exifTagExifIFDPointer, ifd: 1},
+				},
+				[]exifTestEntry{
+					asciiEntry(0x9003, "2024:05:01 10:00:00"),
+					{tag: exifTagExifIFDPointer, ifd: 1},
+				},
+			),
+			want: map[string]string{"DateTimeOriginal": "2024:05:01 10:00:00"},
+		},
+		{
+			name: "バイトオーダーが不正なTIFF",
+			tiff: append([]byte("XX"), camera(binary.LittleEndian)[2:]...),
+			want: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := readEXIFFields(jpegWithEXIF(tt.tiff))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readEXIFFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadEXIFFieldsWithoutEXIF(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "空のデータ", data: nil},
+		{name: "JPEGでないデータ", data: []byte("GIF89a")},
+		{name: "EXIFのないJPEG", data: []byte{0xFF, jpegMarkerSOI, 0xFF, jpegMarkerSOS, 0, 2}},
+		{name: "TIFFヘッダーより短いEXIF", data: jpegWithEXIF([]byte("II*"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readEXIFFields(tt.data); len(got) != 0 {
+				t.Errorf("readEXIFFields() = %v, want empty", got)
+			}
+		})
+	}
+}
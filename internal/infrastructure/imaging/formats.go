@@ -0,0 +1,184 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+	"golang.org/x/image/webp"
+)
+
+// FormatError はサポートされていない画像形式を表すエラー
+type FormatError struct {
+	ContentType string
+	Operation   string
+}
+
+// Error はエラーメッセージを返します
+func (e *FormatError) Error() string {
+	return fmt.Sprintf("unsupported image format for %s: %s", e.Operation, e.ContentType)
+}
+
+// 形式エラーの操作種別
+const (
+	formatOperationDecode = "decode"
+	formatOperationEncode = "encode"
+)
+
+// imageFormat は画像形式ごとのデコーダーとエンコーダーを表します
+// encode が nil の形式はデコードのみ対応します
+type imageFormat struct {
+	contentType  string
+	decode       func(r io.Reader) (image.Image, error)
+	decodeConfig func(r io.Reader) (image.Config, error)
+	encode       func(w io.Writer, img image.Image, quality int) error
+}
+
+// FormatRegistry はコンテンツタイプと画像形式の対応を管理します
+type FormatRegistry struct {
+	formats map[string]*imageFormat
+}
+
+// NewFormatRegistry は標準の画像形式を登録したレジストリを作成します
+func NewFormatRegistry() *FormatRegistry {
+	registry := &FormatRegistry{
+		formats: make(map[string]*imageFormat),
+	}
+
+	jpegFormat := &imageFormat{
+		contentType:  "image/jpeg",
+		decode:       jpeg.Decode,
+		decodeConfig: jpeg.DecodeConfig,
+		encode: func(w io.Writer, img image.Image, quality int) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+		},
+	}
+	registry.Register(jpegFormat, "image/jpeg", "image/jpg", "image/pjpeg")
+
+	registry.Register(&imageFormat{
+		contentType:  "image/png",
+		decode:       png.Decode,
+		decodeConfig: png.DecodeConfig,
+		encode: func(w io.Writer, img image.Image, _ int) error {
+			return png.Encode(w, img)
+		},
+	}, "image/png")
+
+	// アニメーションGIFの場合は先頭フレームのみを扱う（全フレームは gif.go で処理）
+	registry.Register(&imageFormat{
+		contentType:  "image/gif",
+		decode:       gif.Decode,
+		decodeConfig: gif.DecodeConfig,
+		encode: func(w io.Writer, img image.Image, _ int) error {
+			// 誤差拡散でパレット化して色の段差を抑える
+			return gif.Encode(w, img, &gif.Options{NumColors: 256, Drawer: draw.FloydSteinberg})
+		},
+	}, "image/gif")
+
+	// 以下はデコードのみ対応
+	registry.Register(&imageFormat{
+		contentType:  "image/webp",
+		decode:       webp.Decode,
+		decodeConfig: webp.DecodeConfig,
+	}, "image/webp")
+
+	registry.Register(&imageFormat{
+		contentType:  "image/bmp",
+		decode:       bmp.Decode,
+		decodeConfig: bmp.DecodeConfig,
+	}, "image/bmp", "image/x-bmp", "image/x-ms-bmp")
+
+	registry.Register(&imageFormat{
+		contentType:  "image/tiff",
+		decode:       tiff.Decode,
+		decodeConfig: tiff.DecodeConfig,
+	}, "image/tiff", "image/tif")
+
+	return registry
+}
+
+// Register は画像形式を指定されたコンテンツタイプで登録します
+func (r *FormatRegistry) Register(format *imageFormat, contentTypes ...string) {
+	for _, contentType := range contentTypes {
+		r.formats[normalizeContentType(contentType)] = format
+	}
+}
+
+// lookup はコンテンツタイプに対応する画像形式を取得します
+func (r *FormatRegistry) lookup(contentType string) (*imageFormat, bool) {
+	format, ok := r.formats[normalizeContentType(contentType)]
+	return format, ok
+}
+
+// CanDecode は指定されたコンテンツタイプをデコードできるかを判定します
+func (r *FormatRegistry) CanDecode(contentType string) bool {
+	_, ok := r.lookup(contentType)
+	return ok
+}
+
+// CanEncode は指定されたコンテンツタイプでエンコードできるかを判定します
+func (r *FormatRegistry) CanEncode(contentType string) bool {
+	format, ok := r.lookup(contentType)
+	return ok && format.encode != nil
+}
+
+// Decode は画像データを指定されたコンテンツタイプとしてデコードします
+func (r *FormatRegistry) Decode(contentType string, data []byte) (image.Image, error) {
+	format, ok := r.lookup(contentType)
+	if !ok {
+		return nil, &FormatError{ContentType: contentType, Operation: formatOperationDecode}
+	}
+
+	img, err := format.decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// DecodeConfig は画像全体をデコードせずにヘッダーから色モデルと大きさを読み取ります
+func (r *FormatRegistry) DecodeConfig(contentType string, data []byte) (image.Config, error) {
+	format, ok := r.lookup(contentType)
+	if !ok {
+		return image.Config{}, &FormatError{ContentType: contentType, Operation: formatOperationDecode}
+	}
+
+	config, err := format.decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return image.Config{}, fmt.Errorf("failed to decode image header: %w", err)
+	}
+	return config, nil
+}
+
+// Encode は画像を指定されたコンテンツタイプでエンコードします
+func (r *FormatRegistry) Encode(w io.Writer, img image.Image, contentType string, quality int) error {
+	format, ok := r.lookup(contentType)
+	if !ok || format.encode == nil {
+		return &FormatError{ContentType: contentType, Operation: formatOperationEncode}
+	}
+	return format.encode(w, img, quality)
+}
+
+// CanonicalContentType は別名を含むコンテンツタイプを正規のコンテンツタイプに変換します
+func (r *FormatRegistry) CanonicalContentType(contentType string) string {
+	if format, ok := r.lookup(contentType); ok {
+		return format.contentType
+	}
+	return contentType
+}
+
+// normalizeContentType はパラメータを除去して小文字に正規化します
+func normalizeContentType(contentType string) string {
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
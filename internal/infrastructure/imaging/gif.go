@@ -0,0 +1,160 @@
+package imaging
+
+import (
+	"bytes"
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+)
+
+// isGIF はコンテンツタイプがGIFかどうかを判定します
+func isGIF(contentType string) bool {
+	return normalizeContentType(contentType) == "image/gif"
+}
+
+// countGIFFrames はフレームをデコードせずにGIFのブロック構造をたどり、イメージディスクリプタの数を数えます
+// データが途中で切れている場合はそこまでに見つかった数を返します（不正なデータはデコード時にエラーになります）
+func countGIFFrames(data []byte) int {
+	// ヘッダー（6バイト）と論理スクリーンディスクリプタ（7バイト）
+	const headerSize = 13
+	if len(data) < headerSize {
+		return 0
+	}
+	offset := headerSize
+	if flags := data[10]; flags&0x80 != 0 {
+		offset += gifColorTableSize(flags)
+	}
+
+	frames := 0
+	for offset < len(data) {
+		switch data[offset] {
+		case 0x21: // 拡張ブロック（識別子の後にサブブロックが続く）
+			offset = skipGIFSubBlocks(data, offset+2)
+		case 0x2c: // イメージディスクリプタ
+			frames++
+			if offset+10 > len(data) {
+				return frames
+			}
+			flags := data[offset+9]
+			offset += 10
+			if flags&0x80 != 0 {
+				offset += gifColorTableSize(flags)
+			}
+			// LZWの最小コードサイズの後に画像データのサブブロックが続く
+			offset = skipGIFSubBlocks(data, offset+1)
+		default: // トレーラーまたは不正なブロック
+			return frames
+		}
+	}
+	return frames
+}
+
+// gifColorTableSize はフラグで宣言されたカラーテーブルのバイト数を返します
+func gifColorTableSize(flags byte) int {
+	return 3 << ((flags & 0x07) + 1)
+}
+
+// skipGIFSubBlocks は終端の空ブロックまでのサブブロックを読み飛ばし、次のブロックの位置を返します
+func skipGIFSubBlocks(data []byte, offset int) int {
+	for offset < len(data) {
+		size := int(data[offset])
+		offset++
+		if size == 0 {
+			return offset
+		}
+		offset += size
+	}
+	return len(data)
+}
+
+// resizeAnimation はフレームの遅延とループ回数を維持したままアニメーションGIFをリサイズします
+// 切り取り領域は先頭フレームで決定し、全フレームに同じ領域を適用して返します
+// 透かしが設定されている場合は先頭フレームの大きさに合わせて一度だけ用意し、各フレームに重ねます
+func resizeAnimation(animation *gif.GIF, spec valueobject.RenditionSpec, mark image.Image) (*gif.GIF, image.Rectangle, error) {
+	// 論理スクリーンのサイズ
+	canvasWidth := animation.Config.Width
+	canvasHeight := animation.Config.Height
+	if canvasWidth == 0 || canvasHeight == 0 {
+		bounds := animation.Image[0].Bounds()
+		canvasWidth, canvasHeight = bounds.Max.X, bounds.Max.Y
+	}
+
+	// フレームは差分で格納されているため、キャンバスに合成してからリサイズする
+	canvas := image.NewRGBA(image.Rect(0, 0, canvasWidth, canvasHeight))
+	frames := make([]*image.Paletted, 0, len(animation.Image))
+	disposals := make([]byte, 0, len(animation.Image))
+	var region image.Rectangle
+	var scaledMark image.Image
+
+	for i, frame := range animation.Image {
+		// 前フレームの状態を保持（DisposalPrevious 用）
+		var previous *image.RGBA
+		disposal := byte(0)
+		if i < len(animation.Disposal) {
+			disposal = animation.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(canvas.Bounds())
+			draw.Draw(previous, previous.Bounds(), canvas, image.Point{}, draw.Src)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		// 合成したフレームをリサイズしてパレット化
+		if i == 0 {
+			region = cropRegion(canvas, spec)
+		}
+		resized := applyCrop(canvas, region, spec)
+		if watermark, ok := spec.Watermark(); ok {
+			if i == 0 {
+				var err error
+				scaledMark, err = scaleWatermark(resized.Bounds(), watermark, mark)
+				if err != nil {
+					return nil, image.Rectangle{}, err
+				}
+			}
+			resized = overlayWatermark(resized, watermark, scaledMark)
+		}
+		framePalette := frame.Palette
+		if len(framePalette) == 0 {
+			framePalette = palette.Plan9
+		}
+		paletted := image.NewPaletted(resized.Bounds(), framePalette)
+		draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), resized, resized.Bounds().Min)
+		frames = append(frames, paletted)
+		disposals = append(disposals, gif.DisposalNone)
+
+		// 次フレームのために廃棄処理を適用
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	bounds := frames[0].Bounds()
+	resizedAnimation := &gif.GIF{
+		Image:     frames,
+		Delay:     animation.Delay,
+		LoopCount: animation.LoopCount,
+		Disposal:  disposals,
+		Config: image.Config{
+			Width:  bounds.Dx(),
+			Height: bounds.Dy(),
+		},
+	}
+	return resizedAnimation, region, nil
+}
+
+// encodeAnimation はアニメーションGIFをエンコードします
+func encodeAnimation(animation *gif.GIF) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, animation); err != nil {
+		return nil, fmt.Errorf("failed to encode animated gif: %w", err)
+	}
+	return buf.Bytes(), nil
+}
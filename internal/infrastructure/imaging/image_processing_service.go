@@ -2,125 +2,342 @@ package imaging
 
 import (
 	"bytes"
+	imagevalueobject "cloudpix/internal/domain/imagemanagement/valueobject"
 	"cloudpix/internal/domain/thumbnailmanagement/service"
 	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
 	"errors"
 	"fmt"
 	"image"
-	"image/jpeg"
-	"image/png"
+	"image/gif"
+	"io"
 	"path/filepath"
 	"strings"
 
 	"github.com/disintegration/imaging"
 )
 
+// サムネイルのデフォルトJPEG品質
+const defaultJPEGQuality = 85
+
+// エンコードに対応していない形式の出力に使用するコンテンツタイプ
+const fallbackContentType = "image/jpeg"
+
 // ImageProcessingServiceImpl は画像処理サービスの実装
 type ImageProcessingServiceImpl struct {
-	supportedFormats map[string]bool
+	formats *FormatRegistry
+	limits  imagevalueobject.ImageLimits
 }
 
 // NewImageProcessingService は新しい画像処理サービスを作成します
-func NewImageProcessingService() service.ImageProcessingService {
+// 上限を超える画像はデコードせずに imagevalueobject.ErrImageTooLarge で拒否します
+func NewImageProcessingService(limits imagevalueobject.ImageLimits) service.ImageProcessingService {
 	return &ImageProcessingServiceImpl{
-		supportedFormats: map[string]bool{
-			"image/jpeg": true,
-			"image/jpg":  true,
-			"image/png":  true,
-			"image/gif":  true,
-		},
+		formats: NewFormatRegistry(),
+		limits:  limits,
 	}
 }
 
-// DecodeImage は画像をデコードして幅と高さを取得します
-func (s *ImageProcessingServiceImpl) DecodeImage(data valueobject.ImageData) (int, int, error) {
-	// コンテンツタイプをチェック
-	if !s.IsSupported(data.ContentType) {
-		return 0, 0, fmt.Errorf("unsupported image format: %s", data.ContentType)
+// sourceImage はデコード済みの元画像の実装
+// animation はアニメーションGIFの全フレーム（保持しない場合やフレームが1枚以下の場合は nil）
+type sourceImage struct {
+	img         image.Image
+	animation   *gif.GIF
+	contentType string
+	exif        map[string]string
+}
+
+// Dimensions はEXIFの向きを考慮した表示上の幅と高さを返します
+func (s *sourceImage) Dimensions() valueobject.Dimensions {
+	bounds := s.img.Bounds()
+	// デコードに成功した画像は正の大きさを持つため、エラーは発生しない
+	dimensions, _ := valueobject.NewDimensions(bounds.Dx(), bounds.Dy())
+	return dimensions
+}
+
+// ContentType は元画像のコンテンツタイプを返します
+func (s *sourceImage) ContentType() string {
+	return s.contentType
+}
+
+// EXIF は元画像のEXIFから読み取った撮影機材などのフィールドを返します
+func (s *sourceImage) EXIF() map[string]string {
+	return s.exif
+}
+
+// Decode は元画像をストリームから一度だけデコードします
+// ヘッダーで画素数の上限を確認してから本体をデコードし、バイト数の上限は読み込みながら確認します
+func (s *ImageProcessingServiceImpl) Decode(r io.Reader, contentType string, keepAnimation bool) (service.SourceImage, error) {
+	format, ok := s.formats.lookup(contentType)
+	if !ok {
+		return nil, &FormatError{ContentType: contentType, Operation: formatOperationDecode}
+	}
+
+	source := newLimitedReader(r, s.limits.MaxBytes())
+
+	// ヘッダーを読み取った部分を保持しておき、本体のデコードで再利用する
+	var header bytes.Buffer
+	config, err := format.decodeConfig(io.TeeReader(source, &header))
+	if err != nil {
+		return nil, source.wrap(fmt.Errorf("failed to decode image header: %w", err), s.limits)
+	}
+	if err := s.limits.CheckDimensions(config.Width, config.Height); err != nil {
+		return nil, err
 	}
 
-	var img image.Image
-	var err error
+	// EXIFはJPEGのフレームヘッダーより前にあるため、読み取り済みのヘッダーから向きとフィールドを取得できる
+	orientation := readOrientation(header.Bytes())
+	body := io.MultiReader(bytes.NewReader(header.Bytes()), source)
 
-	// フォーマットに応じたデコード
-	reader := bytes.NewReader(data.Data)
-	if strings.Contains(data.ContentType, "jpeg") || strings.Contains(data.ContentType, "jpg") {
-		img, err = jpeg.Decode(reader)
-	} else if strings.Contains(data.ContentType, "png") {
-		img, err = png.Decode(reader)
+	decoded := &sourceImage{contentType: contentType, exif: readEXIFFields(header.Bytes())}
+	if keepAnimation && isGIF(contentType) {
+		// 全フレームを確保する前にフレーム数を数え、展開後の画素数が上限を超えるものは拒否
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, source.wrap(fmt.Errorf("failed to read animated gif: %w", err), s.limits)
+		}
+		if err := source.check(s.limits); err != nil {
+			return nil, err
+		}
+		if err := s.limits.CheckFrames(config.Width, config.Height, countGIFFrames(data)); err != nil {
+			return nil, err
+		}
+
+		animation, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, source.wrap(fmt.Errorf("failed to decode animated gif: %w", err), s.limits)
+		}
+		// フレームが1枚以下の場合はアニメーションとして扱わない
+		if len(animation.Image) > 1 {
+			decoded.animation = animation
+		}
+		decoded.img = animation.Image[0]
 	} else {
-		// サポートされている他のフォーマットはここで対応
-		return 0, 0, fmt.Errorf("format not implemented: %s", data.ContentType)
+		decoded.img, err = format.decode(body)
+		if err != nil {
+			return nil, source.wrap(fmt.Errorf("failed to decode image: %w", err), s.limits)
+		}
+	}
+
+	// デコーダーが末尾まで読まない形式でも上限を超えたデータは受け付けない
+	if err := source.check(s.limits); err != nil {
+		return nil, err
+	}
+
+	// EXIFの向きを適用してからリサイズする
+	decoded.img = applyOrientation(decoded.img, orientation)
+	return decoded, nil
+}
+
+// sourceOf はデコード済みの元画像をこのサービスの実装に変換します
+func sourceOf(source service.SourceImage) (*sourceImage, error) {
+	decoded, ok := source.(*sourceImage)
+	if !ok || decoded == nil || decoded.img == nil {
+		return nil, errors.New("source image was not decoded by this service")
 	}
+	return decoded, nil
+}
 
+// GenerateRenditions は一度のデコードで指定されたすべてのレンディションと知覚ハッシュ、代表色、プレースホルダーを生成します
+func (s *ImageProcessingServiceImpl) GenerateRenditions(source service.SourceImage, specs []valueobject.RenditionSpec) (*service.ProcessingResult, error) {
+	decoded, err := sourceOf(source)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to decode image: %w", err)
+		return nil, err
 	}
+	// デコード済みの画像を全レンディションで共有
+	img := decoded.img
+	animation := decoded.animation
+	contentType := decoded.contentType
 
-	// 画像サイズを取得
-	bounds := img.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
+	outputs := make([]service.RenditionOutput, 0, len(specs))
+	for _, spec := range specs {
+		// 透かしが設定されている場合は重ねる画像を用意
+		var mark image.Image
+		if watermark, ok := spec.Watermark(); ok {
+			mark, err = s.watermarkMark(watermark)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate rendition %s: %w", spec.Name(), err)
+			}
+		}
+
+		// アニメーションを維持するレンディション
+		if animation != nil && spec.Animated() && isGIF(spec.OutputContentType(contentType)) {
+			output, err := s.generateAnimatedRendition(animation, spec, mark)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate rendition %s: %w", spec.Name(), err)
+			}
+			outputs = append(outputs, output)
+			continue
+		}
 
-	return width, height, nil
+		// 切り取り方法に応じて元画像の使用領域を決めてリサイズ
+		region := cropRegion(img, spec)
+		resized := applyCrop(img, region, spec)
+		if watermark, ok := spec.Watermark(); ok {
+			resized, err = applyWatermark(resized, watermark, mark)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate rendition %s: %w", spec.Name(), err)
+			}
+		}
+
+		// レンディションをエンコード
+		renditionData, dimensions, err := s.encodeImage(resized, spec.OutputContentType(contentType), spec.Quality())
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate rendition %s: %w", spec.Name(), err)
+		}
+		dimensions = dimensions.WithCropBox(toCropBox(img.Bounds(), region))
+
+		outputs = append(outputs, service.RenditionOutput{
+			Spec:       spec,
+			Data:       renditionData,
+			Dimensions: dimensions,
+		})
+	}
+
+	// 読み込み中に表示するプレースホルダーを生成
+	placeholder, err := s.placeholderImage(img)
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.ProcessingResult{
+		Renditions:     outputs,
+		PerceptualHash: differenceHash(img),
+		Palette:        extractPalette(img),
+		BlurHash:       blurHash(img),
+		LQIP:           placeholder,
+	}, nil
 }
 
-// GenerateThumbnail はサムネイルを生成します
-func (s *ImageProcessingServiceImpl) GenerateThumbnail(data valueobject.ImageData, targetWidth int) (valueobject.ImageData, valueobject.Dimensions, error) {
-	// コンテンツタイプをチェック
-	if !s.IsSupported(data.ContentType) {
-		return valueobject.ImageData{}, valueobject.Dimensions{}, fmt.Errorf("unsupported image format: %s", data.ContentType)
+// generateAnimatedRendition はフレームの遅延とループ回数を維持したアニメーションレンディションを生成します
+func (s *ImageProcessingServiceImpl) generateAnimatedRendition(animation *gif.GIF, spec valueobject.RenditionSpec, mark image.Image) (service.RenditionOutput, error) {
+	resized, region, err := resizeAnimation(animation, spec, mark)
+	if err != nil {
+		return service.RenditionOutput{}, err
 	}
 
-	var img image.Image
-	var err error
+	encoded, err := encodeAnimation(resized)
+	if err != nil {
+		return service.RenditionOutput{}, err
+	}
 
-	// フォーマットに応じたデコード
-	reader := bytes.NewReader(data.Data)
-	if strings.Contains(data.ContentType, "jpeg") || strings.Contains(data.ContentType, "jpg") {
-		img, err = jpeg.Decode(reader)
-	} else if strings.Contains(data.ContentType, "png") {
-		img, err = png.Decode(reader)
-	} else {
-		return valueobject.ImageData{}, valueobject.Dimensions{}, fmt.Errorf("format not implemented: %s", data.ContentType)
+	dimensions, err := valueobject.NewDimensions(resized.Config.Width, resized.Config.Height)
+	if err != nil {
+		return service.RenditionOutput{}, fmt.Errorf("invalid thumbnail dimensions: %w", err)
 	}
+	// 論理スクリーンは原点から始まるため領域をそのまま切り取り領域とする
+	dimensions = dimensions.WithCropBox(toCropBox(image.Rectangle{}, region))
+
+	return service.RenditionOutput{
+		Spec:       spec,
+		Data:       valueobject.NewImageData(encoded, "image/gif"),
+		Dimensions: dimensions,
+	}, nil
+}
 
+// Render は変換パラメータに従って画像をリサイズ・切り取りし、透かしがあれば重ねます
+func (s *ImageProcessingServiceImpl) Render(source service.SourceImage, options valueobject.RenderOptions) (valueobject.ImageData, valueobject.Dimensions, error) {
+	decoded, err := sourceOf(source)
 	if err != nil {
-		return valueobject.ImageData{}, valueobject.Dimensions{}, fmt.Errorf("failed to decode image: %w", err)
+		return valueobject.ImageData{}, valueobject.Dimensions{}, err
 	}
+	img := decoded.img
 
-	// サムネイルを生成（幅を指定して縦横比を維持）
-	thumbnail := imaging.Resize(img, targetWidth, 0, imaging.Lanczos)
+	// フィットモードに応じて変換
+	var rendered image.Image
+	switch options.Fit() {
+	case valueobject.FitCover:
+		rendered = imaging.Fill(img, options.Width(), options.Height(), gravityAnchor(options.Gravity()), imaging.Lanczos)
+	case valueobject.FitFill:
+		rendered = imaging.Resize(img, options.Width(), options.Height(), imaging.Lanczos)
+	default:
+		rendered = fitWithin(img, options.Width(), options.Height())
+	}
 
-	// サムネイルのサイズを取得
-	bounds := thumbnail.Bounds()
-	thumbnailWidth := bounds.Dx()
-	thumbnailHeight := bounds.Dy()
+	// 透かしが設定されている場合は変換結果に重ねる
+	if watermark, ok := options.Watermark(); ok {
+		mark, err := s.watermarkMark(watermark)
+		if err != nil {
+			return valueobject.ImageData{}, valueobject.Dimensions{}, fmt.Errorf("failed to render watermark: %w", err)
+		}
+		rendered, err = applyWatermark(rendered, watermark, mark)
+		if err != nil {
+			return valueobject.ImageData{}, valueobject.Dimensions{}, fmt.Errorf("failed to render watermark: %w", err)
+		}
+	}
 
-	// サムネイルをエンコード
-	var buf bytes.Buffer
-	var encodeErr error
+	return s.encodeImage(rendered, options.OutputContentType(decoded.contentType), options.Quality())
+}
 
-	if strings.Contains(data.ContentType, "jpeg") || strings.Contains(data.ContentType, "jpg") {
-		encodeErr = jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 85})
-	} else if strings.Contains(data.ContentType, "png") {
-		encodeErr = png.Encode(&buf, thumbnail)
+// gravityAnchor は切り取り位置をimagingのアンカーに変換します
+func gravityAnchor(gravity valueobject.Gravity) imaging.Anchor {
+	switch gravity {
+	case valueobject.GravityNorth:
+		return imaging.Top
+	case valueobject.GravitySouth:
+		return imaging.Bottom
+	case valueobject.GravityEast:
+		return imaging.Right
+	case valueobject.GravityWest:
+		return imaging.Left
+	case valueobject.GravityNorthEast:
+		return imaging.TopRight
+	case valueobject.GravityNorthWest:
+		return imaging.TopLeft
+	case valueobject.GravitySouthEast:
+		return imaging.BottomRight
+	case valueobject.GravitySouthWest:
+		return imaging.BottomLeft
+	default:
+		return imaging.Center
 	}
+}
+
+// decode は大きさの上限を確認してからメモリ上の画像をデコードします（透かし画像など。GIFは先頭フレーム）
+func (s *ImageProcessingServiceImpl) decode(data valueobject.ImageData) (image.Image, error) {
+	if _, err := checkLimits(s.formats, s.limits, data.ContentType, data.Data); err != nil {
+		return nil, err
+	}
+	return s.formats.Decode(data.ContentType, data.Data)
+}
+
+// encodeImage は画像を指定された形式でエンコードし、サイズとともに返します
+func (s *ImageProcessingServiceImpl) encodeImage(img image.Image, contentType string, quality int) (valueobject.ImageData, valueobject.Dimensions, error) {
+	// 画像のサイズを取得
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
 
-	if encodeErr != nil {
-		return valueobject.ImageData{}, valueobject.Dimensions{}, fmt.Errorf("failed to encode thumbnail: %w", encodeErr)
+	// デコードのみ対応の形式（WebP・BMP・TIFF）はJPEGで出力
+	if !s.formats.CanEncode(contentType) {
+		contentType = fallbackContentType
 	}
 
-	// サムネイルデータを作成
-	thumbnailData := valueobject.NewImageData(buf.Bytes(), data.ContentType)
+	// 画像をエンコード
+	var buf bytes.Buffer
+	if err := s.formats.Encode(&buf, img, contentType, quality); err != nil {
+		return valueobject.ImageData{}, valueobject.Dimensions{}, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
 
-	// サムネイルのサイズ値オブジェクトを作成
-	dimensions, err := valueobject.NewDimensions(thumbnailWidth, thumbnailHeight)
+	// サイズ値オブジェクトを作成
+	dimensions, err := valueobject.NewDimensions(width, height)
 	if err != nil {
 		return valueobject.ImageData{}, valueobject.Dimensions{}, fmt.Errorf("invalid thumbnail dimensions: %w", err)
 	}
 
-	return thumbnailData, dimensions, nil
+	return valueobject.NewImageData(buf.Bytes(), s.formats.CanonicalContentType(contentType)), dimensions, nil
+}
+
+// fitWithin は縦横比を維持したまま最大サイズに収まるよう画像を縮小します
+// maxHeight が0の場合は幅のみで制限します
+func fitWithin(img image.Image, maxWidth, maxHeight int) image.Image {
+	bounds := img.Bounds()
+	if maxHeight <= 0 {
+		if bounds.Dx() <= maxWidth {
+			return img
+		}
+		return imaging.Resize(img, maxWidth, 0, imaging.Lanczos)
+	}
+	return imaging.Fit(img, maxWidth, maxHeight, imaging.Lanczos)
 }
 
 // ExtractImageID は画像キーから画像IDを抽出します
@@ -144,6 +361,5 @@ func (s *ImageProcessingServiceImpl) ExtractImageID(key string) (string, error)
 
 // IsSupported は指定されたコンテンツタイプがサポートされているかをチェックします
 func (s *ImageProcessingServiceImpl) IsSupported(contentType string) bool {
-	_, supported := s.supportedFormats[contentType]
-	return supported
+	return s.formats.CanDecode(contentType)
 }
@@ -0,0 +1,106 @@
+package imaging
+
+import (
+	"cloudpix/internal/domain/imagemanagement/service"
+	imagevalueobject "cloudpix/internal/domain/imagemanagement/valueobject"
+	thumbnailservice "cloudpix/internal/domain/thumbnailmanagement/service"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+)
+
+// checkLimits は画像全体をデコードする前に、バイト数とヘッダーで宣言された画素数が上限以内かを確認します
+func checkLimits(formats *FormatRegistry, limits imagevalueobject.ImageLimits, contentType string, data []byte) (image.Config, error) {
+	if err := limits.CheckBytes(int64(len(data))); err != nil {
+		return image.Config{}, err
+	}
+
+	config, err := formats.DecodeConfig(contentType, data)
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	if err := limits.CheckDimensions(config.Width, config.Height); err != nil {
+		return image.Config{}, err
+	}
+	return config, nil
+}
+
+// limitedReader はバイト数の上限を超えて読み込まないリーダー
+// 上限を超えたかどうかと、読み込み元で発生したエラーを記録します
+type limitedReader struct {
+	r        io.Reader
+	maxBytes int64
+	read     int64
+	exceeded bool
+	readErr  error
+}
+
+// newLimitedReader は新しい上限付きリーダーを作成します
+func newLimitedReader(r io.Reader, maxBytes int64) *limitedReader {
+	return &limitedReader{r: r, maxBytes: maxBytes}
+}
+
+// Read は上限までデータを読み込み、上限を超えた場合はデコーダーに EOF を返します
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.exceeded {
+		return 0, io.EOF
+	}
+	// 上限を超えたことを検出するため1バイト余分に読み込む
+	if remaining := l.maxBytes + 1 - l.read; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.maxBytes {
+		l.exceeded = true
+		return n, io.EOF
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		l.readErr = err
+	}
+	return n, err
+}
+
+// check は読み込んだバイト数が上限を超えていないかを確認します
+func (l *limitedReader) check(limits imagevalueobject.ImageLimits) error {
+	if l.exceeded {
+		return limits.CheckBytes(l.read)
+	}
+	return nil
+}
+
+// wrap はデコードのエラーを、上限超過や読み込み元のエラーが原因であればそのエラーに置き換えます
+func (l *limitedReader) wrap(err error, limits imagevalueobject.ImageLimits) error {
+	if limitErr := l.check(limits); limitErr != nil {
+		return limitErr
+	}
+	if l.readErr != nil {
+		return fmt.Errorf("%w: %v", thumbnailservice.ErrSourceRead, l.readErr)
+	}
+	return err
+}
+
+// ImageInspectorImpl は画像のヘッダーを読み取って検査するサービスの実装
+type ImageInspectorImpl struct {
+	formats *FormatRegistry
+	limits  imagevalueobject.ImageLimits
+}
+
+// NewImageInspector は新しい画像検査サービスを作成します
+func NewImageInspector(limits imagevalueobject.ImageLimits) service.ImageInspector {
+	return &ImageInspectorImpl{
+		formats: NewFormatRegistry(),
+		limits:  limits,
+	}
+}
+
+// Inspect は画像のヘッダーから幅と高さを読み取り、大きさの上限を超えていないかを確認します
+func (i *ImageInspectorImpl) Inspect(contentType string, data []byte) (int, int, error) {
+	config, err := checkLimits(i.formats, i.limits, contentType, data)
+	if err != nil {
+		return 0, 0, err
+	}
+	return config.Width, config.Height, nil
+}
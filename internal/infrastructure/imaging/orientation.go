@@ -0,0 +1,165 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+
+	"github.com/disintegration/imaging"
+)
+
+// EXIFのOrientationタグの値
+const (
+	orientationUnspecified = 0
+	orientationNormal      = 1
+	orientationFlipH       = 2
+	orientationRotate180   = 3
+	orientationFlipV       = 4
+	orientationTranspose   = 5
+	orientationRotate270   = 6
+	orientationTransverse  = 7
+	orientationRotate90    = 8
+)
+
+// EXIF解析で使用するマーカーとタグ
+const (
+	jpegMarkerSOI       = 0xD8
+	jpegMarkerAPP1      = 0xE1
+	jpegMarkerSOS       = 0xDA
+	exifTagOrientation  = 0x0112
+	exifTypeShort       = 3
+	exifIFDEntrySize    = 12
+	exifHeaderSignature = "Exif\x00\x00"
+)
+
+// readOrientation はJPEGデータからEXIFのOrientationタグを読み取ります
+// タグが存在しない、または解析できない場合は orientationUnspecified を返します
+func readOrientation(data []byte) int {
+	tiff := findEXIF(data)
+	if tiff == nil {
+		return orientationUnspecified
+	}
+	return parseEXIFOrientation(tiff)
+}
+
+// findEXIF はJPEGデータのAPP1セグメントからTIFF形式のEXIFデータを探します
+// EXIFが存在しない、またはJPEGとして解析できない場合は nil を返します
+func findEXIF(data []byte) []byte {
+	// JPEGのSOIマーカーを確認
+	if len(data) < 4 || data[0] != 0xFF || data[1] != jpegMarkerSOI {
+		return nil
+	}
+
+	// APP1セグメントを探す
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil
+		}
+		marker := data[pos+1]
+
+		// パディングのFFはスキップ
+		if marker == 0xFF {
+			pos++
+			continue
+		}
+
+		// 画像データの開始以降にEXIFは存在しない
+		if marker == jpegMarkerSOS {
+			return nil
+		}
+
+		segmentLength := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		if segmentLength < 2 || pos+2+segmentLength > len(data) {
+			return nil
+		}
+		segment := data[pos+4 : pos+2+segmentLength]
+
+		if marker == jpegMarkerAPP1 && bytes.HasPrefix(segment, []byte(exifHeaderSignature)) {
+			return segment[len(exifHeaderSignature):]
+		}
+
+		pos += 2 + segmentLength
+	}
+
+	return nil
+}
+
+// parseEXIFOrientation はTIFF形式のEXIFデータからOrientationタグを探します
+func parseEXIFOrientation(tiff []byte) int {
+	order, ok := tiffByteOrder(tiff)
+	if !ok {
+		return orientationUnspecified
+	}
+
+	// IFD0のエントリを走査
+	ifdOffset := int(order.Uint32(tiff[4:8]))
+	if ifdOffset < 8 || ifdOffset+2 > len(tiff) {
+		return orientationUnspecified
+	}
+	entryCount := int(order.Uint16(tiff[ifdOffset : ifdOffset+2]))
+
+	for i := 0; i < entryCount; i++ {
+		entry := ifdOffset + 2 + i*exifIFDEntrySize
+		if entry+exifIFDEntrySize > len(tiff) {
+			return orientationUnspecified
+		}
+
+		if order.Uint16(tiff[entry:entry+2]) != exifTagOrientation {
+			continue
+		}
+		if order.Uint16(tiff[entry+2:entry+4]) != exifTypeShort {
+			return orientationUnspecified
+		}
+
+		orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+		if orientation < orientationNormal || orientation > orientationRotate90 {
+			return orientationUnspecified
+		}
+		return orientation
+	}
+
+	return orientationUnspecified
+}
+
+// tiffByteOrder はTIFF形式のデータのヘッダーからバイトオーダーを判定します
+func tiffByteOrder(tiff []byte) (binary.ByteOrder, bool) {
+	if len(tiff) < 8 {
+		return nil, false
+	}
+	switch string(tiff[:2]) {
+	case "II":
+		return binary.LittleEndian, true
+	case "MM":
+		return binary.BigEndian, true
+	default:
+		return nil, false
+	}
+}
+
+// applyOrientation はOrientationタグに従って画像を回転・反転します
+func applyOrientation(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case orientationFlipH:
+		return imaging.FlipH(img)
+	case orientationRotate180:
+		return imaging.Rotate180(img)
+	case orientationFlipV:
+		return imaging.FlipV(img)
+	case orientationTranspose:
+		return imaging.Transpose(img)
+	case orientationRotate270:
+		return imaging.Rotate270(img)
+	case orientationTransverse:
+		return imaging.Transverse(img)
+	case orientationRotate90:
+		return imaging.Rotate90(img)
+	default:
+		return img
+	}
+}
+
+// swapsDimensions は指定されたOrientationで幅と高さが入れ替わるかを判定します
+func swapsDimensions(orientation int) bool {
+	return orientation >= orientationTranspose && orientation <= orientationRotate90
+}
@@ -0,0 +1,206 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/disintegration/imaging"
+)
+
+// orientationTIFF はIFD0に指定したエントリを並べたTIFFを返します（値はエントリ内に格納）
+func orientationTIFF(order binary.ByteOrder, entries ...[3]uint16) []byte {
+	tiff := make([]byte, 8+2+len(entries)*exifIFDEntrySize+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:4], 42)
+	order.PutUint32(tiff[4:8], 8)
+	order.PutUint16(tiff[8:10], uint16(len(entries)))
+	for i, e := range entries {
+		entry := 10 + i*exifIFDEntrySize
+		order.PutUint16(tiff[entry:], e[0])
+		order.PutUint16(tiff[entry+2:], e[1])
+		order.PutUint32(tiff[entry+4:], 1)
+		order.PutUint16(tiff[entry+8:], e[2])
+	}
+	return tiff
+}
+
+func TestParseEXIFOrientation(t *testing.T) {
+	tests := []struct {
+		name string
+		tiff []byte
+		want int
+	}{
+		{
+			name: "リトルエンディアン",
+			tiff: orientationTIFF(binary.LittleEndian, [3]uint16{exifTagOrientation, exifTypeShort, orientationRotate90}),
+			want: orientationRotate90,
+		},
+		{
+			name: "ビッグエンディアン",
+			tiff: orientationTIFF(binary.BigEndian, [3]uint16{exifTagOrientation, exifTypeShort, orientationRotate270}),
+			want: orientationRotate270,
+		},
+		{
+			name: "他のタグの後ろにあるOrientation",
+			tiff: orientationTIFF(binary.BigEndian,
+				[3]uint16{0x0100, exifTypeShort, 4000},
+				[3]uint16{0x0101, exifTypeShort, 3000},
+				[3]uint16{exifTagOrientation, exifTypeShort, orientationFlipH},
+			),
+			want: orientationFlipH,
+		},
+		{
+			name: "Orientationのないデータ",
+			tiff: orientationTIFF(binary.LittleEndian, [3]uint16{0x0100, exifTypeShort, 4000}),
+			want: orientationUnspecified,
+		},
+		{
+			name: "範囲外の値（0）",
+			tiff: orientationTIFF(binary.LittleEndian, [3]uint16{exifTagOrientation, exifTypeShort, 0}),
+			want: orientationUnspecified,
+		},
+		{
+			name: "範囲外の値（9）",
+			tiff: orientationTIFF(binary.LittleEndian, [3]uint16{exifTagOrientation, exifTypeShort, 9}),
+			want: orientationUnspecified,
+		},
+		{
+			name: "SHORT以外の型",
+			tiff: orientationTIFF(binary.LittleEndian, [3]uint16{exifTagOrientation, exifTypeLong, orientationRotate90}),
+			want: orientationUnspecified,
+		},
+		{
+			name: "途中で切れたエントリ",
+			tiff: orientationTIFF(binary.LittleEndian, [3]uint16{exifTagOrientation, exifTypeShort, orientationRotate90})[:16],
+			want: orientationUnspecified,
+		},
+		{
+			name: "エントリ数がデータより多い",
+			tiff: func() []byte {
+				tiff := orientationTIFF(binary.LittleEndian, [3]uint16{0x0100, exifTypeShort, 4000})
+				binary.LittleEndian.PutUint16(tiff[8:10], 0xFFFF)
+				return tiff
+			}(),
+			want: orientationUnspecified,
+		},
+		{
+			name: "ヘッダー内を指すIFDのオフセット",
+			tiff: func() []byte {
+				tiff := orientationTIFF(binary.LittleEndian, [3]uint16{exifTagOrientation, exifTypeShort, orientationRotate90})
+				binary.LittleEndian.PutUint32(tiff[4:8], 4)
+				return tiff
+			}(),
+			want: orientationUnspecified,
+		},
+		{
+			name: "末尾を超えるIFDのオフセット",
+			tiff: func() []byte {
+				tiff := orientationTIFF(binary.BigEndian, [3]uint16{exifTagOrientation, exifTypeShort, orientationRotate90})
+				binary.BigEndian.PutUint32(tiff[4:8], 0xFFFFFFF0)
+				return tiff
+			}(),
+			want: orientationUnspecified,
+		},
+		{
+			name: "バイトオーダーが不正",
+			tiff: append([]byte("XX"), orientationTIFF(binary.LittleEndian, [3]uint16{exifTagOrientation, exifTypeShort, orientationRotate90})[2:]...),
+			want: orientationUnspecified,
+		},
+		{
+			name: "ヘッダーより短いデータ",
+			tiff: []byte("II*\x00"),
+			want: orientationUnspecified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseEXIFOrientation(tt.tiff); got != tt.want {
+				t.Errorf("parseEXIFOrientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadOrientation(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{
+			name: "EXIFのOrientation",
+			data: jpegWithEXIF(orientationTIFF(binary.BigEndian, [3]uint16{exifTagOrientation, exifTypeShort, orientationRotate180})),
+			want: orientationRotate180,
+		},
+		{
+			name: "EXIFのないJPEG",
+			data: []byte{0xFF, jpegMarkerSOI, 0xFF, jpegMarkerSOS, 0, 2},
+			want: orientationUnspecified,
+		},
+		{
+			name: "JPEGでないデータ",
+			data: []byte("\x89PNG\r\n\x1a\n"),
+			want: orientationUnspecified,
+		},
+		{
+			name: "長さが末尾を超えるセグメント",
+			data: []byte{0xFF, jpegMarkerSOI, 0xFF, jpegMarkerAPP1, 0xFF, 0xFF, 'E', 'x'},
+			want: orientationUnspecified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readOrientation(tt.data); got != tt.want {
+				t.Errorf("readOrientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// 左上だけが赤い3x2の画像
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	src := imaging.New(3, 2, color.NRGBA{A: 0xff})
+	src.Set(0, 0, red)
+
+	tests := []struct {
+		name        string
+		orientation int
+		wantSize    image.Point
+		wantRed     image.Point // 元画像の左上の画素の移動先
+	}{
+		{name: "指定なし", orientation: orientationUnspecified, wantSize: image.Pt(3, 2), wantRed: image.Pt(0, 0)},
+		{name: "通常", orientation: orientationNormal, wantSize: image.Pt(3, 2), wantRed: image.Pt(0, 0)},
+		{name: "左右反転", orientation: orientationFlipH, wantSize: image.Pt(3, 2), wantRed: image.Pt(2, 0)},
+		{name: "180度回転", orientation: orientationRotate180, wantSize: image.Pt(3, 2), wantRed: image.Pt(2, 1)},
+		{name: "上下反転", orientation: orientationFlipV, wantSize: image.Pt(3, 2), wantRed: image.Pt(0, 1)},
+		{name: "転置", orientation: orientationTranspose, wantSize: image.Pt(2, 3), wantRed: image.Pt(0, 0)},
+		{name: "時計回りに90度回転", orientation: orientationRotate270, wantSize: image.Pt(2, 3), wantRed: image.Pt(1, 0)},
+		{name: "反転転置", orientation: orientationTransverse, wantSize: image.Pt(2, 3), wantRed: image.Pt(1, 2)},
+		{name: "反時計回りに90度回転", orientation: orientationRotate90, wantSize: image.Pt(2, 3), wantRed: image.Pt(0, 2)},
+		{name: "範囲外の値", orientation: 9, wantSize: image.Pt(3, 2), wantRed: image.Pt(0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := imaging.Clone(applyOrientation(src, tt.orientation))
+			if size := got.Bounds().Size(); size != tt.wantSize {
+				t.Fatalf("applyOrientation() size = %v, want %v", size, tt.wantSize)
+			}
+			if c := got.NRGBAAt(tt.wantRed.X, tt.wantRed.Y); c != red {
+				t.Errorf("applyOrientation() pixel at %v = %v, want %v", tt.wantRed, c, red)
+			}
+			if swapped := swapsDimensions(tt.orientation); swapped != (tt.wantSize.X == 2) {
+				t.Errorf("swapsDimensions(%d) = %t, want %t", tt.orientation, swapped, tt.wantSize.X == 2)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package imaging
+
+import (
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"image"
+	"sort"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// 代表色の抽出に使用する作業画像の最大辺
+	paletteWorkSize = 64
+	// 抽出する代表色の最大数
+	paletteSize = 5
+	// 代表色の計算対象とする画素の最小アルファ値
+	paletteMinAlpha = 128
+)
+
+// rgb は代表色の計算に使用する画素の色
+type rgb [3]uint8
+
+// extractPalette はメディアンカット法で画像の代表色を占有率の高い順に抽出します
+// 処理量を抑えるため、サムネイル相当の大きさに縮小した画像から計算します
+func extractPalette(img image.Image) []valueobject.PaletteColor {
+	small := imaging.Fit(img, paletteWorkSize, paletteWorkSize, imaging.Box)
+
+	// 透明な画素は背景とみなして除外
+	pixels := make([]rgb, 0, len(small.Pix)/4)
+	for i := 0; i+3 < len(small.Pix); i += 4 {
+		if small.Pix[i+3] < paletteMinAlpha {
+			continue
+		}
+		pixels = append(pixels, rgb{small.Pix[i], small.Pix[i+1], small.Pix[i+2]})
+	}
+	if len(pixels) == 0 {
+		return nil
+	}
+
+	// 色の幅が最も広い箱を中央値で分割することを繰り返す
+	boxes := [][]rgb{pixels}
+	for len(boxes) < paletteSize {
+		index, channel := widestBox(boxes)
+		if index < 0 {
+			break
+		}
+		box := boxes[index]
+		sort.Slice(box, func(i, j int) bool { return box[i][channel] < box[j][channel] })
+		median := len(box) / 2
+		boxes[index] = box[:median]
+		boxes = append(boxes, box[median:])
+	}
+
+	// 中央値での分割により同じ色が複数の箱に分かれることがあるため、同色の箱はまとめる
+	counts := make(map[rgb]int, len(boxes))
+	order := make([]rgb, 0, len(boxes))
+	for _, box := range boxes {
+		average := averageColor(box)
+		if _, ok := counts[average]; !ok {
+			order = append(order, average)
+		}
+		counts[average] += len(box)
+	}
+
+	palette := make([]valueobject.PaletteColor, 0, len(order))
+	for _, average := range order {
+		paletteColor, err := valueobject.NewPaletteColor(average[0], average[1], average[2], float64(counts[average])/float64(len(pixels)))
+		if err != nil {
+			continue
+		}
+		palette = append(palette, paletteColor)
+	}
+	sort.SliceStable(palette, func(i, j int) bool { return palette[i].Weight() > palette[j].Weight() })
+
+	return palette
+}
+
+// widestBox は分割可能な箱のうち色の幅が最も広い箱とそのチャンネルを返します
+// 分割できる箱がない場合は -1 を返します
+func widestBox(boxes [][]rgb) (int, int) {
+	index, channel, widest := -1, 0, 0
+	for i, box := range boxes {
+		if len(box) < 2 {
+			continue
+		}
+		for c := 0; c < 3; c++ {
+			low, high := box[0][c], box[0][c]
+			for _, pixel := range box[1:] {
+				if pixel[c] < low {
+					low = pixel[c]
+				}
+				if pixel[c] > high {
+					high = pixel[c]
+				}
+			}
+			if width := int(high) - int(low); width > widest {
+				index, channel, widest = i, c, width
+			}
+		}
+	}
+	return index, channel
+}
+
+// averageColor は箱に含まれる画素の平均色を返します
+func averageColor(box []rgb) rgb {
+	var sum [3]int
+	for _, pixel := range box {
+		sum[0] += int(pixel[0])
+		sum[1] += int(pixel[1])
+		sum[2] += int(pixel[2])
+	}
+	n := len(box)
+	return rgb{uint8(sum[0] / n), uint8(sum[1] / n), uint8(sum[2] / n)}
+}
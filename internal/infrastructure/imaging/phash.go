@@ -0,0 +1,28 @@
+package imaging
+
+import (
+	"image"
+
+	"github.com/disintegration/imaging"
+)
+
+// differenceHash は画像の64ビットの知覚ハッシュ（dHash）を計算します
+// 9x8に縮小したグレースケール画像で、隣接する画素の輝度の大小関係をビットにします
+func differenceHash(img image.Image) uint64 {
+	small := imaging.Grayscale(imaging.Resize(img, 9, 8, imaging.Box))
+
+	var hash uint64
+	bit := uint(0)
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			left := small.Pix[small.PixOffset(x, y)]
+			right := small.Pix[small.PixOffset(x+1, y)]
+			if left < right {
+				hash |= 1 << bit
+			}
+			bit++
+		}
+	}
+
+	return hash
+}
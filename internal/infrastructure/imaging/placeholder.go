@@ -0,0 +1,164 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// BlurHashの計算に使用する作業画像の最大辺
+	blurHashWorkSize = 32
+	// BlurHashの長辺方向・短辺方向の成分数
+	blurHashMajorComponents = 4
+	blurHashMinorComponents = 3
+	// LQIPの最大辺と品質
+	lqipSize    = 16
+	lqipQuality = 40
+	// LQIPの形式
+	lqipContentType = "image/jpeg"
+)
+
+// BlurHashで使用する83進数の文字
+const base83Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// blurHash は画像のBlurHash文字列を計算します
+// 縦長の画像は縦方向の成分を多くして、プレースホルダーの縦横比に合わせます
+func blurHash(img image.Image) string {
+	small := flatten(imaging.Fit(img, blurHashWorkSize, blurHashWorkSize, imaging.Box))
+	width, height := small.Bounds().Dx(), small.Bounds().Dy()
+
+	componentsX, componentsY := blurHashMajorComponents, blurHashMinorComponents
+	if height > width {
+		componentsX, componentsY = componentsY, componentsX
+	}
+
+	// 線形RGBに変換した画素
+	linear := make([][3]float64, width*height)
+	for i := range linear {
+		offset := i * 4
+		linear[i] = [3]float64{
+			srgbToLinear(small.Pix[offset]),
+			srgbToLinear(small.Pix[offset+1]),
+			srgbToLinear(small.Pix[offset+2]),
+		}
+	}
+
+	// 各成分のコサイン基底への射影を計算（先頭が直流成分）
+	factors := make([][3]float64, 0, componentsX*componentsY)
+	for cy := 0; cy < componentsY; cy++ {
+		for cx := 0; cx < componentsX; cx++ {
+			normalisation := 2.0
+			if cx == 0 && cy == 0 {
+				normalisation = 1.0
+			}
+			var factor [3]float64
+			for y := 0; y < height; y++ {
+				for x := 0; x < width; x++ {
+					basis := normalisation *
+						math.Cos(math.Pi*float64(cx)*float64(x)/float64(width)) *
+						math.Cos(math.Pi*float64(cy)*float64(y)/float64(height))
+					pixel := linear[y*width+x]
+					factor[0] += basis * pixel[0]
+					factor[1] += basis * pixel[1]
+					factor[2] += basis * pixel[2]
+				}
+			}
+			scale := 1.0 / float64(width*height)
+			factors = append(factors, [3]float64{factor[0] * scale, factor[1] * scale, factor[2] * scale})
+		}
+	}
+
+	var hash strings.Builder
+	hash.WriteString(encodeBase83((componentsX-1)+(componentsY-1)*9, 1))
+
+	// 交流成分の最大値を量子化
+	maximumValue := 1.0
+	if len(factors) > 1 {
+		actualMaximum := 0.0
+		for _, factor := range factors[1:] {
+			for _, value := range factor {
+				actualMaximum = math.Max(actualMaximum, math.Abs(value))
+			}
+		}
+		quantisedMaximum := int(math.Max(0, math.Min(82, math.Floor(actualMaximum*166-0.5))))
+		maximumValue = float64(quantisedMaximum+1) / 166
+		hash.WriteString(encodeBase83(quantisedMaximum, 1))
+	} else {
+		hash.WriteString(encodeBase83(0, 1))
+	}
+
+	dc := factors[0]
+	hash.WriteString(encodeBase83(linearToSRGB(dc[0])<<16+linearToSRGB(dc[1])<<8+linearToSRGB(dc[2]), 4))
+	for _, factor := range factors[1:] {
+		hash.WriteString(encodeBase83(encodeBlurHashAC(factor, maximumValue), 2))
+	}
+
+	return hash.String()
+}
+
+// encodeBlurHashAC は交流成分を19段階に量子化して1つの値にまとめます
+func encodeBlurHashAC(factor [3]float64, maximumValue float64) int {
+	quantise := func(value float64) int {
+		return int(math.Max(0, math.Min(18, math.Floor(signPow(value/maximumValue, 0.5)*9+9.5))))
+	}
+	return quantise(factor[0])*19*19 + quantise(factor[1])*19 + quantise(factor[2])
+}
+
+// encodeBase83 は値を指定された桁数の83進数文字列にします
+func encodeBase83(value, length int) string {
+	digits := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		digits[i] = base83Chars[value%83]
+		value /= 83
+	}
+	return string(digits)
+}
+
+// srgbToLinear はsRGBの値を線形RGBに変換します
+func srgbToLinear(value uint8) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB は線形RGBの値をsRGBに変換します
+func linearToSRGB(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+// signPow は符号を維持したべき乗を計算します
+func signPow(value, exponent float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exponent), value)
+}
+
+// placeholderImage は極小のJPEG画像をdata URIとして返します（LQIP）
+func (s *ImageProcessingServiceImpl) placeholderImage(img image.Image) (string, error) {
+	small := flatten(imaging.Fit(img, lqipSize, lqipSize, imaging.Lanczos))
+
+	var buf bytes.Buffer
+	if err := s.formats.Encode(&buf, small, lqipContentType, lqipQuality); err != nil {
+		return "", fmt.Errorf("failed to encode placeholder: %w", err)
+	}
+
+	return "data:" + lqipContentType + ";base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// flatten は透過部分を白背景で塗りつぶします
+func flatten(img image.Image) *image.NRGBA {
+	bounds := img.Bounds()
+	background := imaging.New(bounds.Dx(), bounds.Dy(), color.White)
+	return imaging.Overlay(background, img, image.Pt(0, 0), 1.0)
+}
@@ -0,0 +1,230 @@
+package imaging
+
+import (
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	_ "embed"
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"sync"
+
+	"github.com/disintegration/imaging"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// 画像の短辺に対する透かしの余白の割合
+const watermarkMarginRatio = 0.03
+
+// 文字の大きさに対する透かし文字列の縁取りの太さの割合
+const watermarkOutlineRatio = 1.0 / 16
+
+// 透かし文字列の大きさを測るときの文字の大きさ（ピクセル）
+const watermarkMeasureSize = 100
+
+// watermarkFontData は透かし文字列の描画に使用する日本語を含むフォント（M+ 1p、fonts/LICENSE を参照）
+//
+//go:embed fonts/mplus-1p-regular.ttf
+var watermarkFontData []byte
+
+// watermarkFont は埋め込みフォントを一度だけ解析して返します
+var watermarkFont = sync.OnceValues(func() (*opentype.Font, error) {
+	return opentype.Parse(watermarkFontData)
+})
+
+// watermarkMark は透かしとして重ねる画像を作成します
+// 画像の透かしは読み込み済みの画像をデコードし、文字列の透かしは重ねる画像の大きさに合わせて描画するためnilを返します
+func (s *ImageProcessingServiceImpl) watermarkMark(watermark valueobject.Watermark) (image.Image, error) {
+	if watermark.Type() == valueobject.WatermarkText {
+		return nil, nil
+	}
+
+	overlay := watermark.Overlay()
+	if overlay.IsEmpty() {
+		return nil, fmt.Errorf("watermark image is not loaded: %s", watermark.ImageKey())
+	}
+	mark, err := s.decode(overlay)
+	if err != nil {
+		// 透かし画像の問題で元画像が拒否されないようにエラーを連鎖させない
+		return nil, fmt.Errorf("failed to decode watermark image: %v", err)
+	}
+	return mark, nil
+}
+
+// scaleWatermark は重ねる画像の幅に対する割合の大きさで透かしを用意します
+// 文字列はその大きさのフォントで描画し、画像は拡大縮小します（画像からはみ出さないよう収める）
+func scaleWatermark(bounds image.Rectangle, watermark valueobject.Watermark, mark image.Image) (image.Image, error) {
+	if bounds.Empty() {
+		return nil, nil
+	}
+	targetWidth := maxInt(1, int(math.Round(float64(bounds.Dx())*watermark.Scale())))
+
+	if watermark.Type() == valueobject.WatermarkText {
+		return renderWatermarkText(watermark.Text(), targetWidth, bounds.Dy())
+	}
+
+	markBounds := mark.Bounds()
+	if markBounds.Empty() {
+		return nil, nil
+	}
+	targetHeight := maxInt(1, markBounds.Dy()*targetWidth/markBounds.Dx())
+	if targetHeight > bounds.Dy() {
+		targetHeight = bounds.Dy()
+		targetWidth = maxInt(1, markBounds.Dx()*targetHeight/markBounds.Dy())
+	}
+	return imaging.Resize(mark, targetWidth, targetHeight, imaging.Linear), nil
+}
+
+// renderWatermarkText は文字列を幅が maxWidth（高さは maxHeight）に収まる大きさのフォントで白文字・黒縁の透過画像に描画します
+func renderWatermarkText(text string, maxWidth, maxHeight int) (image.Image, error) {
+	parsed, err := watermarkFont()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse watermark font: %w", err)
+	}
+
+	// 基準の大きさで文字列の幅と高さを測り、縁取りを含めて収まる大きさを求める
+	measure, err := opentype.NewFace(parsed, &opentype.FaceOptions{Size: watermarkMeasureSize, DPI: 72, Hinting: font.HintingNone})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create watermark font face: %w", err)
+	}
+	metrics := measure.Metrics()
+	_, extent := textExtent(measure, text)
+	textWidth := float64(extent) / 64
+	lineHeight := float64(metrics.Ascent+metrics.Descent) / 64
+	measure.Close()
+	if textWidth <= 0 {
+		return nil, nil
+	}
+
+	size := float64(maxWidth) / (textWidth/watermarkMeasureSize + 2*watermarkOutlineRatio)
+	if height := size * (lineHeight/watermarkMeasureSize + 2*watermarkOutlineRatio); height > float64(maxHeight) {
+		size = float64(maxHeight) / (lineHeight/watermarkMeasureSize + 2*watermarkOutlineRatio)
+	}
+	size = math.Max(size, 1)
+
+	face, err := opentype.NewFace(parsed, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingNone})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create watermark font face: %w", err)
+	}
+	defer face.Close()
+
+	// 文字の形をマスクに描画
+	outline := maxInt(1, int(math.Round(size*watermarkOutlineRatio)))
+	metrics = face.Metrics()
+	left, extent := textExtent(face, text)
+	// 画素単位への切り上げで収める大きさを超えた分は切り捨てる
+	width := minInt(extent.Ceil()+2*outline, maxWidth)
+	height := minInt((metrics.Ascent+metrics.Descent).Ceil()+2*outline, maxHeight)
+	glyphs := image.NewAlpha(image.Rect(0, 0, width, height))
+	drawer := &font.Drawer{
+		Dst:  glyphs,
+		Src:  image.Opaque,
+		Face: face,
+		Dot:  fixed.Point26_6{X: fixed.I(outline) - left, Y: fixed.I(outline) + metrics.Ascent},
+	}
+	drawer.DrawString(text)
+
+	// 文字の形を縁取りの太さだけ広げた黒縁の上に白文字を重ねる
+	canvas := image.NewNRGBA(glyphs.Bounds())
+	draw.DrawMask(canvas, canvas.Bounds(), image.Black, image.Point{}, dilateAlpha(glyphs, outline), image.Point{}, draw.Over)
+	draw.DrawMask(canvas, canvas.Bounds(), image.White, image.Point{}, glyphs, image.Point{}, draw.Over)
+	return canvas, nil
+}
+
+// textExtent は文字列を描画する範囲の描画位置からの左端と幅を返します（送り幅からはみ出す字形を含む）
+func textExtent(face font.Face, text string) (fixed.Int26_6, fixed.Int26_6) {
+	bounds, advance := font.BoundString(face, text)
+	left := bounds.Min.X
+	if left > 0 {
+		left = 0
+	}
+	right := bounds.Max.X
+	if right < advance {
+		right = advance
+	}
+	return left, right - left
+}
+
+// dilateAlpha はマスクの各画素を周囲 radius 画素の最大値に置き換えて太らせます（横方向・縦方向の順に適用）
+func dilateAlpha(mask *image.Alpha, radius int) *image.Alpha {
+	bounds := mask.Bounds()
+	horizontal := image.NewAlpha(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var value uint8
+			for dx := maxInt(bounds.Min.X, x-radius); dx <= minInt(bounds.Max.X-1, x+radius); dx++ {
+				if a := mask.AlphaAt(dx, y).A; a > value {
+					value = a
+				}
+			}
+			horizontal.Pix[horizontal.PixOffset(x, y)] = value
+		}
+	}
+
+	dilated := image.NewAlpha(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var value uint8
+			for dy := maxInt(bounds.Min.Y, y-radius); dy <= minInt(bounds.Max.Y-1, y+radius); dy++ {
+				if a := horizontal.AlphaAt(x, dy).A; a > value {
+					value = a
+				}
+			}
+			dilated.Pix[dilated.PixOffset(x, y)] = value
+		}
+	}
+	return dilated
+}
+
+// applyWatermark は画像幅に対する割合の大きさで透かしを用意し、指定された位置と不透明度で重ねます
+func applyWatermark(img image.Image, watermark valueobject.Watermark, mark image.Image) (image.Image, error) {
+	scaled, err := scaleWatermark(img.Bounds(), watermark, mark)
+	if err != nil {
+		return nil, err
+	}
+	return overlayWatermark(img, watermark, scaled), nil
+}
+
+// overlayWatermark は大きさを合わせた透かしを指定された位置と不透明度で重ねます
+func overlayWatermark(img image.Image, watermark valueobject.Watermark, scaled image.Image) image.Image {
+	bounds := img.Bounds()
+	if scaled == nil || bounds.Empty() {
+		return img
+	}
+	position := watermarkOffset(bounds, scaled.Bounds().Size(), watermark.Position())
+	return imaging.Overlay(img, scaled, bounds.Min.Add(position), watermark.Opacity())
+}
+
+// watermarkOffset は画像の左上からの透かしの配置位置を計算します
+func watermarkOffset(bounds image.Rectangle, size image.Point, position valueobject.WatermarkPosition) image.Point {
+	margin := int(math.Round(float64(minInt(bounds.Dx(), bounds.Dy())) * watermarkMarginRatio))
+	left := margin
+	centerX := (bounds.Dx() - size.X) / 2
+	right := bounds.Dx() - size.X - margin
+	top := margin
+	centerY := (bounds.Dy() - size.Y) / 2
+	bottom := bounds.Dy() - size.Y - margin
+
+	switch position {
+	case valueobject.WatermarkTopLeft:
+		return image.Pt(left, top)
+	case valueobject.WatermarkTop:
+		return image.Pt(centerX, top)
+	case valueobject.WatermarkTopRight:
+		return image.Pt(right, top)
+	case valueobject.WatermarkLeft:
+		return image.Pt(left, centerY)
+	case valueobject.WatermarkCenter:
+		return image.Pt(centerX, centerY)
+	case valueobject.WatermarkRight:
+		return image.Pt(right, centerY)
+	case valueobject.WatermarkBottomLeft:
+		return image.Pt(left, bottom)
+	case valueobject.WatermarkBottom:
+		return image.Pt(centerX, bottom)
+	default:
+		return image.Pt(right, bottom)
+	}
+}
@@ -120,8 +120,11 @@ func (s *CloudWatchMetricsService) AddMetric(ctx context.Context, metricName str
 // getUnit はメトリクス名に対応する単位を返す
 func (s *CloudWatchMetricsService) getUnit(metricName string) *string {
 	switch metricName {
-	case "Duration", "ProcessingTime", "AverageProcessingTime", "Latency":
+	case "Duration", "ProcessingTime", "AverageProcessingTime", "Latency",
+		MetricDecodeLatency, MetricRenditionLatency, MetricUploadLatency, MetricThumbnailLatency:
 		return aws.String(cloudwatch.StandardUnitMilliseconds)
+	case MetricPeakHeapMemory:
+		return aws.String(cloudwatch.StandardUnitBytes)
 	default:
 		return aws.String(cloudwatch.StandardUnitCount)
 	}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"cloudpix/internal/domain/thumbnailmanagement/service"
+	"context"
+	"log"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+// サムネイル生成のメトリクス名
+const (
+	MetricDecodeLatency    = "DecodeLatency"
+	MetricRenditionLatency = "RenditionLatency"
+	MetricUploadLatency    = "UploadLatency"
+	MetricThumbnailLatency = "ThumbnailLatency"
+	MetricPeakHeapMemory   = "PeakHeapMemory"
+)
+
+// ヒープ上の使用中オブジェクトの合計バイト数を表すランタイムメトリクス
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+// メモリ使用量のサンプリング間隔
+const memorySampleInterval = 10 * time.Millisecond
+
+// ThumbnailProcessingMetrics はサムネイル生成の処理時間とピークメモリ使用量をCloudWatchに記録する実装
+type ThumbnailProcessingMetrics struct {
+	metricsService MetricsService
+	functionName   string
+}
+
+// NewThumbnailProcessingMetrics は新しいサムネイル生成メトリクスを作成します
+// metricsService が nil の場合は計測のみ行い、記録はしません
+func NewThumbnailProcessingMetrics(metricsService MetricsService, functionName string) service.ProcessingMetrics {
+	return &ThumbnailProcessingMetrics{
+		metricsService: metricsService,
+		functionName:   functionName,
+	}
+}
+
+// TrackMemory はヒープ使用量のサンプリングを開始し、停止してピーク値を返す関数を返します
+// 停止関数は複数回呼び出しても最初のピーク値を返します
+func (m *ThumbnailProcessingMetrics) TrackMemory() func() uint64 {
+	if m.metricsService == nil {
+		return func() uint64 { return 0 }
+	}
+
+	peak := readHeapObjects()
+	stopCh := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(memorySampleInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				peak = max(peak, readHeapObjects())
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() uint64 {
+		once.Do(func() {
+			close(stopCh)
+			<-done
+			peak = max(peak, readHeapObjects())
+		})
+		return peak
+	}
+}
+
+// readHeapObjects は現在のヒープ上の使用中オブジェクトの合計バイト数を読み取ります
+func readHeapObjects() uint64 {
+	samples := []metrics.Sample{{Name: heapObjectsMetric}}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return samples[0].Value.Uint64()
+}
+
+// Record は計測結果をCloudWatchに送信します
+func (m *ThumbnailProcessingMetrics) Record(ctx context.Context, stats service.ProcessingStats) {
+	if m.metricsService == nil {
+		return
+	}
+
+	dimensions := []*cloudwatch.Dimension{
+		{
+			Name:  aws.String("Service"),
+			Value: aws.String("CloudPix"),
+		},
+		{
+			Name:  aws.String("Operation"),
+			Value: aws.String("GenerateThumbnail"),
+		},
+		{
+			Name:  aws.String("FunctionName"),
+			Value: aws.String(m.functionName),
+		},
+	}
+
+	m.metricsService.AddMetric(ctx, MetricDecodeLatency, milliseconds(stats.DecodeLatency), dimensions)
+	m.metricsService.AddMetric(ctx, MetricRenditionLatency, milliseconds(stats.RenditionLatency), dimensions)
+	m.metricsService.AddMetric(ctx, MetricUploadLatency, milliseconds(stats.UploadLatency), dimensions)
+	m.metricsService.AddMetric(ctx, MetricThumbnailLatency, milliseconds(stats.TotalLatency), dimensions)
+	m.metricsService.AddMetric(ctx, MetricPeakHeapMemory, float64(stats.PeakMemoryBytes), dimensions)
+
+	// Lambdaの実行環境が停止される前に送信する
+	if err := m.metricsService.Flush(ctx); err != nil {
+		log.Printf("Error flushing thumbnail metrics: %v", err)
+	}
+}
+
+// milliseconds は処理時間をミリ秒に変換します
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
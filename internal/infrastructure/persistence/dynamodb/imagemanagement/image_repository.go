@@ -17,20 +17,60 @@ import (
 
 // DynamoDBImageItem はDynamoDBのイメージアイテム表現
 type DynamoDBImageItem struct {
-	ImageID         string   `json:"ImageID"`
-	FileName        string   `json:"FileName"`
-	ContentType     string   `json:"ContentType"`
-	Size            int      `json:"Size"`
-	UploadDate      string   `json:"UploadDate"`
-	S3ObjectKey     string   `json:"S3ObjectKey"`
-	DownloadURL     string   `json:"DownloadURL"`
-	ThumbnailURL    string   `json:"ThumbnailURL,omitempty"`
-	ThumbnailWidth  int      `json:"ThumbnailWidth,omitempty"`
-	ThumbnailHeight int      `json:"ThumbnailHeight,omitempty"`
-	Tags            []string `json:"Tags,omitempty"`
-	CreatedAt       string   `json:"CreatedAt"`
-	ModifiedAt      string   `json:"ModifiedAt"`
-	HasThumbnail    bool     `json:"HasThumbnail"`
+	ImageID            string   `json:"ImageID"`
+	FileName           string   `json:"FileName"`
+	ContentType        string   `json:"ContentType"`
+	Size               int      `json:"Size"`
+	UploadDate         string   `json:"UploadDate"`
+	S3ObjectKey        string   `json:"S3ObjectKey"`
+	DownloadURL        string   `json:"DownloadURL"`
+	Owner              string   `json:"Owner,omitempty"`
+	ThumbnailURL       string   `json:"ThumbnailURL,omitempty"`
+	ThumbnailWidth     int      `json:"ThumbnailWidth,omitempty"`
+	ThumbnailHeight    int      `json:"ThumbnailHeight,omitempty"`
+	Tags               []string `json:"Tags"` // タグがなくても属性を書き込み、タグの同期済みを表す
+	CreatedAt          string   `json:"CreatedAt"`
+	ModifiedAt         string   `json:"ModifiedAt"`
+	HasThumbnail       bool     `json:"HasThumbnail"`
+	PerceptualHash     string   `json:"PerceptualHash,omitempty"`
+	BlurHash           string   `json:"BlurHash,omitempty"`
+	LQIP               string   `json:"LQIP,omitempty"`
+	ThumbnailVersion   string   `json:"ThumbnailVersion,omitempty"`
+	ThumbnailWatermark string   `json:"ThumbnailWatermark,omitempty"`
+
+	Renditions []DynamoDBRenditionItem `json:"Renditions,omitempty"`
+	Palette    []DynamoDBPaletteItem   `json:"Palette,omitempty"`
+
+	ThumbnailStatus          string `json:"ThumbnailStatus,omitempty"`
+	ThumbnailAttempts        int    `json:"ThumbnailAttempts,omitempty"`
+	ThumbnailError           string `json:"ThumbnailError,omitempty"`
+	ThumbnailNextRetryAt     string `json:"ThumbnailNextRetryAt,omitempty"`
+	ThumbnailStatusUpdatedAt string `json:"ThumbnailStatusUpdatedAt,omitempty"`
+}
+
+// DynamoDBRenditionItem はDynamoDBのサムネイルレンディション表現
+type DynamoDBRenditionItem struct {
+	Name        string               `json:"Name"`
+	Key         string               `json:"Key"`
+	URL         string               `json:"URL"`
+	Width       int                  `json:"Width"`
+	Height      int                  `json:"Height"`
+	ContentType string               `json:"ContentType"`
+	Crop        *DynamoDBCropBoxItem `json:"Crop,omitempty"`
+}
+
+// DynamoDBCropBoxItem はDynamoDBの切り取り領域表現
+type DynamoDBCropBoxItem struct {
+	X      int `json:"X"`
+	Y      int `json:"Y"`
+	Width  int `json:"Width"`
+	Height int `json:"Height"`
+}
+
+// DynamoDBPaletteItem はDynamoDBの代表色表現
+type DynamoDBPaletteItem struct {
+	Color  string  `json:"Color"`
+	Weight float64 `json:"Weight"`
 }
 
 // DynamoDBImageRepository はDynamoDBを使用した画像リポジトリの実装
@@ -72,6 +112,11 @@ func (r *DynamoDBImageRepository) FindByID(ctx context.Context, id string) (*agg
 		return nil, fmt.Errorf("failed to unmarshal DynamoDB item: %w", err)
 	}
 
+	return toAggregate(item), nil
+}
+
+// toAggregate はDynamoDBアイテムを画像集約に変換します
+func toAggregate(item DynamoDBImageItem) *aggregate.ImageAggregate {
 	// 値オブジェクトの作成
 	fileName, _ := valueobject.NewFileName(item.FileName)
 	contentType, _ := valueobject.NewContentType(item.ContentType)
@@ -87,6 +132,7 @@ func (r *DynamoDBImageRepository) FindByID(ctx context.Context, id string) (*agg
 		UploadDate:   uploadDate,
 		S3ObjectKey:  item.S3ObjectKey,
 		DownloadURL:  item.DownloadURL,
+		Owner:        item.Owner,
 		HasThumbnail: item.HasThumbnail,
 	}
 
@@ -96,12 +142,83 @@ func (r *DynamoDBImageRepository) FindByID(ctx context.Context, id string) (*agg
 	imageAggregate.ThumbnailWidth = item.ThumbnailWidth
 	imageAggregate.ThumbnailHeight = item.ThumbnailHeight
 	imageAggregate.Tags = item.Tags
+	imageAggregate.PerceptualHash = item.PerceptualHash
+	imageAggregate.BlurHash = item.BlurHash
+	imageAggregate.LQIP = item.LQIP
+	imageAggregate.ThumbnailVersion = item.ThumbnailVersion
+	imageAggregate.ThumbnailWatermark = item.ThumbnailWatermark
+	imageAggregate.Processing = toAggregateProcessing(item)
+	for _, paletteItem := range item.Palette {
+		imageAggregate.Palette = append(imageAggregate.Palette, aggregate.PaletteColor{
+			Color:  paletteItem.Color,
+			Weight: paletteItem.Weight,
+		})
+	}
+	for _, rendition := range item.Renditions {
+		imageAggregate.Renditions = append(imageAggregate.Renditions, aggregate.ThumbnailRendition{
+			Name:        rendition.Name,
+			Key:         rendition.Key,
+			URL:         rendition.URL,
+			Width:       rendition.Width,
+			Height:      rendition.Height,
+			ContentType: rendition.ContentType,
+			Crop:        toAggregateCropBox(rendition.Crop),
+		})
+	}
+
+	return imageAggregate
+}
+
+// toAggregateProcessing はアイテムの処理状態を集約の表現に変換します
+func toAggregateProcessing(item DynamoDBImageItem) *aggregate.ThumbnailProcessing {
+	if item.ThumbnailStatus == "" {
+		return nil
+	}
+	nextRetryAt, _ := time.Parse(time.RFC3339, item.ThumbnailNextRetryAt)
+	updatedAt, _ := time.Parse(time.RFC3339, item.ThumbnailStatusUpdatedAt)
+	return &aggregate.ThumbnailProcessing{
+		Status:      item.ThumbnailStatus,
+		Attempts:    item.ThumbnailAttempts,
+		LastError:   item.ThumbnailError,
+		NextRetryAt: nextRetryAt,
+		UpdatedAt:   updatedAt,
+	}
+}
+
+// setProcessingItem は集約の処理状態をアイテムに設定します
+func setProcessingItem(item *DynamoDBImageItem, processing *aggregate.ThumbnailProcessing) {
+	if processing == nil {
+		return
+	}
+	item.ThumbnailStatus = processing.Status
+	item.ThumbnailAttempts = processing.Attempts
+	item.ThumbnailError = processing.LastError
+	if !processing.NextRetryAt.IsZero() {
+		item.ThumbnailNextRetryAt = processing.NextRetryAt.UTC().Format(time.RFC3339)
+	}
+	if !processing.UpdatedAt.IsZero() {
+		item.ThumbnailStatusUpdatedAt = processing.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+}
+
+// toAggregateCropBox は切り取り領域のアイテムを集約の表現に変換します
+func toAggregateCropBox(item *DynamoDBCropBoxItem) *aggregate.CropBox {
+	if item == nil {
+		return nil
+	}
+	return &aggregate.CropBox{X: item.X, Y: item.Y, Width: item.Width, Height: item.Height}
+}
 
-	return imageAggregate, nil
+// toCropBoxItem は集約の切り取り領域をアイテムに変換します
+func toCropBoxItem(cropBox *aggregate.CropBox) *DynamoDBCropBoxItem {
+	if cropBox == nil {
+		return nil
+	}
+	return &DynamoDBCropBoxItem{X: cropBox.X, Y: cropBox.Y, Width: cropBox.Width, Height: cropBox.Height}
 }
 
 // FindByDate は指定された日付の画像を検索します
-func (r *DynamoDBImageRepository) FindByDate(ctx context.Context, date valueobject.UploadDate) ([]*entity.Image, error) {
+func (r *DynamoDBImageRepository) FindByDate(ctx context.Context, date valueobject.UploadDate) ([]*aggregate.ImageAggregate, error) {
 	// フィルター式の作成
 	filt := expression.Name("UploadDate").Equal(expression.Value(date.String()))
 	expr, err := expression.NewBuilder().WithFilter(filt).Build()
@@ -122,39 +239,22 @@ func (r *DynamoDBImageRepository) FindByDate(ctx context.Context, date valueobje
 		return nil, fmt.Errorf("failed to scan DynamoDB: %w", err)
 	}
 
-	// 結果をエンティティに変換
-	images := make([]*entity.Image, 0)
+	// 結果を集約に変換
+	images := make([]*aggregate.ImageAggregate, 0)
 	for _, item := range result.Items {
 		var dbItem DynamoDBImageItem
 		if err := dynamodbattribute.UnmarshalMap(item, &dbItem); err != nil {
 			continue
 		}
 
-		// 値オブジェクトの作成
-		fileName, _ := valueobject.NewFileName(dbItem.FileName)
-		contentType, _ := valueobject.NewContentType(dbItem.ContentType)
-		size, _ := valueobject.NewImageSize(dbItem.Size)
-		uploadDate, _ := valueobject.NewUploadDate(dbItem.UploadDate)
-
-		image := &entity.Image{
-			ID:           dbItem.ImageID,
-			FileName:     fileName,
-			ContentType:  contentType,
-			Size:         size,
-			UploadDate:   uploadDate,
-			S3ObjectKey:  dbItem.S3ObjectKey,
-			DownloadURL:  dbItem.DownloadURL,
-			HasThumbnail: dbItem.HasThumbnail,
-		}
-
-		images = append(images, image)
+		images = append(images, toAggregate(dbItem))
 	}
 
 	return images, nil
 }
 
 // Find は条件に一致する画像を検索します
-func (r *DynamoDBImageRepository) Find(ctx context.Context, options repository.ImageQueryOptions) ([]*entity.Image, error) {
+func (r *DynamoDBImageRepository) Find(ctx context.Context, options repository.ImageQueryOptions) ([]*aggregate.ImageAggregate, error) {
 	// 検索条件の構築
 	var filterBuilder expression.ConditionBuilder
 	var filterSet bool
@@ -202,32 +302,161 @@ func (r *DynamoDBImageRepository) Find(ctx context.Context, options repository.I
 		return nil, fmt.Errorf("failed to scan DynamoDB: %w", err)
 	}
 
-	// 結果をエンティティに変換
-	images := make([]*entity.Image, 0)
+	// 結果を集約に変換
+	images := make([]*aggregate.ImageAggregate, 0)
+	for _, item := range result.Items {
+		var dbItem DynamoDBImageItem
+		if err := dynamodbattribute.UnmarshalMap(item, &dbItem); err != nil {
+			continue
+		}
+
+		images = append(images, toAggregate(dbItem))
+	}
+
+	return images, nil
+}
+
+// FindPage はアーカイブされていない画像をカーソルの位置から1ページ分検索します
+// カーソルは最後に評価した画像IDで、スキャンの再開位置として使用します
+func (r *DynamoDBImageRepository) FindPage(ctx context.Context, cursor string, limit int) (*repository.ImagePage, error) {
+	filt := expression.Name("ImageStatus").AttributeNotExists().
+		Or(expression.Name("ImageStatus").NotEqual(expression.Value("ARCHIVED")))
+	expr, err := expression.NewBuilder().WithFilter(filt).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		TableName:                 aws.String(r.metadataTableName),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+	}
+	if limit > 0 {
+		scanInput.Limit = aws.Int64(int64(limit))
+	}
+	if cursor != "" {
+		scanInput.ExclusiveStartKey = map[string]*dynamodb.AttributeValue{
+			"ImageID": {S: aws.String(cursor)},
+		}
+	}
+
+	result, err := r.client.ScanWithContext(ctx, scanInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan DynamoDB: %w", err)
+	}
+
+	// 結果を集約に変換
+	page := &repository.ImagePage{
+		Images: make([]*aggregate.ImageAggregate, 0, len(result.Items)),
+	}
 	for _, item := range result.Items {
 		var dbItem DynamoDBImageItem
 		if err := dynamodbattribute.UnmarshalMap(item, &dbItem); err != nil {
 			continue
 		}
+		page.Images = append(page.Images, toAggregate(dbItem))
+	}
+
+	// フィルターで除外された場合も含め、続きがあれば次のカーソルを返す
+	if lastKey, ok := result.LastEvaluatedKey["ImageID"]; ok && lastKey.S != nil {
+		page.NextCursor = *lastKey.S
+	}
+
+	return page, nil
+}
+
+// BatchGetItem で一度に取得できるキーの上限
+const batchGetLimit = 100
+
+// FindByIDs は指定されたIDのうちアーカイブされていない画像集約をまとめて取得します
+func (r *DynamoDBImageRepository) FindByIDs(ctx context.Context, ids []string) ([]*aggregate.ImageAggregate, error) {
+	found := make(map[string]*aggregate.ImageAggregate, len(ids))
+	for start := 0; start < len(ids); start += batchGetLimit {
+		end := min(start+batchGetLimit, len(ids))
+
+		// 重複したキーはBatchGetItemでエラーになるため除外
+		keys := make([]map[string]*dynamodb.AttributeValue, 0, end-start)
+		seen := make(map[string]bool, end-start)
+		for _, id := range ids[start:end] {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			keys = append(keys, map[string]*dynamodb.AttributeValue{
+				"ImageID": {S: aws.String(id)},
+			})
+		}
+
+		requestItems := map[string]*dynamodb.KeysAndAttributes{
+			r.metadataTableName: {Keys: keys},
+		}
+		// 未処理のキーがなくなるまで繰り返す
+		for len(requestItems) > 0 {
+			result, err := r.client.BatchGetItemWithContext(ctx, &dynamodb.BatchGetItemInput{
+				RequestItems: requestItems,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to batch get images from DynamoDB: %w", err)
+			}
+
+			for _, item := range result.Responses[r.metadataTableName] {
+				if status, ok := item["ImageStatus"]; ok && status.S != nil && *status.S == "ARCHIVED" {
+					continue
+				}
+				var dbItem DynamoDBImageItem
+				if err := dynamodbattribute.UnmarshalMap(item, &dbItem); err != nil {
+					continue
+				}
+				found[dbItem.ImageID] = toAggregate(dbItem)
+			}
+
+			requestItems = result.UnprocessedKeys
+		}
+	}
 
-		// 値オブジェクトの作成
-		fileName, _ := valueobject.NewFileName(dbItem.FileName)
-		contentType, _ := valueobject.NewContentType(dbItem.ContentType)
-		size, _ := valueobject.NewImageSize(dbItem.Size)
-		uploadDate, _ := valueobject.NewUploadDate(dbItem.UploadDate)
-
-		image := &entity.Image{
-			ID:           dbItem.ImageID,
-			FileName:     fileName,
-			ContentType:  contentType,
-			Size:         size,
-			UploadDate:   uploadDate,
-			S3ObjectKey:  dbItem.S3ObjectKey,
-			DownloadURL:  dbItem.DownloadURL,
-			HasThumbnail: dbItem.HasThumbnail,
+	// 指定されたIDの順に並べる
+	images := make([]*aggregate.ImageAggregate, 0, len(found))
+	for _, id := range ids {
+		if image, ok := found[id]; ok {
+			images = append(images, image)
+			delete(found, id)
 		}
+	}
+	return images, nil
+}
+
+// FindByOwner は指定されたユーザーが所有する画像を検索します
+func (r *DynamoDBImageRepository) FindByOwner(ctx context.Context, owner string) ([]*aggregate.ImageAggregate, error) {
+	// OwnerIndexでクエリ
+	keyCond := expression.Key("Owner").Equal(expression.Value(owner))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
 
-		images = append(images, image)
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.metadataTableName),
+		IndexName:                 aws.String("OwnerIndex"),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+
+	// ページングしながらすべての画像を取得
+	images := make([]*aggregate.ImageAggregate, 0)
+	err = r.client.QueryPagesWithContext(ctx, input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			var dbItem DynamoDBImageItem
+			if err := dynamodbattribute.UnmarshalMap(item, &dbItem); err != nil {
+				continue
+			}
+			images = append(images, toAggregate(dbItem))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query images by owner: %w", err)
 	}
 
 	return images, nil
@@ -240,20 +469,44 @@ func (r *DynamoDBImageRepository) Save(ctx context.Context, imageAggregate *aggr
 
 	// DynamoDBアイテムを作成
 	item := DynamoDBImageItem{
-		ImageID:         image.ID,
-		FileName:        image.FileName.String(),
-		ContentType:     image.ContentType.String(),
-		Size:            image.Size.Value(),
-		UploadDate:      image.UploadDate.String(),
-		S3ObjectKey:     image.S3ObjectKey,
-		DownloadURL:     image.DownloadURL,
-		ThumbnailURL:    imageAggregate.ThumbnailURL,
-		ThumbnailWidth:  imageAggregate.ThumbnailWidth,
-		ThumbnailHeight: imageAggregate.ThumbnailHeight,
-		Tags:            imageAggregate.Tags,
-		CreatedAt:       image.CreatedAt.Format(time.RFC3339),
-		ModifiedAt:      image.ModifiedAt.Format(time.RFC3339),
-		HasThumbnail:    image.HasThumbnail,
+		ImageID:            image.ID,
+		FileName:           image.FileName.String(),
+		ContentType:        image.ContentType.String(),
+		Size:               image.Size.Value(),
+		UploadDate:         image.UploadDate.String(),
+		S3ObjectKey:        image.S3ObjectKey,
+		DownloadURL:        image.DownloadURL,
+		Owner:              image.Owner,
+		ThumbnailURL:       imageAggregate.ThumbnailURL,
+		ThumbnailWidth:     imageAggregate.ThumbnailWidth,
+		ThumbnailHeight:    imageAggregate.ThumbnailHeight,
+		Tags:               imageAggregate.Tags,
+		CreatedAt:          image.CreatedAt.Format(time.RFC3339),
+		ModifiedAt:         image.ModifiedAt.Format(time.RFC3339),
+		HasThumbnail:       image.HasThumbnail,
+		PerceptualHash:     imageAggregate.PerceptualHash,
+		BlurHash:           imageAggregate.BlurHash,
+		LQIP:               imageAggregate.LQIP,
+		ThumbnailVersion:   imageAggregate.ThumbnailVersion,
+		ThumbnailWatermark: imageAggregate.ThumbnailWatermark,
+	}
+	setProcessingItem(&item, imageAggregate.Processing)
+	for _, rendition := range imageAggregate.Renditions {
+		item.Renditions = append(item.Renditions, DynamoDBRenditionItem{
+			Name:        rendition.Name,
+			Key:         rendition.Key,
+			URL:         rendition.URL,
+			Width:       rendition.Width,
+			Height:      rendition.Height,
+			ContentType: rendition.ContentType,
+			Crop:        toCropBoxItem(rendition.Crop),
+		})
+	}
+	for _, paletteColor := range imageAggregate.Palette {
+		item.Palette = append(item.Palette, DynamoDBPaletteItem{
+			Color:  paletteColor.Color,
+			Weight: paletteColor.Weight,
+		})
 	}
 
 	// DynamoDBのアイテム形式に変換
@@ -0,0 +1,177 @@
+package imagemanagement
+
+import (
+	"cloudpix/internal/domain/imagemanagement/repository"
+	"cloudpix/internal/domain/imagemanagement/valueobject"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// 候補検索で同時に実行するクエリの数
+const similarityQueryConcurrency = 8
+
+// DynamoDBSimilarityItem は知覚ハッシュのバンドインデックスのアイテム表現
+type DynamoDBSimilarityItem struct {
+	Band    string `json:"Band"`
+	ImageID string `json:"ImageID"`
+	Hash    string `json:"Hash"`
+}
+
+// DynamoDBSimilarityRepository はDynamoDBを使用した類似画像インデックスの実装
+// ハッシュをバンドに分割して各バンド値をパーティションキーとして保存することで、全件走査せずに候補を取得します
+type DynamoDBSimilarityRepository struct {
+	client              *dynamodb.DynamoDB
+	similarityTableName string
+}
+
+// NewDynamoDBSimilarityRepository は新しい類似画像インデックスのリポジトリを作成します
+func NewDynamoDBSimilarityRepository(client *dynamodb.DynamoDB, similarityTableName string) repository.SimilarityRepository {
+	return &DynamoDBSimilarityRepository{
+		client:              client,
+		similarityTableName: similarityTableName,
+	}
+}
+
+// Index は画像の知覚ハッシュを各バンドに登録します
+func (r *DynamoDBSimilarityRepository) Index(ctx context.Context, imageID string, hash valueobject.PerceptualHash) error {
+	writeRequests := make([]*dynamodb.WriteRequest, 0, valueobject.PerceptualHashBands)
+	for _, band := range hash.Bands() {
+		av, err := dynamodbattribute.MarshalMap(DynamoDBSimilarityItem{
+			Band:    band,
+			ImageID: imageID,
+			Hash:    hash.String(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal similarity item: %w", err)
+		}
+		writeRequests = append(writeRequests, &dynamodb.WriteRequest{
+			PutRequest: &dynamodb.PutRequest{Item: av},
+		})
+	}
+
+	return r.batchWrite(ctx, writeRequests)
+}
+
+// FindCandidates は指定されたバンドキーのいずれかに一致する画像を取得します
+func (r *DynamoDBSimilarityRepository) FindCandidates(ctx context.Context, bandKeys []string) ([]repository.SimilarityEntry, error) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	entries := make(map[string]repository.SimilarityEntry)
+
+	// バンドごとのクエリを並列に実行
+	semaphore := make(chan struct{}, similarityQueryConcurrency)
+	for _, bandKey := range bandKeys {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(bandKey string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			items, err := r.queryBand(ctx, bandKey)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			for _, item := range items {
+				hash, err := valueobject.ParsePerceptualHash(item.Hash)
+				if err != nil {
+					continue
+				}
+				entries[item.ImageID] = repository.SimilarityEntry{ImageID: item.ImageID, Hash: hash}
+			}
+		}(bandKey)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	result := make([]repository.SimilarityEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
+// queryBand は1つのバンドキーに登録された画像を取得します
+func (r *DynamoDBSimilarityRepository) queryBand(ctx context.Context, bandKey string) ([]DynamoDBSimilarityItem, error) {
+	keyCond := expression.Key("Band").Equal(expression.Value(bandKey))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.similarityTableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+
+	items := make([]DynamoDBSimilarityItem, 0)
+	err = r.client.QueryPagesWithContext(ctx, input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, av := range page.Items {
+			var item DynamoDBSimilarityItem
+			if err := dynamodbattribute.UnmarshalMap(av, &item); err != nil {
+				continue
+			}
+			items = append(items, item)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query similarity index: %w", err)
+	}
+
+	return items, nil
+}
+
+// Remove は画像をすべてのバンドから削除します
+func (r *DynamoDBSimilarityRepository) Remove(ctx context.Context, imageID string, hash valueobject.PerceptualHash) error {
+	writeRequests := make([]*dynamodb.WriteRequest, 0, valueobject.PerceptualHashBands)
+	for _, band := range hash.Bands() {
+		writeRequests = append(writeRequests, &dynamodb.WriteRequest{
+			DeleteRequest: &dynamodb.DeleteRequest{
+				Key: map[string]*dynamodb.AttributeValue{
+					"Band":    {S: aws.String(band)},
+					"ImageID": {S: aws.String(imageID)},
+				},
+			},
+		})
+	}
+
+	return r.batchWrite(ctx, writeRequests)
+}
+
+// batchWrite は書き込みリクエストを一括実行し、未処理のリクエストを再試行します
+func (r *DynamoDBSimilarityRepository) batchWrite(ctx context.Context, writeRequests []*dynamodb.WriteRequest) error {
+	requestItems := map[string][]*dynamodb.WriteRequest{
+		r.similarityTableName: writeRequests,
+	}
+
+	for len(requestItems) > 0 {
+		output, err := r.client.BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: requestItems,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write similarity index: %w", err)
+		}
+		requestItems = output.UnprocessedItems
+	}
+
+	return nil
+}
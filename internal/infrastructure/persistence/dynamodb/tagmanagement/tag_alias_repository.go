@@ -0,0 +1,111 @@
+package tagmanagement
+
+import (
+	"cloudpix/internal/domain/tagmanagement/repository"
+	"cloudpix/internal/domain/tagmanagement/valueobject"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// DynamoDBTagAliasItem はDynamoDBのタグの別名アイテム表現
+type DynamoDBTagAliasItem struct {
+	Alias        string `json:"Alias"` // PK
+	CanonicalTag string `json:"CanonicalTag"`
+	UpdatedAt    string `json:"UpdatedAt"`
+}
+
+// DynamoDBTagAliasRepository はDynamoDBを使用したタグの別名リポジトリの実装
+// 別名は管理者が登録する少数のアイテムのため、一覧はテーブル全体の走査で取得します
+type DynamoDBTagAliasRepository struct {
+	client              *dynamodb.DynamoDB
+	tagAliasesTableName string
+}
+
+// NewDynamoDBTagAliasRepository は新しいタグの別名リポジトリを作成します
+func NewDynamoDBTagAliasRepository(client *dynamodb.DynamoDB, tagAliasesTableName string) repository.TagAliasRepository {
+	return &DynamoDBTagAliasRepository{
+		client:              client,
+		tagAliasesTableName: tagAliasesTableName,
+	}
+}
+
+// FindAll はすべての別名を取得します
+func (r *DynamoDBTagAliasRepository) FindAll(ctx context.Context) ([]valueobject.TagAlias, error) {
+	aliases := make([]valueobject.TagAlias, 0)
+	err := r.client.ScanPagesWithContext(ctx, &dynamodb.ScanInput{
+		TableName: aws.String(r.tagAliasesTableName),
+	}, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			var aliasItem DynamoDBTagAliasItem
+			if err := dynamodbattribute.UnmarshalMap(item, &aliasItem); err != nil {
+				continue
+			}
+			alias, err := toTagAlias(aliasItem)
+			if err != nil {
+				continue // 無効な別名はスキップ
+			}
+			aliases = append(aliases, alias)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan tag aliases table: %w", err)
+	}
+
+	return aliases, nil
+}
+
+// Save は別名を保存します
+func (r *DynamoDBTagAliasRepository) Save(ctx context.Context, alias valueobject.TagAlias) error {
+	item, err := dynamodbattribute.MarshalMap(DynamoDBTagAliasItem{
+		Alias:        alias.Alias().Name(),
+		CanonicalTag: alias.Canonical().Name(),
+		UpdatedAt:    time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal tag alias: %w", err)
+	}
+
+	_, err = r.client.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tagAliasesTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save tag alias: %w", err)
+	}
+
+	return nil
+}
+
+// Delete は別名を削除します
+func (r *DynamoDBTagAliasRepository) Delete(ctx context.Context, alias valueobject.Tag) error {
+	_, err := r.client.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tagAliasesTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Alias": {S: aws.String(alias.Name())},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete tag alias: %w", err)
+	}
+
+	return nil
+}
+
+// toTagAlias はDynamoDBのアイテムを別名の値オブジェクトに変換します
+func toTagAlias(item DynamoDBTagAliasItem) (valueobject.TagAlias, error) {
+	alias, err := valueobject.NewTag(item.Alias)
+	if err != nil {
+		return valueobject.TagAlias{}, err
+	}
+	canonical, err := valueobject.NewTag(item.CanonicalTag)
+	if err != nil {
+		return valueobject.TagAlias{}, err
+	}
+	return valueobject.NewTagAlias(alias, canonical)
+}
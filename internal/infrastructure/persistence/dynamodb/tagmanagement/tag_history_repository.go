@@ -0,0 +1,274 @@
+package tagmanagement
+
+import (
+	"cloudpix/internal/domain/tagmanagement/entity"
+	"cloudpix/internal/domain/tagmanagement/repository"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/google/uuid"
+)
+
+// DynamoDBTagHistoryItem はDynamoDBのタグの変更履歴アイテム表現
+type DynamoDBTagHistoryItem struct {
+	ImageID     string   `json:"ImageID"`   // PK
+	EntryKey    string   `json:"EntryKey"`  // SK（発生時刻#ID）
+	EntryDate   string   `json:"EntryDate"` // 日付ごとの検索用のGSIのパーティションキー
+	ActorID     string   `json:"ActorID"`
+	AddedTags   []string `json:"AddedTags"`
+	RemovedTags []string `json:"RemovedTags"`
+	OccurredAt  string   `json:"OccurredAt"`
+}
+
+// 期間での検索に使用するGSI
+const tagHistoryDateIndex = "EntryDateIndex"
+
+// historyTimeLayout は文字列の順序が時刻の順序と一致する固定長の時刻形式（UTC）
+const historyTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// historyDateLayout はGSIのパーティションキーに使用する日付の形式（UTC）
+const historyDateLayout = "2006-01-02"
+
+// entryKeySeparator は履歴エントリのキーの発生時刻とIDを区切る文字
+const entryKeySeparator = "#"
+
+// historyCursorSeparator は期間での検索の再開位置でエントリのキーと画像IDを区切る文字
+const historyCursorSeparator = "/"
+
+// DynamoDBTagHistoryRepository はDynamoDBを使用したタグの変更履歴リポジトリの実装
+// 画像ごとの履歴はテーブルのキー、期間での検索は日付ごとのGSIで新しい順に取得します
+type DynamoDBTagHistoryRepository struct {
+	client              *dynamodb.DynamoDB
+	tagHistoryTableName string
+}
+
+// NewDynamoDBTagHistoryRepository は新しいタグの変更履歴リポジトリを作成します
+func NewDynamoDBTagHistoryRepository(client *dynamodb.DynamoDB, tagHistoryTableName string) repository.TagHistoryRepository {
+	return &DynamoDBTagHistoryRepository{
+		client:              client,
+		tagHistoryTableName: tagHistoryTableName,
+	}
+}
+
+// Save は変更履歴エントリを保存します
+func (r *DynamoDBTagHistoryRepository) Save(ctx context.Context, entry *entity.TagHistoryEntry) error {
+	occurredAt := entry.OccurredAt.UTC()
+	item, err := dynamodbattribute.MarshalMap(DynamoDBTagHistoryItem{
+		ImageID:     entry.ImageID,
+		EntryKey:    occurredAt.Format(historyTimeLayout) + entryKeySeparator + uuid.New().String(),
+		EntryDate:   occurredAt.Format(historyDateLayout),
+		ActorID:     entry.ActorID,
+		AddedTags:   entry.AddedTags,
+		RemovedTags: entry.RemovedTags,
+		OccurredAt:  occurredAt.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal tag history entry: %w", err)
+	}
+
+	_, err = r.client.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tagHistoryTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save tag history entry: %w", err)
+	}
+
+	return nil
+}
+
+// FindByImage は画像のタグの変更履歴を新しい順にカーソルの位置から1ページ分取得します
+// カーソルは最後に返したエントリのキーです
+func (r *DynamoDBTagHistoryRepository) FindByImage(ctx context.Context, imageID string, cursor string, limit int) (*repository.TagHistoryPage, error) {
+	keyCondition := expression.Key("ImageID").Equal(expression.Value(imageID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tagHistoryTableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int64(int64(limit)),
+	}
+	if cursor != "" {
+		if _, ok := entryDate(cursor); !ok {
+			return nil, repository.ErrInvalidHistoryCursor
+		}
+		queryInput.ExclusiveStartKey = map[string]*dynamodb.AttributeValue{
+			"ImageID":  {S: aws.String(imageID)},
+			"EntryKey": {S: aws.String(cursor)},
+		}
+	}
+
+	page := &repository.TagHistoryPage{
+		Entries: make([]*entity.TagHistoryEntry, 0),
+	}
+	if err := r.queryEntries(ctx, queryInput, page, limit, func(item DynamoDBTagHistoryItem) string {
+		return item.EntryKey
+	}); err != nil {
+		return nil, fmt.Errorf("failed to query tag history by image: %w", err)
+	}
+
+	return page, nil
+}
+
+// FindByTimeRange は期間内のすべての画像のタグの変更履歴を新しい順にカーソルの位置から1ページ分取得します
+// 期間の終了日から開始日に向かって日付ごとにGSIを検索し、カーソルは最後に返したエントリのキーと画像IDです
+func (r *DynamoDBTagHistoryRepository) FindByTimeRange(ctx context.Context, query repository.TagHistoryQuery) (*repository.TagHistoryPage, error) {
+	fromKey := query.From.UTC().Format(historyTimeLayout)
+	// 終了時刻と同時刻のエントリも含めるため、IDに使われる文字より後ろの文字を付ける
+	toKey := query.To.UTC().Format(historyTimeLayout) + entryKeySeparator + "~"
+
+	firstDay := query.From.UTC().Format(historyDateLayout)
+	day := query.To.UTC().Format(historyDateLayout)
+	var startKey map[string]*dynamodb.AttributeValue
+	if query.Cursor != "" {
+		entryKey, imageID, ok := strings.Cut(query.Cursor, historyCursorSeparator)
+		if !ok || imageID == "" {
+			return nil, repository.ErrInvalidHistoryCursor
+		}
+		cursorDay, ok := entryDate(entryKey)
+		if !ok || cursorDay < firstDay || cursorDay > day {
+			return nil, repository.ErrInvalidHistoryCursor
+		}
+		day = cursorDay
+		startKey = map[string]*dynamodb.AttributeValue{
+			"EntryDate": {S: aws.String(cursorDay)},
+			"EntryKey":  {S: aws.String(entryKey)},
+			"ImageID":   {S: aws.String(imageID)},
+		}
+	}
+
+	page := &repository.TagHistoryPage{
+		Entries: make([]*entity.TagHistoryEntry, 0),
+	}
+	for day >= firstDay {
+		queryInput, err := r.timeRangeQueryInput(day, fromKey, toKey, query)
+		if err != nil {
+			return nil, err
+		}
+		queryInput.ExclusiveStartKey = startKey
+
+		if err := r.queryEntries(ctx, queryInput, page, query.Limit, func(item DynamoDBTagHistoryItem) string {
+			return item.EntryKey + historyCursorSeparator + item.ImageID
+		}); err != nil {
+			return nil, fmt.Errorf("failed to query tag history by date: %w", err)
+		}
+		if page.NextCursor != "" {
+			break
+		}
+
+		// 前の日付へ
+		date, _ := time.Parse(historyDateLayout, day)
+		day = date.AddDate(0, 0, -1).Format(historyDateLayout)
+		startKey = nil
+	}
+
+	return page, nil
+}
+
+// timeRangeQueryInput は1日分の期間での検索のクエリを作成します
+func (r *DynamoDBTagHistoryRepository) timeRangeQueryInput(day, fromKey, toKey string, query repository.TagHistoryQuery) (*dynamodb.QueryInput, error) {
+	keyCondition := expression.Key("EntryDate").Equal(expression.Value(day)).
+		And(expression.Key("EntryKey").Between(expression.Value(fromKey), expression.Value(toKey)))
+	builder := expression.NewBuilder().WithKeyCondition(keyCondition)
+
+	// タグ・ユーザーでの絞り込み
+	var filter expression.ConditionBuilder
+	filtered := false
+	if query.TagName != "" {
+		filter = expression.Contains(expression.Name("AddedTags"), query.TagName).
+			Or(expression.Contains(expression.Name("RemovedTags"), query.TagName))
+		filtered = true
+	}
+	if query.ActorID != "" {
+		actorFilter := expression.Name("ActorID").Equal(expression.Value(query.ActorID))
+		if filtered {
+			filter = filter.And(actorFilter)
+		} else {
+			filter = actorFilter
+		}
+		filtered = true
+	}
+	if filtered {
+		builder = builder.WithFilter(filter)
+	}
+
+	expr, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	return &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tagHistoryTableName),
+		IndexName:                 aws.String(tagHistoryDateIndex),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int64(int64(query.Limit)),
+	}, nil
+}
+
+// queryEntries はクエリの結果をページに追加し、件数が limit に達した場合は最後のエントリの再開位置を設定して終了します
+func (r *DynamoDBTagHistoryRepository) queryEntries(
+	ctx context.Context,
+	queryInput *dynamodb.QueryInput,
+	page *repository.TagHistoryPage,
+	limit int,
+	cursorOf func(item DynamoDBTagHistoryItem) string,
+) error {
+	var unmarshalErr error
+	err := r.client.QueryPagesWithContext(ctx, queryInput, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range output.Items {
+			var historyItem DynamoDBTagHistoryItem
+			if err := dynamodbattribute.UnmarshalMap(item, &historyItem); err != nil {
+				unmarshalErr = fmt.Errorf("failed to unmarshal tag history entry: %w", err)
+				return false
+			}
+			page.Entries = append(page.Entries, toTagHistoryEntry(historyItem))
+			if len(page.Entries) >= limit {
+				page.NextCursor = cursorOf(historyItem)
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return unmarshalErr
+}
+
+// toTagHistoryEntry はDynamoDBのアイテムを変更履歴エントリに変換します
+func toTagHistoryEntry(item DynamoDBTagHistoryItem) *entity.TagHistoryEntry {
+	occurredAt, err := time.Parse(time.RFC3339Nano, item.OccurredAt)
+	if err != nil {
+		occurredAt, _ = time.Parse(historyTimeLayout, strings.SplitN(item.EntryKey, entryKeySeparator, 2)[0])
+	}
+	return entity.NewTagHistoryEntry(item.ImageID, item.ActorID, item.AddedTags, item.RemovedTags, occurredAt)
+}
+
+// entryDate はエントリのキーから発生日を取得します（キーの形式が不正な場合は false）
+func entryDate(entryKey string) (string, bool) {
+	occurredAt, _, ok := strings.Cut(entryKey, entryKeySeparator)
+	if !ok {
+		return "", false
+	}
+	t, err := time.Parse(historyTimeLayout, occurredAt)
+	if err != nil {
+		return "", false
+	}
+	return t.Format(historyDateLayout), true
+}
@@ -5,7 +5,10 @@ import (
 	"cloudpix/internal/domain/tagmanagement/repository"
 	"cloudpix/internal/domain/tagmanagement/valueobject"
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -29,11 +32,28 @@ type DynamoDBTaggedImageItem struct {
 	UpdatedAt string   `json:"UpdatedAt"`
 }
 
+// BatchGetItem で一度に取得できるキーの上限
+const batchGetLimit = 100
+
+// TransactWriteItems で一度に書き込めるアイテムの上限
+const maxTransactItems = 100
+
+// 同時更新による競合で保存に失敗した場合に、既存のタグを取得し直して保存する最大回数
+const maxSaveAttempts = 3
+
+// 競合後に既存のタグを取得し直すまでの待機時間（試行ごとに倍にする）
+const saveRetryInterval = 200 * time.Millisecond
+
+// タグ属性を持たない画像のタグを個別に取得する際の同時実行数
+const legacyTagQueryConcurrency = 8
+
 // DynamoDBTagRepository はDynamoDBを使用したタグリポジトリの実装
+// タグテーブルに加えて、一覧取得のために画像メタデータアイテムの Tags 属性にもタグの一覧を保存します
 type DynamoDBTagRepository struct {
-	client            *dynamodb.DynamoDB
-	tagsTableName     string
-	metadataTableName string
+	client             *dynamodb.DynamoDB
+	tagsTableName      string
+	metadataTableName  string
+	tagCountsTableName string
 }
 
 // NewDynamoDBTagRepository は新しいDynamoDBタグリポジトリを作成します
@@ -41,11 +61,13 @@ func NewDynamoDBTagRepository(
 	client *dynamodb.DynamoDB,
 	tagsTableName string,
 	metadataTableName string,
+	tagCountsTableName string,
 ) repository.TagRepository {
 	return &DynamoDBTagRepository{
-		client:            client,
-		tagsTableName:     tagsTableName,
-		metadataTableName: metadataTableName,
+		client:             client,
+		tagsTableName:      tagsTableName,
+		metadataTableName:  metadataTableName,
+		tagCountsTableName: tagCountsTableName,
 	}
 }
 
@@ -134,6 +156,163 @@ func (r *DynamoDBTagRepository) FindTaggedImage(ctx context.Context, imageID str
 	return taggedImage, nil
 }
 
+// FindTagsByImageIDs は複数の画像のタグをまとめて取得し、画像IDごとのタグを返します
+// メタデータアイテムの Tags 属性をバッチで読み取り、属性を持たない以前の画像のみタグテーブルを個別に検索します
+func (r *DynamoDBTagRepository) FindTagsByImageIDs(ctx context.Context, imageIDs []string) (map[string][]valueobject.Tag, error) {
+	tagsByImage := make(map[string][]valueobject.Tag)
+	legacyImageIDs := make([]string, 0)
+
+	// 重複を除去してから上限ごとにバッチ取得
+	uniqueIDs := uniqueStrings(imageIDs)
+	for start := 0; start < len(uniqueIDs); start += batchGetLimit {
+		end := min(start+batchGetLimit, len(uniqueIDs))
+		found, legacy, err := r.batchGetTags(ctx, uniqueIDs[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for imageID, tags := range found {
+			tagsByImage[imageID] = tags
+		}
+		legacyImageIDs = append(legacyImageIDs, legacy...)
+	}
+
+	// Tags 属性を持たない画像はタグテーブルから取得
+	legacyTags, err := r.queryLegacyTags(ctx, legacyImageIDs)
+	if err != nil {
+		return nil, err
+	}
+	for imageID, tags := range legacyTags {
+		tagsByImage[imageID] = tags
+	}
+
+	return tagsByImage, nil
+}
+
+// batchGetTags はメタデータアイテムの Tags 属性をバッチで取得します
+// Tags 属性を持たないアイテムの画像IDは legacy として返します
+func (r *DynamoDBTagRepository) batchGetTags(ctx context.Context, imageIDs []string) (map[string][]valueobject.Tag, []string, error) {
+	keys := make([]map[string]*dynamodb.AttributeValue, len(imageIDs))
+	for i, imageID := range imageIDs {
+		keys[i] = map[string]*dynamodb.AttributeValue{
+			"ImageID": {S: aws.String(imageID)},
+		}
+	}
+
+	requestItems := map[string]*dynamodb.KeysAndAttributes{
+		r.metadataTableName: {
+			Keys:                     keys,
+			ProjectionExpression:     aws.String("#id, #tags"),
+			ExpressionAttributeNames: map[string]*string{"#id": aws.String("ImageID"), "#tags": aws.String("Tags")},
+		},
+	}
+
+	tagsByImage := make(map[string][]valueobject.Tag)
+	legacy := make([]string, 0)
+	// 未処理のキーがなくなるまで繰り返す
+	for len(requestItems) > 0 {
+		result, err := r.client.BatchGetItemWithContext(ctx, &dynamodb.BatchGetItemInput{
+			RequestItems: requestItems,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to batch get image tags: %w", err)
+		}
+
+		for _, item := range result.Responses[r.metadataTableName] {
+			imageIDAttr, ok := item["ImageID"]
+			if !ok || imageIDAttr.S == nil {
+				continue
+			}
+			tagsAttr, ok := item["Tags"]
+			if !ok {
+				legacy = append(legacy, *imageIDAttr.S)
+				continue
+			}
+
+			var tagNames []string
+			if err := dynamodbattribute.Unmarshal(tagsAttr, &tagNames); err != nil {
+				continue
+			}
+			if tags := toTags(tagNames); len(tags) > 0 {
+				tagsByImage[*imageIDAttr.S] = tags
+			}
+		}
+
+		requestItems = result.UnprocessedKeys
+	}
+
+	return tagsByImage, legacy, nil
+}
+
+// queryLegacyTags は Tags 属性を持たない画像のタグを同時実行数を制限してタグテーブルから取得します
+func (r *DynamoDBTagRepository) queryLegacyTags(ctx context.Context, imageIDs []string) (map[string][]valueobject.Tag, error) {
+	tagsByImage := make(map[string][]valueobject.Tag)
+	if len(imageIDs) == 0 {
+		return tagsByImage, nil
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	semaphore := make(chan struct{}, legacyTagQueryConcurrency)
+	for _, imageID := range imageIDs {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(imageID string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			taggedImage, err := r.FindTaggedImage(ctx, imageID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			if taggedImage != nil && len(taggedImage.Tags) > 0 {
+				tagsByImage[imageID] = taggedImage.Tags
+			}
+		}(imageID)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return tagsByImage, nil
+}
+
+// toTags はタグ名をタグの値オブジェクトに変換します（無効なタグは除外）
+func toTags(tagNames []string) []valueobject.Tag {
+	tags := make([]valueobject.Tag, 0, len(tagNames))
+	for _, tagName := range tagNames {
+		tag, err := valueobject.NewTag(tagName)
+		if err != nil {
+			continue // 無効なタグはスキップ
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// uniqueStrings は順序を維持したまま重複を除去します
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		unique = append(unique, value)
+	}
+	return unique
+}
+
 // FindImagesByTag は指定されたタグを持つ画像IDのリストを取得します
 func (r *DynamoDBTagRepository) FindImagesByTag(ctx context.Context, tag valueobject.Tag) ([]string, error) {
 	// タグ名で検索するためのキー条件を作成
@@ -148,6 +327,48 @@ func (r *DynamoDBTagRepository) FindImagesByTag(ctx context.Context, tag valueob
 		KeyConditionExpression:    expr.KeyCondition(),
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
+		ProjectionExpression:      aws.String("ImageID"),
+	}
+
+	// 1MBを超える結果にも対応するため、全ページを取得して画像IDのリストを抽出
+	imageIDs := make([]string, 0)
+	err = r.client.QueryPagesWithContext(ctx, queryInput, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			if imageIDAttr, ok := item["ImageID"]; ok && imageIDAttr.S != nil {
+				imageIDs = append(imageIDs, *imageIDAttr.S)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags by tag name: %w", err)
+	}
+
+	return imageIDs, nil
+}
+
+// FindImagesByTagPage は指定されたタグを持つ画像IDを画像ID順にカーソルの位置から1ページ分検索します
+// カーソルは前のページの最後の画像IDで、カーソルより前の画像のタグが変更されても続きから検索できます
+func (r *DynamoDBTagRepository) FindImagesByTagPage(ctx context.Context, tag valueobject.Tag, cursor string, limit int) (*repository.TaggedImagePage, error) {
+	keyCondition := expression.Key("TagName").Equal(expression.Value(tag.Name()))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tagsTableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ProjectionExpression:      aws.String("ImageID"),
+		Limit:                     aws.Int64(int64(limit)),
+	}
+	if cursor != "" {
+		queryInput.ExclusiveStartKey = map[string]*dynamodb.AttributeValue{
+			"TagName": {S: aws.String(tag.Name())},
+			"ImageID": {S: aws.String(cursor)},
+		}
 	}
 
 	result, err := r.client.QueryWithContext(ctx, queryInput)
@@ -155,19 +376,65 @@ func (r *DynamoDBTagRepository) FindImagesByTag(ctx context.Context, tag valueob
 		return nil, fmt.Errorf("failed to query tags by tag name: %w", err)
 	}
 
-	// 画像IDのリストを抽出
-	imageIDs := make([]string, 0, len(result.Items))
+	page := &repository.TaggedImagePage{
+		ImageIDs: make([]string, 0, len(result.Items)),
+	}
 	for _, item := range result.Items {
 		if imageIDAttr, ok := item["ImageID"]; ok && imageIDAttr.S != nil {
-			imageIDs = append(imageIDs, *imageIDAttr.S)
+			page.ImageIDs = append(page.ImageIDs, *imageIDAttr.S)
 		}
 	}
+	if lastKey, ok := result.LastEvaluatedKey["ImageID"]; ok && lastKey.S != nil {
+		page.NextCursor = *lastKey.S
+	}
 
-	return imageIDs, nil
+	return page, nil
 }
 
 // Save はタグ付き画像情報を保存します
+// タグアイテムの追加・削除とタグの利用数の増減を同じトランザクションで実行します
+// 既存のタグは結果整合性のインデックスから取得するため、書き込みの条件に失敗した場合は取得し直して再試行します
 func (r *DynamoDBTagRepository) Save(ctx context.Context, taggedImage *entity.TaggedImage) error {
+	var err error
+	for attempt := 0; attempt < maxSaveAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(saveRetryInterval << (attempt - 1)):
+			}
+		}
+
+		err = r.save(ctx, taggedImage)
+		if !isConditionalCheckFailed(err) {
+			return err
+		}
+
+		// 画像が削除された場合は再試行しても成功しない
+		exists, existsErr := r.ImageExists(ctx, taggedImage.ImageID)
+		if existsErr != nil || !exists {
+			return err
+		}
+	}
+	return err
+}
+
+// isConditionalCheckFailed はトランザクションが書き込みの条件の失敗により取り消されたかを判定します
+func isConditionalCheckFailed(err error) bool {
+	var canceled *dynamodb.TransactionCanceledException
+	if !errors.As(err, &canceled) {
+		return false
+	}
+	for _, reason := range canceled.CancellationReasons {
+		if reason != nil && aws.StringValue(reason.Code) == "ConditionalCheckFailed" {
+			return true
+		}
+	}
+	return false
+}
+
+// save は既存のタグとの差分をトランザクションで書き込みます
+func (r *DynamoDBTagRepository) save(ctx context.Context, taggedImage *entity.TaggedImage) error {
 	// 既存のタグを一度取得
 	existingTagged, err := r.FindTaggedImage(ctx, taggedImage.ImageID)
 	if err != nil {
@@ -188,13 +455,19 @@ func (r *DynamoDBTagRepository) Save(ctx context.Context, taggedImage *entity.Ta
 		newTagMap[tag.Name()] = true
 	}
 
-	// DynamoDBのトランザクションアイテムを準備
-	var transactItems []*dynamodb.TransactWriteItem
+	// 利用数を数える範囲（全体と画像の所有者）
+	scopes, _, err := r.usageScopes(ctx, taggedImage.ImageID)
+	if err != nil {
+		return err
+	}
+
+	// DynamoDBのトランザクションアイテムをタグごとに準備
+	var changes [][]*dynamodb.TransactWriteItem
 
 	// 1. 削除されたタグを削除
 	for tagName := range existingTagMap {
 		if !newTagMap[tagName] {
-			// このタグは削除する必要がある
+			// 同時に削除された場合に利用数を二重に減らさないよう、存在する場合のみ削除
 			deleteItem := &dynamodb.TransactWriteItem{
 				Delete: &dynamodb.Delete{
 					TableName: aws.String(r.tagsTableName),
@@ -202,9 +475,10 @@ func (r *DynamoDBTagRepository) Save(ctx context.Context, taggedImage *entity.Ta
 						"TagName": {S: aws.String(tagName)},
 						"ImageID": {S: aws.String(taggedImage.ImageID)},
 					},
+					ConditionExpression: aws.String("attribute_exists(TagName)"),
 				},
 			}
-			transactItems = append(transactItems, deleteItem)
+			changes = append(changes, append([]*dynamodb.TransactWriteItem{deleteItem}, r.usageUpdates(tagName, scopes, -1)...))
 		}
 	}
 
@@ -223,33 +497,98 @@ func (r *DynamoDBTagRepository) Save(ctx context.Context, taggedImage *entity.Ta
 				return fmt.Errorf("failed to marshal tag item: %w", err)
 			}
 
+			// 同時に追加された場合に利用数を二重に増やさないよう、存在しない場合のみ追加
 			putItem := &dynamodb.TransactWriteItem{
 				Put: &dynamodb.Put{
-					TableName: aws.String(r.tagsTableName),
-					Item:      av,
+					TableName:           aws.String(r.tagsTableName),
+					Item:                av,
+					ConditionExpression: aws.String("attribute_not_exists(TagName)"),
 				},
 			}
-			transactItems = append(transactItems, putItem)
+			changes = append(changes, append([]*dynamodb.TransactWriteItem{putItem}, r.usageUpdates(tagName, scopes, 1)...))
 		}
 	}
 
 	// トランザクションアイテムがない場合は処理しない
-	if len(transactItems) == 0 {
+	if len(changes) == 0 {
 		return nil
 	}
 
-	// トランザクションを実行
-	_, err = r.client.TransactWriteItemsWithContext(ctx, &dynamodb.TransactWriteItemsInput{
-		TransactItems: transactItems,
-	})
+	// 3. 一覧取得のためにメタデータアイテムのタグ一覧を更新（画像が存在しない場合はトランザクション全体を取り消す）
+	metadataUpdate, err := r.metadataTagsUpdate(taggedImage.ImageID, newTagMap)
+	if err != nil {
+		return err
+	}
+
+	return r.transactChanges(ctx, changes, metadataUpdate)
+}
+
+// transactChanges はタグごとの変更をトランザクションの上限に収まるようにまとめて実行します
+// タグアイテムと利用数は常に同じトランザクションで更新し、メタデータの更新は最後のトランザクションに含めます
+// 途中で失敗した場合も、再度保存すればタグテーブルとの差分から残りの変更が適用されます
+func (r *DynamoDBTagRepository) transactChanges(ctx context.Context, changes [][]*dynamodb.TransactWriteItem, last *dynamodb.TransactWriteItem) error {
+	var batch []*dynamodb.TransactWriteItem
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		_, err := r.client.TransactWriteItemsWithContext(ctx, &dynamodb.TransactWriteItemsInput{
+			TransactItems: batch,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to execute transaction: %w", err)
+		}
+		batch = nil
+		return nil
+	}
+
+	for _, change := range changes {
+		// 最後のメタデータ更新の分を空けておく
+		if len(batch)+len(change)+1 > maxTransactItems {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		batch = append(batch, change...)
+	}
+	if last != nil {
+		batch = append(batch, last)
+	}
+
+	return flush()
+}
+
+// metadataTagsUpdate はメタデータアイテムの Tags 属性を更新するトランザクションアイテムを作成します
+func (r *DynamoDBTagRepository) metadataTagsUpdate(imageID string, tagMap map[string]bool) (*dynamodb.TransactWriteItem, error) {
+	tagNames := make([]string, 0, len(tagMap))
+	for tagName := range tagMap {
+		tagNames = append(tagNames, tagName)
+	}
+	sort.Strings(tagNames)
+
+	update := expression.Set(expression.Name("Tags"), expression.Value(tagNames))
+	condition := expression.AttributeExists(expression.Name("ImageID"))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
 	if err != nil {
-		return fmt.Errorf("failed to execute transaction: %w", err)
+		return nil, fmt.Errorf("failed to build expression: %w", err)
 	}
 
-	return nil
+	return &dynamodb.TransactWriteItem{
+		Update: &dynamodb.Update{
+			TableName: aws.String(r.metadataTableName),
+			Key: map[string]*dynamodb.AttributeValue{
+				"ImageID": {S: aws.String(imageID)},
+			},
+			UpdateExpression:          expr.Update(),
+			ConditionExpression:       expr.Condition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		},
+	}, nil
 }
 
 // Delete はタグ付き画像情報を削除します
+// メタデータアイテムを削除する前に呼び出す必要があります（利用数を減らす範囲の特定に画像の所有者を使用するため）
 func (r *DynamoDBTagRepository) Delete(ctx context.Context, imageID string) error {
 	// 特定の画像IDに関連するタグを取得
 	existingTagged, err := r.FindTaggedImage(ctx, imageID)
@@ -262,42 +601,37 @@ func (r *DynamoDBTagRepository) Delete(ctx context.Context, imageID string) erro
 		return nil
 	}
 
-	// DynamoDBのバッチ書き込みアイテムを準備
-	var writeRequests []*dynamodb.WriteRequest
+	scopes, metadataExists, err := r.usageScopes(ctx, imageID)
+	if err != nil {
+		return err
+	}
 
-	// 各タグの削除リクエストを作成
+	// 各タグの削除と利用数の減少をタグごとにまとめる
+	changes := make([][]*dynamodb.TransactWriteItem, 0, len(existingTagged.Tags))
 	for _, tag := range existingTagged.Tags {
-		deleteRequest := &dynamodb.WriteRequest{
-			DeleteRequest: &dynamodb.DeleteRequest{
+		deleteItem := &dynamodb.TransactWriteItem{
+			Delete: &dynamodb.Delete{
+				TableName: aws.String(r.tagsTableName),
 				Key: map[string]*dynamodb.AttributeValue{
 					"TagName": {S: aws.String(tag.Name())},
 					"ImageID": {S: aws.String(imageID)},
 				},
+				ConditionExpression: aws.String("attribute_exists(TagName)"),
 			},
 		}
-		writeRequests = append(writeRequests, deleteRequest)
+		changes = append(changes, append([]*dynamodb.TransactWriteItem{deleteItem}, r.usageUpdates(tag.Name(), scopes, -1)...))
 	}
 
-	// バッチ削除実行（最大25アイテムずつ）
-	const batchSize = 25
-	for i := 0; i < len(writeRequests); i += batchSize {
-		end := i + batchSize
-		if end > len(writeRequests) {
-			end = len(writeRequests)
-		}
-
-		batch := writeRequests[i:end]
-		_, err := r.client.BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{
-			RequestItems: map[string][]*dynamodb.WriteRequest{
-				r.tagsTableName: batch,
-			},
-		})
+	// メタデータアイテムのタグ一覧も空にする（削除済みの画像は対象外）
+	var metadataUpdate *dynamodb.TransactWriteItem
+	if metadataExists {
+		metadataUpdate, err = r.metadataTagsUpdate(imageID, map[string]bool{})
 		if err != nil {
-			return fmt.Errorf("failed to batch delete tags: %w", err)
+			return err
 		}
 	}
 
-	return nil
+	return r.transactChanges(ctx, changes, metadataUpdate)
 }
 
 // ImageExists は画像が存在するか確認します
@@ -0,0 +1,302 @@
+package tagmanagement
+
+import (
+	"cloudpix/internal/domain/tagmanagement/valueobject"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// タグの利用数を数える範囲
+const (
+	globalUsageScope     = "global"
+	userUsageScopePrefix = "user:"
+)
+
+// BatchWriteItem で一度に書き込めるアイテムの上限
+const batchWriteLimit = 25
+
+// DynamoDBTagCountItem はDynamoDBのタグ利用数アイテム表現
+type DynamoDBTagCountItem struct {
+	Scope      string `json:"Scope"`   // PK（global または user:{ユーザーID}）
+	TagName    string `json:"TagName"` // SK
+	ImageCount int    `json:"ImageCount"`
+	UpdatedAt  string `json:"UpdatedAt"`
+}
+
+// usageScope は所有者に対応する利用数の範囲を返します（空の場合は全体）
+func usageScope(owner string) string {
+	if owner == "" {
+		return globalUsageScope
+	}
+	return userUsageScopePrefix + owner
+}
+
+// usageScopes は画像のタグの利用数を数える範囲（全体と画像の所有者）と、メタデータアイテムが存在するかを返します
+func (r *DynamoDBTagRepository) usageScopes(ctx context.Context, imageID string) ([]string, bool, error) {
+	result, err := r.client.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.metadataTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ImageID": {S: aws.String(imageID)},
+		},
+		ProjectionExpression: aws.String("#owner"),
+		ExpressionAttributeNames: map[string]*string{
+			"#owner": aws.String("Owner"),
+		},
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get image owner: %w", err)
+	}
+
+	scopes := []string{globalUsageScope}
+	if owner, ok := result.Item["Owner"]; ok && owner.S != nil && *owner.S != "" {
+		scopes = append(scopes, usageScope(*owner.S))
+	}
+	return scopes, result.Item != nil, nil
+}
+
+// usageUpdates はタグの利用数を各範囲で増減するトランザクションアイテムを作成します
+func (r *DynamoDBTagRepository) usageUpdates(tagName string, scopes []string, delta int) []*dynamodb.TransactWriteItem {
+	now := time.Now().Format(time.RFC3339)
+	updates := make([]*dynamodb.TransactWriteItem, 0, len(scopes))
+	for _, scope := range scopes {
+		updates = append(updates, &dynamodb.TransactWriteItem{
+			Update: &dynamodb.Update{
+				TableName: aws.String(r.tagCountsTableName),
+				Key: map[string]*dynamodb.AttributeValue{
+					"Scope":   {S: aws.String(scope)},
+					"TagName": {S: aws.String(tagName)},
+				},
+				UpdateExpression: aws.String("ADD ImageCount :delta SET UpdatedAt = :now"),
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":delta": {N: aws.String(strconv.Itoa(delta))},
+					":now":   {S: aws.String(now)},
+				},
+			},
+		})
+	}
+	return updates
+}
+
+// FindTagUsage は画像が1枚以上付けられているタグとその利用数を取得します
+func (r *DynamoDBTagRepository) FindTagUsage(ctx context.Context, owner string) ([]valueobject.TagUsage, error) {
+	keyCondition := expression.Key("Scope").Equal(expression.Value(usageScope(owner)))
+	return r.queryTagUsage(ctx, keyCondition)
+}
+
+// FindTagUsageByPrefix は名前が前方一致するタグとその利用数を取得します
+// 利用数テーブルはタグ名をソートキーに持つため、前方一致はキー条件で絞り込めます
+func (r *DynamoDBTagRepository) FindTagUsageByPrefix(ctx context.Context, owner string, prefix valueobject.TagPrefix) ([]valueobject.TagUsage, error) {
+	keyCondition := expression.Key("Scope").Equal(expression.Value(usageScope(owner))).
+		And(expression.Key("TagName").BeginsWith(prefix.Value()))
+	return r.queryTagUsage(ctx, keyCondition)
+}
+
+// queryTagUsage は利用数テーブルをキー条件で検索し、画像が1枚以上付けられているタグの利用数を返します
+func (r *DynamoDBTagRepository) queryTagUsage(ctx context.Context, keyCondition expression.KeyConditionBuilder) ([]valueobject.TagUsage, error) {
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tagCountsTableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+
+	usages := make([]valueobject.TagUsage, 0)
+	err = r.client.QueryPagesWithContext(ctx, queryInput, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			var countItem DynamoDBTagCountItem
+			if err := dynamodbattribute.UnmarshalMap(item, &countItem); err != nil {
+				continue
+			}
+			// すべての画像から外されたタグは含めない
+			if countItem.ImageCount <= 0 {
+				continue
+			}
+			tag, err := valueobject.NewTag(countItem.TagName)
+			if err != nil {
+				continue // 無効なタグはスキップ
+			}
+			usages = append(usages, valueobject.NewTagUsage(tag, countItem.ImageCount))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tag counts: %w", err)
+	}
+
+	return usages, nil
+}
+
+// usageKey はタグの利用数アイテムのキーを表します
+type usageKey struct {
+	scope   string
+	tagName string
+}
+
+// RebuildTagUsage はタグテーブル全体からタグの利用数を数え直し、更新した利用数の件数を返します
+// 導入前から存在するタグの利用数の作成や、不整合の修正に使用します（実行中のタグの変更は反映されない場合があります）
+func (r *DynamoDBTagRepository) RebuildTagUsage(ctx context.Context) (int, error) {
+	// タグテーブルから画像ごとのタグを取得
+	tagsByImage := make(map[string][]string)
+	err := r.client.ScanPagesWithContext(ctx, &dynamodb.ScanInput{
+		TableName:            aws.String(r.tagsTableName),
+		ProjectionExpression: aws.String("TagName, ImageID"),
+	}, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			tagName, imageID := item["TagName"], item["ImageID"]
+			if tagName == nil || tagName.S == nil || imageID == nil || imageID.S == nil {
+				continue
+			}
+			tagsByImage[*imageID.S] = append(tagsByImage[*imageID.S], *tagName.S)
+		}
+		return true
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan tags table: %w", err)
+	}
+
+	// 画像の所有者をまとめて取得
+	imageIDs := make([]string, 0, len(tagsByImage))
+	for imageID := range tagsByImage {
+		imageIDs = append(imageIDs, imageID)
+	}
+	owners, err := r.batchGetOwners(ctx, imageIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	// 範囲ごとに利用数を数える
+	counts := make(map[usageKey]int)
+	for imageID, tagNames := range tagsByImage {
+		for _, tagName := range tagNames {
+			counts[usageKey{scope: globalUsageScope, tagName: tagName}]++
+			if owner := owners[imageID]; owner != "" {
+				counts[usageKey{scope: usageScope(owner), tagName: tagName}]++
+			}
+		}
+	}
+
+	// 数え直した結果にない既存の利用数は削除する
+	var writeRequests []*dynamodb.WriteRequest
+	err = r.client.ScanPagesWithContext(ctx, &dynamodb.ScanInput{
+		TableName:                aws.String(r.tagCountsTableName),
+		ProjectionExpression:     aws.String("#scope, TagName"),
+		ExpressionAttributeNames: map[string]*string{"#scope": aws.String("Scope")},
+	}, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			scope, tagName := item["Scope"], item["TagName"]
+			if scope == nil || scope.S == nil || tagName == nil || tagName.S == nil {
+				continue
+			}
+			if _, ok := counts[usageKey{scope: *scope.S, tagName: *tagName.S}]; ok {
+				continue
+			}
+			writeRequests = append(writeRequests, &dynamodb.WriteRequest{
+				DeleteRequest: &dynamodb.DeleteRequest{
+					Key: map[string]*dynamodb.AttributeValue{
+						"Scope":   scope,
+						"TagName": tagName,
+					},
+				},
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan tag counts table: %w", err)
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	for key, count := range counts {
+		av, err := dynamodbattribute.MarshalMap(DynamoDBTagCountItem{
+			Scope:      key.scope,
+			TagName:    key.tagName,
+			ImageCount: count,
+			UpdatedAt:  now,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal tag count item: %w", err)
+		}
+		writeRequests = append(writeRequests, &dynamodb.WriteRequest{
+			PutRequest: &dynamodb.PutRequest{Item: av},
+		})
+	}
+
+	if err := r.batchWrite(ctx, r.tagCountsTableName, writeRequests); err != nil {
+		return 0, err
+	}
+	return len(counts), nil
+}
+
+// batchGetOwners は画像の所有者をまとめて取得します
+func (r *DynamoDBTagRepository) batchGetOwners(ctx context.Context, imageIDs []string) (map[string]string, error) {
+	owners := make(map[string]string, len(imageIDs))
+	for start := 0; start < len(imageIDs); start += batchGetLimit {
+		end := min(start+batchGetLimit, len(imageIDs))
+
+		keys := make([]map[string]*dynamodb.AttributeValue, 0, end-start)
+		for _, imageID := range imageIDs[start:end] {
+			keys = append(keys, map[string]*dynamodb.AttributeValue{
+				"ImageID": {S: aws.String(imageID)},
+			})
+		}
+
+		requestItems := map[string]*dynamodb.KeysAndAttributes{
+			r.metadataTableName: {
+				Keys:                     keys,
+				ProjectionExpression:     aws.String("ImageID, #owner"),
+				ExpressionAttributeNames: map[string]*string{"#owner": aws.String("Owner")},
+			},
+		}
+		// 未処理のキーがなくなるまで繰り返す
+		for len(requestItems) > 0 {
+			result, err := r.client.BatchGetItemWithContext(ctx, &dynamodb.BatchGetItemInput{
+				RequestItems: requestItems,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to batch get image owners: %w", err)
+			}
+			for _, item := range result.Responses[r.metadataTableName] {
+				imageID, owner := item["ImageID"], item["Owner"]
+				if imageID == nil || imageID.S == nil || owner == nil || owner.S == nil {
+					continue
+				}
+				owners[*imageID.S] = *owner.S
+			}
+			requestItems = result.UnprocessedKeys
+		}
+	}
+	return owners, nil
+}
+
+// batchWrite は書き込みリクエストを上限ごとに分けて実行し、未処理のリクエストは再実行します
+func (r *DynamoDBTagRepository) batchWrite(ctx context.Context, tableName string, writeRequests []*dynamodb.WriteRequest) error {
+	for start := 0; start < len(writeRequests); start += batchWriteLimit {
+		end := min(start+batchWriteLimit, len(writeRequests))
+
+		requestItems := map[string][]*dynamodb.WriteRequest{
+			tableName: writeRequests[start:end],
+		}
+		for len(requestItems) > 0 {
+			result, err := r.client.BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{
+				RequestItems: requestItems,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to batch write items: %w", err)
+			}
+			requestItems = result.UnprocessedItems
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package thumbnailmanagement
+
+import (
+	"cloudpix/internal/domain/thumbnailmanagement/entity"
+	"cloudpix/internal/domain/thumbnailmanagement/repository"
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// DynamoDBProcessingStateItem は画像メタデータアイテムに含まれる処理状態属性の表現
+type DynamoDBProcessingStateItem struct {
+	ImageID                  string `json:"ImageID"`
+	S3ObjectKey              string `json:"S3ObjectKey"`
+	ThumbnailStatus          string `json:"ThumbnailStatus,omitempty"`
+	ThumbnailAttempts        int    `json:"ThumbnailAttempts,omitempty"`
+	ThumbnailError           string `json:"ThumbnailError,omitempty"`
+	ThumbnailNextRetryAt     string `json:"ThumbnailNextRetryAt,omitempty"`
+	ThumbnailStatusUpdatedAt string `json:"ThumbnailStatusUpdatedAt,omitempty"`
+}
+
+// 再試行を待っている処理状態を検索するインデックス
+// 再試行時刻は再試行を待っている間のみ保存するため、インデックスにはそれらのアイテムだけが含まれます
+const retryIndexName = "ThumbnailRetryIndex"
+
+// DynamoDBProcessingStateRepository はDynamoDBを使用した処理状態リポジトリの実装
+type DynamoDBProcessingStateRepository struct {
+	client            *dynamodb.DynamoDB
+	metadataTableName string
+}
+
+// NewDynamoDBProcessingStateRepository は新しい処理状態リポジトリを作成します
+func NewDynamoDBProcessingStateRepository(client *dynamodb.DynamoDB, metadataTableName string) repository.ProcessingStateRepository {
+	return &DynamoDBProcessingStateRepository{
+		client:            client,
+		metadataTableName: metadataTableName,
+	}
+}
+
+// FindByImageID は指定された画像IDの処理状態を取得します
+func (r *DynamoDBProcessingStateRepository) FindByImageID(ctx context.Context, imageID string) (*entity.ProcessingState, error) {
+	result, err := r.client.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.metadataTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ImageID": {
+				S: aws.String(imageID),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get processing state from DynamoDB: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	// アンマーシャル
+	var item DynamoDBProcessingStateItem
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal DynamoDB item: %w", err)
+	}
+
+	// 処理状態が記録されていない画像
+	if item.ThumbnailStatus == "" {
+		return nil, nil
+	}
+
+	return toProcessingState(item)
+}
+
+// FindDueForRetry は再試行時刻を過ぎた失敗中の処理状態を最大limit件、再試行時刻の古い順に取得します
+func (r *DynamoDBProcessingStateRepository) FindDueForRetry(ctx context.Context, now time.Time, limit int) ([]*entity.ProcessingState, error) {
+	// 時刻はUTCのRFC3339形式で保存しているため文字列の大小で比較できる
+	keyCondition := expression.Key("ThumbnailStatus").Equal(expression.Value(valueobject.ProcessingStatusFailed.String())).
+		And(expression.Key("ThumbnailNextRetryAt").LessThanEqual(expression.Value(now.UTC().Format(time.RFC3339))))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.metadataTableName),
+		IndexName:                 aws.String(retryIndexName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+	if limit > 0 {
+		queryInput.Limit = aws.Int64(int64(limit))
+	}
+
+	states := make([]*entity.ProcessingState, 0)
+	for {
+		result, err := r.client.QueryWithContext(ctx, queryInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query DynamoDB: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var stateItem DynamoDBProcessingStateItem
+			if err := dynamodbattribute.UnmarshalMap(item, &stateItem); err != nil {
+				continue
+			}
+			state, err := toProcessingState(stateItem)
+			if err != nil {
+				continue
+			}
+			states = append(states, state)
+			if limit > 0 && len(states) >= limit {
+				return states, nil
+			}
+		}
+
+		// 続きがなければ終了
+		if result.LastEvaluatedKey == nil {
+			return states, nil
+		}
+		queryInput.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// Save は処理状態を画像メタデータに保存します
+func (r *DynamoDBProcessingStateRepository) Save(ctx context.Context, state *entity.ProcessingState) error {
+	nextRetryAt := ""
+	if !state.NextRetryAt.IsZero() {
+		nextRetryAt = state.NextRetryAt.UTC().Format(time.RFC3339)
+	}
+
+	// 処理状態の属性のみを更新（元画像のキーは未設定の場合のみ設定する）
+	// 再試行時刻はインデックスのキーのため、再試行を待っていない場合は空文字列ではなく属性ごと削除する
+	updateExpression := "SET ThumbnailStatus = :st, ThumbnailAttempts = :at, ThumbnailError = :er, " +
+		"ThumbnailStatusUpdatedAt = :ua, S3ObjectKey = if_not_exists(S3ObjectKey, :key)"
+	values := map[string]*dynamodb.AttributeValue{
+		":st":  {S: aws.String(state.Status.String())},
+		":at":  {N: aws.String(fmt.Sprintf("%d", state.Attempts))},
+		":er":  {S: aws.String(state.LastError)},
+		":ua":  {S: aws.String(state.UpdatedAt.UTC().Format(time.RFC3339))},
+		":key": {S: aws.String(state.ObjectKey)},
+	}
+	if nextRetryAt != "" {
+		updateExpression += ", ThumbnailNextRetryAt = :nr"
+		values[":nr"] = &dynamodb.AttributeValue{S: aws.String(nextRetryAt)}
+	} else {
+		updateExpression += " REMOVE ThumbnailNextRetryAt"
+	}
+
+	// 処理中に画像が削除された場合に処理状態だけのアイテムを作成しないよう、メタデータが存在する場合のみ更新する
+	_, err := r.client.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.metadataTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ImageID": {
+				S: aws.String(state.ImageID),
+			},
+		},
+		UpdateExpression:          aws.String(updateExpression),
+		ExpressionAttributeValues: values,
+		ConditionExpression:       aws.String("attribute_exists(ImageID)"),
+	})
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return repository.ErrProcessingStateImageNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save processing state to DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+// toProcessingState はDynamoDBの表現から処理状態を作成します
+func toProcessingState(item DynamoDBProcessingStateItem) (*entity.ProcessingState, error) {
+	status, err := valueobject.ParseProcessingStatus(item.ThumbnailStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	nextRetryAt, _ := time.Parse(time.RFC3339, item.ThumbnailNextRetryAt)
+	updatedAt, _ := time.Parse(time.RFC3339, item.ThumbnailStatusUpdatedAt)
+	return &entity.ProcessingState{
+		ImageID:     item.ImageID,
+		ObjectKey:   item.S3ObjectKey,
+		Status:      status,
+		Attempts:    item.ThumbnailAttempts,
+		LastError:   item.ThumbnailError,
+		NextRetryAt: nextRetryAt,
+		UpdatedAt:   updatedAt,
+	}, nil
+}
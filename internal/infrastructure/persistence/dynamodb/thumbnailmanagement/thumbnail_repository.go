@@ -5,24 +5,59 @@ import (
 	"cloudpix/internal/domain/thumbnailmanagement/repository"
 	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 )
 
-// DynamoDBThumbnailItem はDynamoDBのサムネイルアイテム表現
+// DynamoDBThumbnailItem は画像メタデータアイテムに含まれるサムネイル属性の表現
 type DynamoDBThumbnailItem struct {
-	ImageID      string `json:"ImageID"`
-	ThumbnailKey string `json:"ThumbnailKey"`
-	ThumbnailURL string `json:"ThumbnailURL"`
-	Width        int    `json:"Width"`
-	Height       int    `json:"Height"`
-	OriginalKey  string `json:"OriginalKey"`
-	ContentType  string `json:"ContentType"`
-	CreatedAt    string `json:"CreatedAt"`
+	ImageID              string                  `json:"ImageID"`
+	ThumbnailKey         string                  `json:"ThumbnailKey"`
+	ThumbnailURL         string                  `json:"ThumbnailURL"`
+	ThumbnailWidth       int                     `json:"ThumbnailWidth"`
+	ThumbnailHeight      int                     `json:"ThumbnailHeight"`
+	ThumbnailContentType string                  `json:"ThumbnailContentType"`
+	ThumbnailCreatedAt   string                  `json:"ThumbnailCreatedAt"`
+	S3ObjectKey          string                  `json:"S3ObjectKey"`
+	Renditions           []DynamoDBRenditionItem `json:"Renditions,omitempty"`
+	PerceptualHash       string                  `json:"PerceptualHash,omitempty"`
+	Palette              []DynamoDBPaletteItem   `json:"Palette,omitempty"`
+	BlurHash             string                  `json:"BlurHash,omitempty"`
+	LQIP                 string                  `json:"LQIP,omitempty"`
+	ThumbnailVersion     string                  `json:"ThumbnailVersion,omitempty"`
+	ThumbnailWatermark   string                  `json:"ThumbnailWatermark,omitempty"`
+	HasThumbnail         bool                    `json:"HasThumbnail"`
+}
+
+// DynamoDBRenditionItem はDynamoDBのレンディション表現
+type DynamoDBRenditionItem struct {
+	Name        string               `json:"Name"`
+	Key         string               `json:"Key"`
+	URL         string               `json:"URL"`
+	Width       int                  `json:"Width"`
+	Height      int                  `json:"Height"`
+	ContentType string               `json:"ContentType"`
+	Crop        *DynamoDBCropBoxItem `json:"Crop,omitempty"`
+}
+
+// DynamoDBCropBoxItem はDynamoDBの切り取り領域表現
+type DynamoDBCropBoxItem struct {
+	X      int `json:"X"`
+	Y      int `json:"Y"`
+	Width  int `json:"Width"`
+	Height int `json:"Height"`
+}
+
+// DynamoDBPaletteItem はDynamoDBの代表色表現
+type DynamoDBPaletteItem struct {
+	Color  string  `json:"Color"`
+	Weight float64 `json:"Weight"`
 }
 
 // DynamoDBThumbnailRepository はDynamoDBを使用したサムネイルリポジトリの実装
@@ -39,31 +74,84 @@ func NewDynamoDBThumbnailRepository(client *dynamodb.DynamoDB, metadataTableName
 	}
 }
 
-// Save はサムネイル情報を保存します
+// Save はサムネイル情報を画像メタデータに保存します
 func (r *DynamoDBThumbnailRepository) Save(ctx context.Context, thumbnail *entity.Thumbnail) error {
-	// DynamoDBアイテムを作成
-	item := DynamoDBThumbnailItem{
-		ImageID:      thumbnail.ImageID,
-		ThumbnailKey: thumbnail.ThumbnailKey,
-		ThumbnailURL: thumbnail.ThumbnailURL,
-		Width:        thumbnail.GetWidth(),
-		Height:       thumbnail.GetHeight(),
-		OriginalKey:  thumbnail.OriginalKey,
-		ContentType:  thumbnail.ContentType,
-		CreatedAt:    thumbnail.CreatedAt.Format(time.RFC3339),
+	// レンディションをDynamoDBの表現に変換
+	renditions := make([]DynamoDBRenditionItem, len(thumbnail.Renditions))
+	for i, rendition := range thumbnail.Renditions {
+		renditions[i] = DynamoDBRenditionItem{
+			Name:        rendition.Name,
+			Key:         rendition.Key,
+			URL:         rendition.URL,
+			Width:       rendition.GetWidth(),
+			Height:      rendition.GetHeight(),
+			ContentType: rendition.ContentType,
+		}
+
+		// 切り取り領域がある場合のみ保存
+		if cropBox := rendition.Dimensions.CropBox(); !cropBox.IsZero() {
+			renditions[i].Crop = &DynamoDBCropBoxItem{
+				X:      cropBox.X(),
+				Y:      cropBox.Y(),
+				Width:  cropBox.Width(),
+				Height: cropBox.Height(),
+			}
+		}
+	}
+
+	// 代表色をDynamoDBの表現に変換
+	palette := make([]DynamoDBPaletteItem, len(thumbnail.Palette))
+	for i, paletteColor := range thumbnail.Palette {
+		palette[i] = DynamoDBPaletteItem{
+			Color:  paletteColor.Hex(),
+			Weight: paletteColor.Weight(),
+		}
 	}
 
 	// マーシャル
-	av, err := dynamodbattribute.MarshalMap(item)
+	renditionsAV, err := dynamodbattribute.Marshal(renditions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal renditions: %w", err)
+	}
+	paletteAV, err := dynamodbattribute.Marshal(palette)
 	if err != nil {
-		return fmt.Errorf("failed to marshal DynamoDB item: %w", err)
+		return fmt.Errorf("failed to marshal palette: %w", err)
 	}
 
-	// DynamoDBに保存
-	_, err = r.client.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+	// 画像アイテムのサムネイル属性のみを更新（画像本体の属性は保持する）
+	// 処理中に画像が削除された場合にサムネイル属性だけのアイテムを作成しないよう、メタデータが存在する場合のみ更新する
+	_, err = r.client.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(r.metadataTableName),
-		Item:      av,
+		Key: map[string]*dynamodb.AttributeValue{
+			"ImageID": {
+				S: aws.String(thumbnail.ImageID),
+			},
+		},
+		UpdateExpression: aws.String("SET ThumbnailKey = :tk, ThumbnailURL = :tu, ThumbnailWidth = :w, ThumbnailHeight = :h, " +
+			"ThumbnailContentType = :ct, ThumbnailCreatedAt = :ca, Renditions = :r, PerceptualHash = :ph, Palette = :pl, " +
+			"BlurHash = :bh, LQIP = :lq, ThumbnailVersion = :tv, ThumbnailWatermark = :wm, HasThumbnail = :ht"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":tk": {S: aws.String(thumbnail.ThumbnailKey)},
+			":tu": {S: aws.String(thumbnail.ThumbnailURL)},
+			":w":  {N: aws.String(fmt.Sprintf("%d", thumbnail.GetWidth()))},
+			":h":  {N: aws.String(fmt.Sprintf("%d", thumbnail.GetHeight()))},
+			":ct": {S: aws.String(thumbnail.ContentType)},
+			":ca": {S: aws.String(thumbnail.CreatedAt.Format(time.RFC3339))},
+			":r":  renditionsAV,
+			":ph": {S: aws.String(thumbnail.PerceptualHash)},
+			":pl": paletteAV,
+			":bh": {S: aws.String(thumbnail.BlurHash)},
+			":lq": {S: aws.String(thumbnail.LQIP)},
+			":tv": {S: aws.String(thumbnail.ConfigVersion)},
+			":wm": {S: aws.String(thumbnail.Watermark)},
+			":ht": {BOOL: aws.Bool(true)},
+		},
+		ConditionExpression: aws.String("attribute_exists(ImageID)"),
 	})
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return repository.ErrThumbnailImageNotFound
+	}
 	if err != nil {
 		return fmt.Errorf("failed to save thumbnail to DynamoDB: %w", err)
 	}
@@ -96,33 +184,75 @@ func (r *DynamoDBThumbnailRepository) FindByImageID(ctx context.Context, imageID
 		return nil, fmt.Errorf("failed to unmarshal DynamoDB item: %w", err)
 	}
 
+	if !item.HasThumbnail {
+		return nil, fmt.Errorf("thumbnail not found for image ID: %s", imageID)
+	}
+
 	// 値オブジェクトの作成
-	dimensions, _ := valueobject.NewDimensions(item.Width, item.Height)
+	dimensions, _ := valueobject.NewDimensions(item.ThumbnailWidth, item.ThumbnailHeight)
 
 	// サムネイルエンティティの作成
-	createdAt, _ := time.Parse(time.RFC3339, item.CreatedAt)
+	createdAt, _ := time.Parse(time.RFC3339, item.ThumbnailCreatedAt)
 	thumbnail := &entity.Thumbnail{
-		ImageID:      item.ImageID,
-		ThumbnailKey: item.ThumbnailKey,
-		ThumbnailURL: item.ThumbnailURL,
-		Dimensions:   dimensions,
-		OriginalKey:  item.OriginalKey,
-		ContentType:  item.ContentType,
-		CreatedAt:    createdAt,
+		ImageID:        item.ImageID,
+		ThumbnailKey:   item.ThumbnailKey,
+		ThumbnailURL:   item.ThumbnailURL,
+		Dimensions:     dimensions,
+		OriginalKey:    item.S3ObjectKey,
+		ContentType:    item.ThumbnailContentType,
+		Renditions:     make([]entity.Rendition, 0, len(item.Renditions)),
+		PerceptualHash: item.PerceptualHash,
+		BlurHash:       item.BlurHash,
+		LQIP:           item.LQIP,
+		ConfigVersion:  item.ThumbnailVersion,
+		Watermark:      item.ThumbnailWatermark,
+		CreatedAt:      createdAt,
+	}
+
+	// 代表色の復元
+	for _, paletteItem := range item.Palette {
+		paletteColor, err := valueobject.ParsePaletteColor(paletteItem.Color, paletteItem.Weight)
+		if err != nil {
+			continue
+		}
+		thumbnail.Palette = append(thumbnail.Palette, paletteColor)
+	}
+
+	// レンディションの復元
+	for _, renditionItem := range item.Renditions {
+		renditionDimensions, _ := valueobject.NewDimensions(renditionItem.Width, renditionItem.Height)
+		if renditionItem.Crop != nil {
+			cropBox, err := valueobject.NewCropBox(renditionItem.Crop.X, renditionItem.Crop.Y, renditionItem.Crop.Width, renditionItem.Crop.Height)
+			if err == nil {
+				renditionDimensions = renditionDimensions.WithCropBox(cropBox)
+			}
+		}
+		thumbnail.AddRendition(entity.NewRendition(
+			renditionItem.Name,
+			renditionItem.Key,
+			renditionItem.URL,
+			renditionDimensions,
+			renditionItem.ContentType,
+		))
 	}
 
 	return thumbnail, nil
 }
 
-// Delete はサムネイルを削除します
+// Delete はサムネイル情報を画像メタデータから削除します
 func (r *DynamoDBThumbnailRepository) Delete(ctx context.Context, imageID string) error {
-	_, err := r.client.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+	_, err := r.client.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(r.metadataTableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"ImageID": {
 				S: aws.String(imageID),
 			},
 		},
+		UpdateExpression: aws.String("SET HasThumbnail = :ht REMOVE ThumbnailKey, ThumbnailURL, ThumbnailWidth, ThumbnailHeight, " +
+			"ThumbnailContentType, ThumbnailCreatedAt, Renditions, PerceptualHash, Palette, BlurHash, LQIP, ThumbnailVersion, ThumbnailWatermark"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":ht": {BOOL: aws.Bool(false)},
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to delete thumbnail from DynamoDB: %w", err)
@@ -140,7 +270,7 @@ func (r *DynamoDBThumbnailRepository) UpdateMetadata(ctx context.Context, imageI
 				S: aws.String(imageID),
 			},
 		},
-		UpdateExpression: aws.String("SET ThumbnailURL = :tu, Width = :w, Height = :h"),
+		UpdateExpression: aws.String("SET ThumbnailURL = :tu, ThumbnailWidth = :w, ThumbnailHeight = :h"),
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
 			":tu": {
 				S: aws.String(thumbnailURL),
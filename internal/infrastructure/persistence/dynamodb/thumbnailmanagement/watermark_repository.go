@@ -0,0 +1,127 @@
+package thumbnailmanagement
+
+import (
+	"cloudpix/internal/domain/thumbnailmanagement/entity"
+	"cloudpix/internal/domain/thumbnailmanagement/repository"
+	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// DynamoDBWatermarkItem はDynamoDBの透かし設定表現
+type DynamoDBWatermarkItem struct {
+	Scope      string   `json:"Scope"`
+	Type       string   `json:"Type"`
+	Text       string   `json:"Text,omitempty"`
+	ImageKey   string   `json:"ImageKey,omitempty"`
+	Position   string   `json:"Position"`
+	Opacity    float64  `json:"Opacity"`
+	Scale      float64  `json:"Scale"`
+	Renditions []string `json:"Renditions"`
+	UpdatedAt  string   `json:"UpdatedAt"`
+}
+
+// DynamoDBWatermarkRepository はDynamoDBを使用した透かし設定リポジトリの実装
+type DynamoDBWatermarkRepository struct {
+	client             *dynamodb.DynamoDB
+	watermarkTableName string
+}
+
+// NewDynamoDBWatermarkRepository は新しい透かし設定リポジトリを作成します
+func NewDynamoDBWatermarkRepository(client *dynamodb.DynamoDB, watermarkTableName string) repository.WatermarkRepository {
+	return &DynamoDBWatermarkRepository{
+		client:             client,
+		watermarkTableName: watermarkTableName,
+	}
+}
+
+// FindByScope は指定されたスコープの透かし設定を取得します
+func (r *DynamoDBWatermarkRepository) FindByScope(ctx context.Context, scope string) (*entity.WatermarkSetting, error) {
+	result, err := r.client.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.watermarkTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Scope": {
+				S: aws.String(scope),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watermark setting from DynamoDB: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	// アンマーシャル
+	var item DynamoDBWatermarkItem
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal DynamoDB item: %w", err)
+	}
+
+	watermark, err := valueobject.NewWatermark(item.Type, item.Text, item.ImageKey, item.Position, item.Opacity, item.Scale)
+	if err != nil {
+		return nil, fmt.Errorf("invalid watermark setting %s: %w", scope, err)
+	}
+
+	updatedAt, _ := time.Parse(time.RFC3339, item.UpdatedAt)
+	return &entity.WatermarkSetting{
+		Scope:      item.Scope,
+		Watermark:  watermark,
+		Renditions: item.Renditions,
+		UpdatedAt:  updatedAt,
+	}, nil
+}
+
+// Save は透かし設定を保存します
+func (r *DynamoDBWatermarkRepository) Save(ctx context.Context, setting *entity.WatermarkSetting) error {
+	watermark := setting.Watermark
+	item := DynamoDBWatermarkItem{
+		Scope:      setting.Scope,
+		Type:       string(watermark.Type()),
+		Text:       watermark.Text(),
+		ImageKey:   watermark.ImageKey(),
+		Position:   string(watermark.Position()),
+		Opacity:    watermark.Opacity(),
+		Scale:      watermark.Scale(),
+		Renditions: setting.Renditions,
+		UpdatedAt:  setting.UpdatedAt.Format(time.RFC3339),
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal DynamoDB item: %w", err)
+	}
+
+	_, err = r.client.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.watermarkTableName),
+		Item:      av,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save watermark setting to DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+// Delete は指定されたスコープの透かし設定を削除します
+func (r *DynamoDBWatermarkRepository) Delete(ctx context.Context, scope string) error {
+	_, err := r.client.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.watermarkTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Scope": {
+				S: aws.String(scope),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete watermark setting from DynamoDB: %w", err)
+	}
+
+	return nil
+}
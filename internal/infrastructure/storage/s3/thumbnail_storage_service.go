@@ -2,13 +2,17 @@ package s3
 
 import (
 	"bytes"
+	imagevalueobject "cloudpix/internal/domain/imagemanagement/valueobject"
 	"cloudpix/internal/domain/thumbnailmanagement/service"
 	"cloudpix/internal/domain/thumbnailmanagement/valueobject"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
@@ -16,13 +20,16 @@ import (
 type S3ThumbnailStorageService struct {
 	s3Client  *s3.S3
 	awsRegion string
+	limits    imagevalueobject.ImageLimits
 }
 
 // NewS3ThumbnailStorageService は新しいストレージサービスを作成します
-func NewS3ThumbnailStorageService(s3Client *s3.S3, awsRegion string) service.StorageService {
+// 上限を超える画像は読み込まずに imagevalueobject.ErrImageTooLarge で拒否します
+func NewS3ThumbnailStorageService(s3Client *s3.S3, awsRegion string, limits imagevalueobject.ImageLimits) service.StorageService {
 	return &S3ThumbnailStorageService{
 		s3Client:  s3Client,
 		awsRegion: awsRegion,
+		limits:    limits,
 	}
 }
 
@@ -38,11 +45,21 @@ func (s *S3ThumbnailStorageService) FetchImage(ctx context.Context, bucket, key
 	}
 	defer resp.Body.Close()
 
-	// レスポンスボディを読み込む
-	data, err := io.ReadAll(resp.Body)
+	// 上限を超えるオブジェクトはボディを読み込まずに拒否
+	if resp.ContentLength != nil {
+		if err := s.limits.CheckBytes(*resp.ContentLength); err != nil {
+			return valueobject.ImageData{}, err
+		}
+	}
+
+	// レスポンスボディを上限まで読み込む（サイズが不明な場合に備えて1バイト多く読む）
+	data, err := io.ReadAll(io.LimitReader(resp.Body, s.limits.MaxBytes()+1))
 	if err != nil {
 		return valueobject.ImageData{}, fmt.Errorf("failed to read S3 object body: %w", err)
 	}
+	if err := s.limits.CheckBytes(int64(len(data))); err != nil {
+		return valueobject.ImageData{}, err
+	}
 
 	// コンテンツタイプを取得
 	contentType := "application/octet-stream"
@@ -54,6 +71,35 @@ func (s *S3ThumbnailStorageService) FetchImage(ctx context.Context, bucket, key
 	return valueobject.NewImageData(data, contentType), nil
 }
 
+// OpenImage はストレージの画像をメモリに読み込まずにストリームとして開き、コンテンツタイプとともに返します
+// 呼び出し側はデコード後にストリームを閉じる必要があります
+func (s *S3ThumbnailStorageService) OpenImage(ctx context.Context, bucket, key string) (io.ReadCloser, string, error) {
+	// S3からオブジェクトを取得
+	resp, err := s.s3Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get S3 object: %w", err)
+	}
+
+	// 上限を超えるオブジェクトはボディを読み込まずに拒否
+	if resp.ContentLength != nil {
+		if err := s.limits.CheckBytes(*resp.ContentLength); err != nil {
+			resp.Body.Close()
+			return nil, "", err
+		}
+	}
+
+	// コンテンツタイプを取得
+	contentType := "application/octet-stream"
+	if resp.ContentType != nil {
+		contentType = *resp.ContentType
+	}
+
+	return resp.Body, contentType, nil
+}
+
 // UploadThumbnail はサムネイルをアップロードします
 func (s *S3ThumbnailStorageService) UploadThumbnail(ctx context.Context, bucket, key string, data valueobject.ImageData) error {
 	// S3にアップロード
@@ -71,6 +117,24 @@ func (s *S3ThumbnailStorageService) UploadThumbnail(ctx context.Context, bucket,
 	return nil
 }
 
+// ObjectExists はオブジェクトが存在するかを確認します
+func (s *S3ThumbnailStorageService) ObjectExists(ctx context.Context, bucket, key string) (bool, error) {
+	_, err := s.s3Client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		// 存在しない場合はエラーではなくfalseを返す
+		var awsErr awserr.RequestFailure
+		if errors.As(err, &awsErr) && awsErr.StatusCode() == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to head S3 object: %w", err)
+	}
+
+	return true, nil
+}
+
 // GetObjectURL はオブジェクトの公開URLを生成します
 func (s *S3ThumbnailStorageService) GetObjectURL(bucket, key string) string {
 	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, s.awsRegion, key)
@@ -102,6 +102,127 @@ resource "aws_lambda_permission" "list_api_gateway" {
   source_arn = "${aws_api_gateway_rest_api.cloudpix_api.execution_arn}/*/*"
 }
 
+################################
+# API Gateway - Image Detail Endpoint
+################################
+# /images リソースの作成
+resource "aws_api_gateway_resource" "images" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_rest_api.cloudpix_api.root_resource_id
+  path_part   = "images"
+}
+
+# /images/{imageId} リソースの作成
+resource "aws_api_gateway_resource" "images_image" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.images.id
+  path_part   = "{imageId}"
+}
+
+# GET /images/{imageId} メソッド - 画像詳細の取得
+resource "aws_api_gateway_method" "images_image_get" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.images_image.id
+  http_method   = "GET"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
+# GET /images/{imageId} との統合（一覧Lambdaで処理）
+resource "aws_api_gateway_integration" "images_image_get_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.images_image.id
+  http_method = aws_api_gateway_method.images_image_get.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_list.invoke_arn
+}
+
+################################
+# API Gateway - Similarity Endpoints
+################################
+# /images/{imageId}/similar リソースの作成
+resource "aws_api_gateway_resource" "images_image_similar" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.images_image.id
+  path_part   = "similar"
+}
+
+# GET /images/{imageId}/similar メソッド - 類似画像の検索
+resource "aws_api_gateway_method" "images_image_similar_get" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.images_image_similar.id
+  http_method   = "GET"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
+# GET /images/{imageId}/similar との統合（一覧Lambdaで処理）
+resource "aws_api_gateway_integration" "images_image_similar_get_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.images_image_similar.id
+  http_method = aws_api_gateway_method.images_image_similar_get.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_list.invoke_arn
+}
+
+# /images/duplicates リソースの作成
+resource "aws_api_gateway_resource" "images_duplicates" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.images.id
+  path_part   = "duplicates"
+}
+
+# GET /images/duplicates メソッド - 重複画像レポート
+resource "aws_api_gateway_method" "images_duplicates_get" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.images_duplicates.id
+  http_method   = "GET"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
+# GET /images/duplicates との統合（一覧Lambdaで処理）
+resource "aws_api_gateway_integration" "images_duplicates_get_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.images_duplicates.id
+  http_method = aws_api_gateway_method.images_duplicates_get.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_list.invoke_arn
+}
+
+# /search リソースの作成
+resource "aws_api_gateway_resource" "search" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_rest_api.cloudpix_api.root_resource_id
+  path_part   = "search"
+}
+
+# GET /search メソッド - タグの組み合わせによる画像検索
+resource "aws_api_gateway_method" "search_get" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.search.id
+  http_method   = "GET"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
+# GET /search との統合（一覧Lambdaで処理）
+resource "aws_api_gateway_integration" "search_get_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.search.id
+  http_method = aws_api_gateway_method.search_get.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_list.invoke_arn
+}
+
 ################################
 # API Gateway - Deployment
 ################################
@@ -110,10 +231,25 @@ resource "aws_api_gateway_deployment" "cloudpix" {
   depends_on = [
     aws_api_gateway_integration.lambda_integration,
     aws_api_gateway_integration.list_lambda_integration,
+    aws_api_gateway_integration.images_image_get_integration,
+    aws_api_gateway_integration.images_image_render_get_integration,
+    aws_api_gateway_integration.images_image_similar_get_integration,
+    aws_api_gateway_integration.images_duplicates_get_integration,
+    aws_api_gateway_integration.search_get_integration,
     aws_api_gateway_integration.tags_get_integration,
     aws_api_gateway_integration.tags_post_integration,
+    aws_api_gateway_integration.tags_suggest_get_integration,
+    aws_api_gateway_integration.tags_rename_post_integration,
+    aws_api_gateway_integration.tags_merge_post_integration,
+    aws_api_gateway_integration.tags_aliases_get_integration,
+    aws_api_gateway_integration.tags_alias_put_integration,
+    aws_api_gateway_integration.tags_alias_delete_integration,
+    aws_api_gateway_integration.tags_history_get_integration,
+    aws_api_gateway_integration.tags_rules_dry_run_post_integration,
     aws_api_gateway_integration.tags_image_get_integration,
-    aws_api_gateway_integration.tags_image_delete_integration
+    aws_api_gateway_integration.tags_image_delete_integration,
+    aws_api_gateway_integration.tags_image_history_get_integration,
+    aws_api_gateway_integration.watermark_integration
   ]
 
   rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
@@ -22,6 +22,16 @@ resource "aws_dynamodb_table" "cloudpix_metadata" {
     type = "S"
   }
 
+  attribute {
+    name = "ThumbnailStatus"
+    type = "S"
+  }
+
+  attribute {
+    name = "ThumbnailNextRetryAt"
+    type = "S"
+  }
+
   # UploadDateによるクエリ用のGSI
   global_secondary_index {
     name            = "UploadDateIndex"
@@ -36,6 +46,14 @@ resource "aws_dynamodb_table" "cloudpix_metadata" {
     projection_type = "ALL"
   }
 
+  # サムネイル生成の再試行待ちのクエリ用のGSI（再試行時刻を持つアイテムのみを含むスパースインデックス）
+  global_secondary_index {
+    name            = "ThumbnailRetryIndex"
+    hash_key        = "ThumbnailStatus"
+    range_key       = "ThumbnailNextRetryAt"
+    projection_type = "ALL"
+  }
+
   tags = {
     Name        = "${var.app_name}-Metadata"
     Environment = var.environment
@@ -70,4 +88,120 @@ resource "aws_dynamodb_table" "cloudpix_tags" {
     Name        = "${var.app_name}-Tags"
     Environment = var.environment
   }
-}
\ No newline at end of file
+}
+
+# タグの利用数（Scope は global または user:<ユーザーID>）
+resource "aws_dynamodb_table" "cloudpix_tag_counts" {
+  name         = "${var.app_name}-tag-counts"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "Scope"
+  range_key    = "TagName"
+
+  attribute {
+    name = "Scope"
+    type = "S"
+  }
+
+  attribute {
+    name = "TagName"
+    type = "S"
+  }
+
+  tags = {
+    Name        = "${var.app_name}-TagCounts"
+    Environment = var.environment
+  }
+}
+
+# タグの別名（同義語）と正規のタグの対応（管理者が登録）
+resource "aws_dynamodb_table" "cloudpix_tag_aliases" {
+  name         = "${var.app_name}-tag-aliases"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "Alias"
+
+  attribute {
+    name = "Alias"
+    type = "S"
+  }
+
+  tags = {
+    Name        = "${var.app_name}-TagAliases"
+    Environment = var.environment
+  }
+}
+
+# タグの変更履歴（EntryKey は「発生時刻#ID」、期間での検索は日付ごとのGSIを使用）
+resource "aws_dynamodb_table" "cloudpix_tag_history" {
+  name         = "${var.app_name}-tag-history"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "ImageID"
+  range_key    = "EntryKey"
+
+  attribute {
+    name = "ImageID"
+    type = "S"
+  }
+
+  attribute {
+    name = "EntryKey"
+    type = "S"
+  }
+
+  attribute {
+    name = "EntryDate"
+    type = "S"
+  }
+
+  # 期間での検索用インデックス（日付ごとに新しい順で取得）
+  global_secondary_index {
+    name            = "EntryDateIndex"
+    hash_key        = "EntryDate"
+    range_key       = "EntryKey"
+    projection_type = "ALL"
+  }
+
+  tags = {
+    Name        = "${var.app_name}-TagHistory"
+    Environment = var.environment
+  }
+}
+
+# 知覚ハッシュのバンドインデックス（類似画像検索用）
+resource "aws_dynamodb_table" "cloudpix_similarity" {
+  name         = "${var.app_name}-similarity"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "Band"
+  range_key    = "ImageID"
+
+  attribute {
+    name = "Band"
+    type = "S"
+  }
+
+  attribute {
+    name = "ImageID"
+    type = "S"
+  }
+
+  tags = {
+    Name        = "${var.app_name}-Similarity"
+    Environment = var.environment
+  }
+}
+
+# 透かし設定テーブル（ユーザーごとの設定と管理者が設定するデフォルト）
+resource "aws_dynamodb_table" "cloudpix_watermarks" {
+  name         = "${var.app_name}-watermarks"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "Scope"
+
+  attribute {
+    name = "Scope"
+    type = "S"
+  }
+
+  tags = {
+    Name        = "${var.app_name}-Watermarks"
+    Environment = var.environment
+  }
+}
@@ -65,12 +65,16 @@ resource "aws_iam_policy" "lambda_dynamodb_access" {
           "dynamodb:UpdateItem",
           "dynamodb:DeleteItem",
           "dynamodb:Query",
-          "dynamodb:Scan"
+          "dynamodb:Scan",
+          "dynamodb:BatchGetItem",
+          "dynamodb:BatchWriteItem"
         ]
         Effect = "Allow"
         Resource = [
           aws_dynamodb_table.cloudpix_metadata.arn,
-          "${aws_dynamodb_table.cloudpix_metadata.arn}/index/*"
+          "${aws_dynamodb_table.cloudpix_metadata.arn}/index/*",
+          aws_dynamodb_table.cloudpix_similarity.arn,
+          aws_dynamodb_table.cloudpix_watermarks.arn
         ]
       }
     ]
@@ -80,7 +84,7 @@ resource "aws_iam_policy" "lambda_dynamodb_access" {
 # Lambda関数にタグテーブルへのアクセス権限を付与
 resource "aws_iam_policy" "lambda_tags_access" {
   name        = "lambda-tags-access-policy"
-  description = "Allow Lambda to access Tags DynamoDB table"
+  description = "Allow Lambda to access Tags, Tag Counts, Tag Aliases and Tag History DynamoDB tables"
 
   policy = jsonencode({
     Version = "2012-10-17"
@@ -92,12 +96,17 @@ resource "aws_iam_policy" "lambda_tags_access" {
           "dynamodb:UpdateItem",
           "dynamodb:DeleteItem",
           "dynamodb:Query",
-          "dynamodb:Scan"
+          "dynamodb:Scan",
+          "dynamodb:BatchWriteItem"
         ]
         Effect = "Allow"
         Resource = [
           aws_dynamodb_table.cloudpix_tags.arn,
-          "${aws_dynamodb_table.cloudpix_tags.arn}/index/*"
+          "${aws_dynamodb_table.cloudpix_tags.arn}/index/*",
+          aws_dynamodb_table.cloudpix_tag_counts.arn,
+          aws_dynamodb_table.cloudpix_tag_aliases.arn,
+          aws_dynamodb_table.cloudpix_tag_history.arn,
+          "${aws_dynamodb_table.cloudpix_tag_history.arn}/index/*"
         ]
       }
     ]
@@ -9,7 +9,22 @@ locals {
     ENABLE_XRAY    = var.enable_xray_tracing ? "true" : "false"
   }
 
-  upload_lambda_env_vars = merge(local.common_lambda_env_vars, {
+  image_limit_env_vars = {
+    MAX_IMAGE_PIXELS = tostring(var.max_image_pixels)
+    MAX_IMAGE_BYTES  = tostring(var.max_image_bytes)
+  }
+
+  # 自動タグ付けで画像にタグを付けるLambdaの設定
+  auto_tag_env_vars = {
+    TAGS_TABLE_NAME        = aws_dynamodb_table.cloudpix_tags.name
+    TAG_COUNTS_TABLE_NAME  = aws_dynamodb_table.cloudpix_tag_counts.name
+    TAG_ALIASES_TABLE_NAME = aws_dynamodb_table.cloudpix_tag_aliases.name
+    TAG_HISTORY_TABLE_NAME = aws_dynamodb_table.cloudpix_tag_history.name
+    TAG_NAMESPACES         = var.tag_namespaces
+    AUTO_TAG_RULES         = var.auto_tag_rules
+  }
+
+  upload_lambda_env_vars = merge(local.common_lambda_env_vars, local.image_limit_env_vars, local.auto_tag_env_vars, {
     S3_BUCKET_NAME      = aws_s3_bucket.cloudpix_images.bucket
     METADATA_TABLE_NAME = aws_dynamodb_table.cloudpix_metadata.name
     USER_POOL_ID        = aws_cognito_user_pool.cloudpix_users.id
@@ -17,28 +32,63 @@ locals {
   })
 
   list_lambda_env_vars = merge(local.common_lambda_env_vars, {
-    METADATA_TABLE_NAME = aws_dynamodb_table.cloudpix_metadata.name
-    USER_POOL_ID        = aws_cognito_user_pool.cloudpix_users.id
-    USER_POOL_CLIENT_ID = aws_cognito_user_pool_client.cloudpix_client.id
+    METADATA_TABLE_NAME   = aws_dynamodb_table.cloudpix_metadata.name
+    SIMILARITY_TABLE_NAME = aws_dynamodb_table.cloudpix_similarity.name
+    TAGS_TABLE_NAME        = aws_dynamodb_table.cloudpix_tags.name
+    TAG_COUNTS_TABLE_NAME  = aws_dynamodb_table.cloudpix_tag_counts.name
+    TAG_ALIASES_TABLE_NAME = aws_dynamodb_table.cloudpix_tag_aliases.name
+    USER_POOL_ID           = aws_cognito_user_pool.cloudpix_users.id
+    USER_POOL_CLIENT_ID   = aws_cognito_user_pool_client.cloudpix_client.id
   })
 
-  thumbnail_lambda_env_vars = merge(local.common_lambda_env_vars, {
-    S3_BUCKET_NAME      = aws_s3_bucket.cloudpix_images.bucket
-    METADATA_TABLE_NAME = aws_dynamodb_table.cloudpix_metadata.name
+  thumbnail_lambda_env_vars = merge(local.common_lambda_env_vars, local.image_limit_env_vars, local.auto_tag_env_vars, {
+    S3_BUCKET_NAME         = aws_s3_bucket.cloudpix_images.bucket
+    METADATA_TABLE_NAME    = aws_dynamodb_table.cloudpix_metadata.name
+    SIMILARITY_TABLE_NAME  = aws_dynamodb_table.cloudpix_similarity.name
+    WATERMARK_TABLE_NAME   = aws_dynamodb_table.cloudpix_watermarks.name
+    THUMBNAIL_RENDITIONS   = var.thumbnail_renditions
+    THUMBNAIL_ANIMATED_GIF = tostring(var.thumbnail_animated_gif)
+    THUMBNAIL_MAX_ATTEMPTS = tostring(var.thumbnail_max_attempts)
+  })
+
+  thumbnail_retry_lambda_env_vars = local.thumbnail_lambda_env_vars
+
+  render_lambda_env_vars = merge(local.common_lambda_env_vars, local.image_limit_env_vars, {
+    S3_BUCKET_NAME       = aws_s3_bucket.cloudpix_images.bucket
+    METADATA_TABLE_NAME  = aws_dynamodb_table.cloudpix_metadata.name
+    WATERMARK_TABLE_NAME = aws_dynamodb_table.cloudpix_watermarks.name
+    USER_POOL_ID         = aws_cognito_user_pool.cloudpix_users.id
+    USER_POOL_CLIENT_ID  = aws_cognito_user_pool_client.cloudpix_client.id
+    RENDER_PRESETS       = var.render_presets
   })
 
   tags_lambda_env_vars = merge(local.common_lambda_env_vars, {
-    TAGS_TABLE_NAME     = aws_dynamodb_table.cloudpix_tags.name
-    METADATA_TABLE_NAME = aws_dynamodb_table.cloudpix_metadata.name
-    USER_POOL_ID        = aws_cognito_user_pool.cloudpix_users.id
-    USER_POOL_CLIENT_ID = aws_cognito_user_pool_client.cloudpix_client.id
+    TAGS_TABLE_NAME        = aws_dynamodb_table.cloudpix_tags.name
+    TAG_COUNTS_TABLE_NAME  = aws_dynamodb_table.cloudpix_tag_counts.name
+    TAG_ALIASES_TABLE_NAME = aws_dynamodb_table.cloudpix_tag_aliases.name
+    TAG_HISTORY_TABLE_NAME = aws_dynamodb_table.cloudpix_tag_history.name
+    METADATA_TABLE_NAME    = aws_dynamodb_table.cloudpix_metadata.name
+    TAG_NAMESPACES         = var.tag_namespaces
+    AUTO_TAG_RULES         = var.auto_tag_rules
+    USER_POOL_ID           = aws_cognito_user_pool.cloudpix_users.id
+    USER_POOL_CLIENT_ID    = aws_cognito_user_pool_client.cloudpix_client.id
+  })
+
+  watermark_lambda_env_vars = merge(local.common_lambda_env_vars, {
+    WATERMARK_TABLE_NAME = aws_dynamodb_table.cloudpix_watermarks.name
+    METADATA_TABLE_NAME  = aws_dynamodb_table.cloudpix_metadata.name
+    THUMBNAIL_RENDITIONS = var.thumbnail_renditions
+    USER_POOL_ID         = aws_cognito_user_pool.cloudpix_users.id
+    USER_POOL_CLIENT_ID  = aws_cognito_user_pool_client.cloudpix_client.id
   })
 
   cleanup_lambda_env_vars = merge(local.common_lambda_env_vars, {
-    S3_BUCKET_NAME      = aws_s3_bucket.cloudpix_images.bucket
-    METADATA_TABLE_NAME = aws_dynamodb_table.cloudpix_metadata.name
-    TAGS_TABLE_NAME     = aws_dynamodb_table.cloudpix_tags.name
-    RETENTION_DAYS      = var.image_retention_days
+    S3_BUCKET_NAME        = aws_s3_bucket.cloudpix_images.bucket
+    METADATA_TABLE_NAME   = aws_dynamodb_table.cloudpix_metadata.name
+    TAGS_TABLE_NAME       = aws_dynamodb_table.cloudpix_tags.name
+    TAG_COUNTS_TABLE_NAME = aws_dynamodb_table.cloudpix_tag_counts.name
+    SIMILARITY_TABLE_NAME = aws_dynamodb_table.cloudpix_similarity.name
+    RETENTION_DAYS        = var.image_retention_days
   })
 
 
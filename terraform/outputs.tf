@@ -36,11 +36,26 @@ output "ecr_thumbnail_repository_url" {
   description = "ECRリポジトリのURL（サムネイル生成機能用）"
 }
 
+output "ecr_render_repository_url" {
+  value       = aws_ecr_repository.cloudpix_render.repository_url
+  description = "ECRリポジトリのURL（オンデマンド画像変換機能用）"
+}
+
 output "ecr_tags_repository_url" {
   value       = aws_ecr_repository.cloudpix_tags.repository_url
   description = "ECRリポジトリのURL（タグ管理用）"
 }
 
+output "ecr_watermark_repository_url" {
+  value       = aws_ecr_repository.cloudpix_watermark.repository_url
+  description = "ECRリポジトリのURL（透かし設定用）"
+}
+
+output "ecr_thumbnail_retry_repository_url" {
+  value       = aws_ecr_repository.cloudpix_thumbnail_retry.repository_url
+  description = "ECRリポジトリのURL（サムネイル再試行用）"
+}
+
 output "ecr_cleanup_repository_url" {
   value       = aws_ecr_repository.cloudpix_cleanup.repository_url
   description = "ECRリポジトリのURL（クリーンアップ用）"
@@ -0,0 +1,104 @@
+################################
+# ECR Repository for Render
+################################
+# オンデマンド画像変換用のECRリポジトリ
+resource "aws_ecr_repository" "cloudpix_render" {
+  name                 = "${var.app_name}-render"
+  image_tag_mutability = "MUTABLE"
+  force_delete         = true
+
+  image_scanning_configuration {
+    scan_on_push = true
+  }
+}
+
+################################
+# Docker Build & Push - Render
+################################
+# 画像変換関数のイメージのビルドとプッシュ
+resource "null_resource" "docker_build_push_render" {
+  depends_on = [aws_ecr_repository.cloudpix_render]
+
+  triggers = {
+    ecr_repository_url = aws_ecr_repository.cloudpix_render.repository_url
+    dockerfile_hash    = filemd5("${path.module}/../Dockerfile")
+    main_go_hash       = filemd5("${path.module}/../cmd/render/main.go")
+    build_script_hash  = filemd5("${path.module}/../build_and_push.sh")
+  }
+
+  provisioner "local-exec" {
+    command = <<-EOT
+      echo "Building render function image..."
+      cd ${path.module}/.. && \
+      chmod +x build_and_push.sh && \
+      REPO_NAME="cloudpix-render" ./build_and_push.sh ${aws_ecr_repository.cloudpix_render.repository_url} ./cmd/render/main.go
+    EOT
+  }
+}
+
+################################
+# Render Lambda Function
+################################
+# オンデマンド画像変換用Lambda関数
+resource "aws_lambda_function" "cloudpix_render" {
+  function_name = "${var.app_name}-render"
+  role          = aws_iam_role.lambda_role.arn
+  package_type  = "Image"
+  image_uri     = "${aws_ecr_repository.cloudpix_render.repository_url}:latest"
+
+  timeout     = 30  # 画像変換は時間がかかるため、長めのタイムアウトを設定
+  memory_size = 512 # 画像処理には多めのメモリが必要
+
+  environment {
+    variables = local.render_lambda_env_vars
+  }
+
+  depends_on = [
+    null_resource.docker_build_push_render
+  ]
+
+  # X-Rayトレースを有効化
+  tracing_config {
+    mode = "Active"
+  }
+}
+
+################################
+# API Gateway - Render Endpoint
+################################
+# /images/{imageId}/render リソースの作成
+resource "aws_api_gateway_resource" "images_image_render" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.images_image.id
+  path_part   = "render"
+}
+
+# GET /images/{imageId}/render メソッド - 画像の変換
+resource "aws_api_gateway_method" "images_image_render_get" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.images_image_render.id
+  http_method   = "GET"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
+# GET /images/{imageId}/render との統合
+resource "aws_api_gateway_integration" "images_image_render_get_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.images_image_render.id
+  http_method = aws_api_gateway_method.images_image_render_get.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_render.invoke_arn
+}
+
+# Lambda実行権限の付与
+resource "aws_lambda_permission" "render_api_gateway" {
+  statement_id  = "AllowExecutionFromAPIGatewayForRender"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.cloudpix_render.function_name
+  principal     = "apigateway.amazonaws.com"
+
+  source_arn = "${aws_api_gateway_rest_api.cloudpix_api.execution_arn}/*/*"
+}
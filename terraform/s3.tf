@@ -48,6 +48,13 @@ resource "aws_s3_bucket_policy" "allow_public_read" {
         Principal = "*"
         Action    = "s3:GetObject"
         Resource  = "${aws_s3_bucket.cloudpix_images.arn}/uploads/*"
+      },
+      {
+        Sid       = "PublicReadForRenders"
+        Effect    = "Allow"
+        Principal = "*"
+        Action    = "s3:GetObject"
+        Resource  = "${aws_s3_bucket.cloudpix_images.arn}/renders/*"
       }
     ]
   })
@@ -80,6 +80,69 @@ resource "aws_api_gateway_resource" "tags_image" {
   path_part   = "{imageId}"
 }
 
+# /tags/suggest リソースの作成
+resource "aws_api_gateway_resource" "tags_suggest" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.tags.id
+  path_part   = "suggest"
+}
+
+# /tags/rename リソースの作成
+resource "aws_api_gateway_resource" "tags_rename" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.tags.id
+  path_part   = "rename"
+}
+
+# /tags/merge リソースの作成
+resource "aws_api_gateway_resource" "tags_merge" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.tags.id
+  path_part   = "merge"
+}
+
+# /tags/aliases リソースの作成
+resource "aws_api_gateway_resource" "tags_aliases" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.tags.id
+  path_part   = "aliases"
+}
+
+# /tags/aliases/{alias} リソースの作成
+resource "aws_api_gateway_resource" "tags_alias" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.tags_aliases.id
+  path_part   = "{alias}"
+}
+
+# /tags/history リソースの作成
+resource "aws_api_gateway_resource" "tags_history" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.tags.id
+  path_part   = "history"
+}
+
+# /tags/rules リソースの作成
+resource "aws_api_gateway_resource" "tags_rules" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.tags.id
+  path_part   = "rules"
+}
+
+# /tags/rules/dry-run リソースの作成
+resource "aws_api_gateway_resource" "tags_rules_dry_run" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.tags_rules.id
+  path_part   = "dry-run"
+}
+
+# /tags/{imageId}/history リソースの作成
+resource "aws_api_gateway_resource" "tags_image_history" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.tags_image.id
+  path_part   = "history"
+}
+
 # GET /tags メソッド - すべてのタグのリスト取得
 resource "aws_api_gateway_method" "tags_get" {
   rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
@@ -98,6 +161,78 @@ resource "aws_api_gateway_method" "tags_post" {
   authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
 }
 
+# GET /tags/suggest メソッド - タグの入力補完
+resource "aws_api_gateway_method" "tags_suggest_get" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.tags_suggest.id
+  http_method   = "GET"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
+# POST /tags/rename メソッド - タグの名前変更（管理者のみ）
+resource "aws_api_gateway_method" "tags_rename_post" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.tags_rename.id
+  http_method   = "POST"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
+# POST /tags/merge メソッド - タグの統合（管理者のみ）
+resource "aws_api_gateway_method" "tags_merge_post" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.tags_merge.id
+  http_method   = "POST"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
+# GET /tags/aliases メソッド - タグの別名の一覧取得
+resource "aws_api_gateway_method" "tags_aliases_get" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.tags_aliases.id
+  http_method   = "GET"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
+# PUT /tags/aliases/{alias} メソッド - タグの別名の登録（管理者のみ）
+resource "aws_api_gateway_method" "tags_alias_put" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.tags_alias.id
+  http_method   = "PUT"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
+# DELETE /tags/aliases/{alias} メソッド - タグの別名の削除（管理者のみ）
+resource "aws_api_gateway_method" "tags_alias_delete" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.tags_alias.id
+  http_method   = "DELETE"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
+# GET /tags/history メソッド - 期間を指定したタグの変更履歴の取得（管理者のみ）
+resource "aws_api_gateway_method" "tags_history_get" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.tags_history.id
+  http_method   = "GET"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
+# POST /tags/rules/dry-run メソッド - 自動タグ付けのルールの試行（管理者のみ）
+resource "aws_api_gateway_method" "tags_rules_dry_run_post" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.tags_rules_dry_run.id
+  http_method   = "POST"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
 # GET /tags/{imageId} メソッド - 画像のタグ取得
 resource "aws_api_gateway_method" "tags_image_get" {
   rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
@@ -116,6 +251,15 @@ resource "aws_api_gateway_method" "tags_image_delete" {
   authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
 }
 
+# GET /tags/{imageId}/history メソッド - 画像のタグの変更履歴の取得
+resource "aws_api_gateway_method" "tags_image_history_get" {
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = aws_api_gateway_resource.tags_image_history.id
+  http_method   = "GET"
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
 # GET /tags との統合
 resource "aws_api_gateway_integration" "tags_get_integration" {
   rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
@@ -138,6 +282,94 @@ resource "aws_api_gateway_integration" "tags_post_integration" {
   uri                     = aws_lambda_function.cloudpix_tags.invoke_arn
 }
 
+# GET /tags/suggest との統合
+resource "aws_api_gateway_integration" "tags_suggest_get_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.tags_suggest.id
+  http_method = aws_api_gateway_method.tags_suggest_get.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_tags.invoke_arn
+}
+
+# POST /tags/rename との統合
+resource "aws_api_gateway_integration" "tags_rename_post_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.tags_rename.id
+  http_method = aws_api_gateway_method.tags_rename_post.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_tags.invoke_arn
+}
+
+# POST /tags/merge との統合
+resource "aws_api_gateway_integration" "tags_merge_post_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.tags_merge.id
+  http_method = aws_api_gateway_method.tags_merge_post.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_tags.invoke_arn
+}
+
+# GET /tags/aliases との統合
+resource "aws_api_gateway_integration" "tags_aliases_get_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.tags_aliases.id
+  http_method = aws_api_gateway_method.tags_aliases_get.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_tags.invoke_arn
+}
+
+# PUT /tags/aliases/{alias} との統合
+resource "aws_api_gateway_integration" "tags_alias_put_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.tags_alias.id
+  http_method = aws_api_gateway_method.tags_alias_put.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_tags.invoke_arn
+}
+
+# DELETE /tags/aliases/{alias} との統合
+resource "aws_api_gateway_integration" "tags_alias_delete_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.tags_alias.id
+  http_method = aws_api_gateway_method.tags_alias_delete.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_tags.invoke_arn
+}
+
+# GET /tags/history との統合
+resource "aws_api_gateway_integration" "tags_history_get_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.tags_history.id
+  http_method = aws_api_gateway_method.tags_history_get.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_tags.invoke_arn
+}
+
+# POST /tags/rules/dry-run との統合
+resource "aws_api_gateway_integration" "tags_rules_dry_run_post_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.tags_rules_dry_run.id
+  http_method = aws_api_gateway_method.tags_rules_dry_run_post.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_tags.invoke_arn
+}
+
 # GET /tags/{imageId} との統合
 resource "aws_api_gateway_integration" "tags_image_get_integration" {
   rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
@@ -160,6 +392,17 @@ resource "aws_api_gateway_integration" "tags_image_delete_integration" {
   uri                     = aws_lambda_function.cloudpix_tags.invoke_arn
 }
 
+# GET /tags/{imageId}/history との統合
+resource "aws_api_gateway_integration" "tags_image_history_get_integration" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = aws_api_gateway_resource.tags_image_history.id
+  http_method = aws_api_gateway_method.tags_image_history_get.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_tags.invoke_arn
+}
+
 # Lambda実行権限の付与
 resource "aws_lambda_permission" "tags_api_gateway" {
   statement_id  = "AllowExecutionFromAPIGatewayForTags"
@@ -93,6 +93,41 @@ resource "aws_s3_bucket_notification" "bucket_notification" {
     filter_suffix       = ".png"
   }
 
+  lambda_function {
+    lambda_function_arn = aws_lambda_function.cloudpix_thumbnail.arn
+    events              = ["s3:ObjectCreated:*"]
+    filter_prefix       = "uploads/"
+    filter_suffix       = ".gif"
+  }
+
+  lambda_function {
+    lambda_function_arn = aws_lambda_function.cloudpix_thumbnail.arn
+    events              = ["s3:ObjectCreated:*"]
+    filter_prefix       = "uploads/"
+    filter_suffix       = ".webp"
+  }
+
+  lambda_function {
+    lambda_function_arn = aws_lambda_function.cloudpix_thumbnail.arn
+    events              = ["s3:ObjectCreated:*"]
+    filter_prefix       = "uploads/"
+    filter_suffix       = ".bmp"
+  }
+
+  lambda_function {
+    lambda_function_arn = aws_lambda_function.cloudpix_thumbnail.arn
+    events              = ["s3:ObjectCreated:*"]
+    filter_prefix       = "uploads/"
+    filter_suffix       = ".tif"
+  }
+
+  lambda_function {
+    lambda_function_arn = aws_lambda_function.cloudpix_thumbnail.arn
+    events              = ["s3:ObjectCreated:*"]
+    filter_prefix       = "uploads/"
+    filter_suffix       = ".tiff"
+  }
+
   depends_on = [
     aws_lambda_permission.allow_bucket
   ]
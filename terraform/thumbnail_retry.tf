@@ -0,0 +1,88 @@
+################################
+# Thumbnail Retry Lambda + EventBridge (CloudWatch Events)
+################################
+
+# サムネイル再試行用のECRリポジトリ
+resource "aws_ecr_repository" "cloudpix_thumbnail_retry" {
+  name                 = "${var.app_name}-thumbnail-retry"
+  image_tag_mutability = "MUTABLE"
+  force_delete         = true
+
+  image_scanning_configuration {
+    scan_on_push = true
+  }
+}
+
+################################
+# Docker Build & Push - Thumbnail Retry
+################################
+# サムネイル再試行関数のイメージのビルドとプッシュ
+resource "null_resource" "docker_build_push_thumbnail_retry" {
+  depends_on = [aws_ecr_repository.cloudpix_thumbnail_retry]
+
+  triggers = {
+    ecr_repository_url = aws_ecr_repository.cloudpix_thumbnail_retry.repository_url
+    dockerfile_hash    = filemd5("${path.module}/../Dockerfile")
+    main_go_hash       = filemd5("${path.module}/../cmd/thumbnailretry/main.go")
+    build_script_hash  = filemd5("${path.module}/../build_and_push.sh")
+  }
+
+  provisioner "local-exec" {
+    command = <<-EOT
+      echo "Building thumbnail retry function image..."
+      cd ${path.module}/.. && \
+      chmod +x build_and_push.sh && \
+      REPO_NAME="cloudpix-thumbnail-retry" ./build_and_push.sh ${aws_ecr_repository.cloudpix_thumbnail_retry.repository_url} ./cmd/thumbnailretry/main.go
+    EOT
+  }
+}
+
+################################
+# Thumbnail Retry Lambda Function
+################################
+# 失敗したサムネイル生成を再試行するLambda関数
+resource "aws_lambda_function" "cloudpix_thumbnail_retry" {
+  function_name = "${var.app_name}-thumbnail-retry"
+  role          = aws_iam_role.lambda_role.arn
+  package_type  = "Image"
+  image_uri     = "${aws_ecr_repository.cloudpix_thumbnail_retry.repository_url}:latest"
+
+  timeout     = 300 # 複数の画像を再処理するため長めに設定（5分）
+  memory_size = var.lambda_memory_size
+
+  environment {
+    variables = local.thumbnail_retry_lambda_env_vars
+  }
+
+  depends_on = [
+    null_resource.docker_build_push_thumbnail_retry
+  ]
+
+  # X-Rayトレースを有効化
+  tracing_config {
+    mode = "Active"
+  }
+}
+
+# EventBridgeルール（定期的に再試行時刻を過ぎた画像を再処理）
+resource "aws_cloudwatch_event_rule" "thumbnail_retry" {
+  name                = "${var.app_name}-thumbnail-retry"
+  description         = "失敗したサムネイル生成を再試行"
+  schedule_expression = var.thumbnail_retry_schedule
+}
+
+# EventBridgeターゲット（Lambda関数を呼び出す）
+resource "aws_cloudwatch_event_target" "thumbnail_retry_lambda" {
+  rule      = aws_cloudwatch_event_rule.thumbnail_retry.name
+  target_id = "TriggerThumbnailRetryLambda"
+  arn       = aws_lambda_function.cloudpix_thumbnail_retry.arn
+}
+
+# Lambda実行権限
+resource "aws_lambda_permission" "allow_eventbridge_thumbnail_retry" {
+  statement_id  = "AllowExecutionFromEventBridge"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.cloudpix_thumbnail_retry.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.thumbnail_retry.arn
+}
@@ -104,4 +104,58 @@ variable "image_retention_days" {
   description = "画像の保持日数"
   type        = number
   default     = 10
-}
\ No newline at end of file
+}
+
+variable "thumbnail_renditions" {
+  description = "サムネイルのレンディション定義（name:WIDTHxHEIGHT[:format[:quality[:crop[:background]]]] のカンマ区切り、crop は fit/fill/smart/pad）"
+  type        = string
+  default     = "small:150x150,medium:600x600,large:1600x1600"
+}
+
+variable "thumbnail_animated_gif" {
+  description = "アニメーションGIFのサムネイルでアニメーションを維持するかどうか（falseの場合は先頭フレームの静止画）"
+  type        = bool
+  default     = false
+}
+
+variable "thumbnail_max_attempts" {
+  description = "サムネイル生成をデッドレターにするまでの最大試行回数"
+  type        = number
+  default     = 5
+}
+
+variable "thumbnail_retry_schedule" {
+  description = "失敗したサムネイル生成を再試行するスケジュール"
+  type        = string
+  default     = "rate(5 minutes)"
+}
+
+variable "max_image_pixels" {
+  description = "デコードを許可する画像の画素数の上限（超える画像はデコードせずに拒否）"
+  type        = number
+  default     = 50000000
+}
+
+variable "max_image_bytes" {
+  description = "デコードを許可する画像のバイト数の上限"
+  type        = number
+  default     = 52428800
+}
+
+variable "render_presets" {
+  description = "オンデマンド画像変換で許可するプリセット（name=WIDTHxHEIGHT[:fit[:format[:quality]]] のカンマ区切り）"
+  type        = string
+  default     = "square=300x300:cover,card=800x600:contain,og=1200x630:cover:jpeg"
+}
+
+variable "tag_namespaces" {
+  description = "「名前空間:値」形式のタグで付与を許可する名前空間（カンマ区切り、空の場合は名前空間つきのタグを許可しない）"
+  type        = string
+  default     = "place,person"
+}
+
+variable "auto_tag_rules" {
+  description = "アップロード後とサムネイル生成後に画像へタグを付ける自動タグ付けのルール（JSON配列、空の場合は自動タグ付けなし）"
+  type        = string
+  default     = ""
+}
@@ -0,0 +1,126 @@
+################################
+# ECR Repository for Watermark
+################################
+# 透かし設定用のECRリポジトリ
+resource "aws_ecr_repository" "cloudpix_watermark" {
+  name                 = "${var.app_name}-watermark"
+  image_tag_mutability = "MUTABLE"
+  force_delete         = true
+
+  image_scanning_configuration {
+    scan_on_push = true
+  }
+}
+
+################################
+# Docker Build & Push - Watermark
+################################
+# 透かし設定関数のイメージのビルドとプッシュ
+resource "null_resource" "docker_build_push_watermark" {
+  depends_on = [aws_ecr_repository.cloudpix_watermark]
+
+  triggers = {
+    ecr_repository_url = aws_ecr_repository.cloudpix_watermark.repository_url
+    dockerfile_hash    = filemd5("${path.module}/../Dockerfile")
+    main_go_hash       = filemd5("${path.module}/../cmd/watermark/main.go")
+    build_script_hash  = filemd5("${path.module}/../build_and_push.sh")
+  }
+
+  provisioner "local-exec" {
+    command = <<-EOT
+      echo "Building watermark function image..."
+      cd ${path.module}/.. && \
+      chmod +x build_and_push.sh && \
+      REPO_NAME="cloudpix-watermark" ./build_and_push.sh ${aws_ecr_repository.cloudpix_watermark.repository_url} ./cmd/watermark/main.go
+    EOT
+  }
+}
+
+################################
+# Watermark Lambda Function
+################################
+# 透かし設定用Lambda関数
+resource "aws_lambda_function" "cloudpix_watermark" {
+  function_name = "${var.app_name}-watermark"
+  role          = aws_iam_role.lambda_role.arn
+  package_type  = "Image"
+  image_uri     = "${aws_ecr_repository.cloudpix_watermark.repository_url}:latest"
+
+  timeout     = var.lambda_timeout
+  memory_size = var.lambda_memory_size
+
+  environment {
+    variables = local.watermark_lambda_env_vars
+  }
+
+  depends_on = [
+    null_resource.docker_build_push_watermark
+  ]
+
+  # X-Rayトレースを有効化
+  tracing_config {
+    mode = "Active"
+  }
+}
+
+################################
+# API Gateway - Watermark Endpoints
+################################
+# /watermark リソースの作成
+resource "aws_api_gateway_resource" "watermark" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_rest_api.cloudpix_api.root_resource_id
+  path_part   = "watermark"
+}
+
+# /watermark/default リソースの作成
+resource "aws_api_gateway_resource" "watermark_default" {
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  parent_id   = aws_api_gateway_resource.watermark.id
+  path_part   = "default"
+}
+
+# /watermark と /watermark/default の各メソッド
+# GET - 設定の取得、PUT - 設定の登録、DELETE - 設定の削除（default の変更は管理者のみ）
+locals {
+  watermark_methods = {
+    watermark_get            = { resource_id = aws_api_gateway_resource.watermark.id, http_method = "GET" }
+    watermark_put            = { resource_id = aws_api_gateway_resource.watermark.id, http_method = "PUT" }
+    watermark_delete         = { resource_id = aws_api_gateway_resource.watermark.id, http_method = "DELETE" }
+    watermark_default_get    = { resource_id = aws_api_gateway_resource.watermark_default.id, http_method = "GET" }
+    watermark_default_put    = { resource_id = aws_api_gateway_resource.watermark_default.id, http_method = "PUT" }
+    watermark_default_delete = { resource_id = aws_api_gateway_resource.watermark_default.id, http_method = "DELETE" }
+  }
+}
+
+resource "aws_api_gateway_method" "watermark" {
+  for_each = local.watermark_methods
+
+  rest_api_id   = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id   = each.value.resource_id
+  http_method   = each.value.http_method
+  authorization = "COGNITO_USER_POOLS"
+  authorizer_id = aws_api_gateway_authorizer.cloudpix_cognito_authorizer.id
+}
+
+resource "aws_api_gateway_integration" "watermark_integration" {
+  for_each = local.watermark_methods
+
+  rest_api_id = aws_api_gateway_rest_api.cloudpix_api.id
+  resource_id = each.value.resource_id
+  http_method = aws_api_gateway_method.watermark[each.key].http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.cloudpix_watermark.invoke_arn
+}
+
+# Lambda実行権限の付与
+resource "aws_lambda_permission" "watermark_api_gateway" {
+  statement_id  = "AllowExecutionFromAPIGatewayForWatermark"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.cloudpix_watermark.function_name
+  principal     = "apigateway.amazonaws.com"
+
+  source_arn = "${aws_api_gateway_rest_api.cloudpix_api.execution_arn}/*/*"
+}